@@ -24,6 +24,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 )
 
 import (
@@ -38,8 +39,12 @@ import (
 	"github.com/arana-db/arana/pkg/constants"
 	"github.com/arana-db/arana/pkg/executor"
 	filter "github.com/arana-db/arana/pkg/filters"
+	_ "github.com/arana-db/arana/pkg/filters/audit"
+	_ "github.com/arana-db/arana/pkg/filters/firewall"
 	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/server"
+	"github.com/arana-db/arana/pkg/tracing"
 	"github.com/arana-db/arana/pkg/util/log"
 )
 
@@ -85,6 +90,10 @@ func Run(bootstrapConfigPath string) {
 		return
 	}
 
+	var (
+		preFilters  []proto.PreFilter
+		postFilters []proto.PostFilter
+	)
 	for _, filterConf := range filters {
 		factory := filter.GetFilterFactory(filterConf.Name)
 		if factory == nil {
@@ -95,6 +104,13 @@ func Run(bootstrapConfigPath string) {
 			panic(errors.WithMessagef(err, "failed to create filter: %s", filterConf.Name))
 		}
 		filter.RegisterFilter(f.GetName(), f)
+
+		if pre, ok := f.(proto.PreFilter); ok {
+			preFilters = append(preFilters, pre)
+		}
+		if post, ok := f.(proto.PostFilter); ok {
+			postFilters = append(postFilters, post)
+		}
 	}
 
 	propeller := server.NewServer()
@@ -111,7 +127,14 @@ func Run(bootstrapConfigPath string) {
 			log.Fatalf("create listener failed: %v", err)
 			return
 		}
-		listener.SetExecutor(executor.NewRedirectExecutor())
+		redirect := executor.NewRedirectExecutor()
+		for _, it := range preFilters {
+			redirect.AddPreFilter(it)
+		}
+		for _, it := range postFilters {
+			redirect.AddPostFilter(it)
+		}
+		listener.SetExecutor(redirect)
 		propeller.AddListener(listener)
 	}
 	propeller.Start()
@@ -127,6 +150,14 @@ func Run(bootstrapConfigPath string) {
 	}()
 	select {
 	case <-ctx.Done():
+		// drain the frontend connections, wait for their transactions to finish
+		propeller.Close()
+
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		if err := tracing.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("failed to shutdown tracing: %v", err)
+		}
 		return
 	}
 }
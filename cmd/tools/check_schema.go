@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+import (
+	"github.com/spf13/cobra"
+)
+
+import (
+	"github.com/arana-db/arana/cmd/cmds"
+	"github.com/arana-db/arana/pkg/boot"
+	"github.com/arana-db/arana/pkg/constants"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+var (
+	checkBootConfPath string
+	checkTables       []string
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:     "check-schema",
+		Short:   "check whether the physical tables of sharding tables are consistent",
+		Example: "./arana check-schema -c ../docker/conf/bootstrap.yaml -t employees.student",
+		Run:     RunCheckSchema,
+	}
+
+	cmd.PersistentFlags().
+		StringVarP(&checkBootConfPath, constants.ConfigPathKey, "c", os.Getenv(constants.EnvBootstrapPath), "bootstrap configuration file path")
+	cmd.PersistentFlags().
+		StringSliceVarP(&checkTables, "tables", "t", nil, "the sharding tables to be checked, format: db.table, all tables will be checked if empty")
+
+	cmds.Handle(func(root *cobra.Command) {
+		root.AddCommand(cmd)
+	})
+}
+
+func RunCheckSchema(cmd *cobra.Command, args []string) {
+	_, _ = cmd, args
+
+	ctx := context.Background()
+
+	provider := boot.NewProvider(checkBootConfPath)
+	if err := boot.Boot(ctx, provider); err != nil {
+		log.Fatal("boot failed: %+v", err)
+		return
+	}
+
+	clusters, err := provider.ListClusters(ctx)
+	if err != nil {
+		log.Fatal("list clusters failed: %+v", err)
+		return
+	}
+
+	filter := make(map[string]struct{}, len(checkTables))
+	for _, it := range checkTables {
+		filter[it] = struct{}{}
+	}
+
+	var drifted bool
+	for _, cluster := range clusters {
+		var tables []string
+		if tables, err = provider.ListTables(ctx, cluster); err != nil {
+			log.Fatal("list tables of %s failed: %+v", cluster, err)
+			return
+		}
+
+		for _, table := range tables {
+			name := fmt.Sprintf("%s.%s", cluster, table)
+			if _, ok := filter[name]; len(filter) > 0 && !ok {
+				continue
+			}
+
+			drifts, err := proto.LoadSchemaChecker().CheckTable(ctx, cluster, table)
+			if err != nil {
+				log.Fatal("check table %s failed: %+v", name, err)
+				return
+			}
+
+			if len(drifts) == 0 {
+				fmt.Printf("%s: OK\n", name)
+				continue
+			}
+
+			drifted = true
+			var sb strings.Builder
+			for _, it := range drifts {
+				sb.WriteString("\n  - ")
+				sb.WriteString(it.String())
+			}
+			fmt.Printf("%s: %d drifts found%s\n", name, len(drifts), sb.String())
+		}
+	}
+
+	if drifted {
+		os.Exit(1)
+	}
+}
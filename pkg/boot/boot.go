@@ -27,12 +27,15 @@ import (
 
 import (
 	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/limiter"
+	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/rule"
 	"github.com/arana-db/arana/pkg/runtime"
 	rcontext "github.com/arana-db/arana/pkg/runtime/context"
 	"github.com/arana-db/arana/pkg/runtime/namespace"
 	_ "github.com/arana-db/arana/pkg/schema"
 	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/pkg/selector"
 	"github.com/arana-db/arana/pkg/util/log"
 )
 
@@ -70,6 +73,8 @@ func Boot(ctx context.Context, provider Discovery) error {
 		security.DefaultTenantManager().PutCluster(c.Tenant, cluster)
 	}
 
+	proto.RegisterRuleManager(&ruleManager{provider: provider})
+
 	var tenants []string
 	if tenants, err = provider.ListTenants(ctx); err != nil {
 		return errors.Wrap(err, "no tenants found")
@@ -84,6 +89,13 @@ func Boot(ctx context.Context, provider Discovery) error {
 		for _, it := range t.Users {
 			security.DefaultTenantManager().PutUser(tenant, it)
 		}
+		security.DefaultTenantManager().SetAllowDestructiveDDL(tenant, t.AllowDestructiveDDL)
+		security.DefaultTenantManager().SetConsistency(tenant, t.Consistency)
+		limiter.Default().SetLimits(tenant, t.Limits)
+	}
+
+	if err = watchConfiguration(ctx, provider); err != nil {
+		return errors.Wrap(err, "failed to watch configuration")
 	}
 
 	return nil
@@ -110,6 +122,14 @@ func buildNamespace(ctx context.Context, provider Discovery, clusterName string)
 	}
 
 	var initCmds []namespace.Command
+	if cluster != nil {
+		strategy, err := selector.ParseStrategy(cluster.LoadBalance)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster %s", clusterName)
+		}
+		initCmds = append(initCmds, namespace.UpdateLoadBalance(strategy))
+	}
+
 	for _, group := range groups {
 		var nodes []string
 		if nodes, err = provider.ListNodes(ctx, clusterName, group); err != nil {
@@ -128,7 +148,22 @@ func buildNamespace(ctx context.Context, provider Discovery, clusterName string)
 		}
 	}
 
-	var tables []string
+	ru, err := buildRule(ctx, provider, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	initCmds = append(initCmds, namespace.UpdateRule(ru))
+
+	return namespace.New(clusterName, initCmds...)
+}
+
+// buildRule builds the rule of logical tables and views from the provider.
+func buildRule(ctx context.Context, provider Discovery, clusterName string) (*rule.Rule, error) {
+	var (
+		tables []string
+		err    error
+	)
 	if tables, err = provider.ListTables(ctx, clusterName); err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -145,7 +180,22 @@ func buildNamespace(ctx context.Context, provider Discovery, clusterName string)
 		}
 		ru.SetVTable(table, vt)
 	}
-	initCmds = append(initCmds, namespace.UpdateRule(&ru))
 
-	return namespace.New(clusterName, initCmds...)
+	var views []string
+	if views, err = provider.ListViews(ctx, clusterName); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, it := range views {
+		var view *config.View
+		if view, err = provider.GetView(ctx, clusterName, it); err != nil {
+			return nil, err
+		}
+		if view == nil {
+			log.Warnf("no such view %s", it)
+			continue
+		}
+		ru.SetView(it, view.Definition)
+	}
+
+	return &ru, nil
 }
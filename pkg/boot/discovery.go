@@ -36,9 +36,14 @@ import (
 )
 
 import (
+	"github.com/arana-db/arana/pkg/breaker"
 	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/health"
+	"github.com/arana-db/arana/pkg/metrics"
 	"github.com/arana-db/arana/pkg/proto/rule"
 	rrule "github.com/arana-db/arana/pkg/runtime/rule"
+	"github.com/arana-db/arana/pkg/slowlog"
+	"github.com/arana-db/arana/pkg/tracing"
 	"github.com/arana-db/arana/pkg/util/file"
 	"github.com/arana-db/arana/pkg/util/log"
 )
@@ -106,6 +111,11 @@ type Discovery interface {
 	// GetTable returns the table info.
 	GetTable(ctx context.Context, cluster, table string) (*rule.VTable, error)
 
+	// ListViews lists the logical view names.
+	ListViews(ctx context.Context, cluster string) ([]string, error)
+	// GetView returns the logical view info.
+	GetView(ctx context.Context, cluster, view string) (*config.View, error)
+
 	// GetConfigCenter
 	GetConfigCenter() *config.Center
 }
@@ -125,6 +135,26 @@ func (fp *discovery) Init(ctx context.Context) error {
 		return err
 	}
 
+	if fp.options.SlowLog != nil {
+		slowlog.Init(fp.options.SlowLog)
+	}
+
+	metrics.Init(fp.options.Metrics)
+
+	if fp.options.Trace != nil {
+		if err := tracing.Init(fp.options.Trace); err != nil {
+			return err
+		}
+	}
+
+	if fp.options.HealthCheck != nil {
+		health.Start(fp.options.HealthCheck)
+	}
+
+	if fp.options.CircuitBreaker != nil {
+		breaker.Init(fp.options.CircuitBreaker)
+	}
+
 	return nil
 }
 
@@ -285,6 +315,28 @@ func (fp *discovery) ListTables(ctx context.Context, cluster string) ([]string,
 	return tables, nil
 }
 
+func (fp *discovery) ListViews(ctx context.Context, cluster string) ([]string, error) {
+	cfg, err := fp.c.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var views []string
+	for name := range fp.loadViews(cfg, cluster) {
+		views = append(views, name)
+	}
+	sort.Strings(views)
+	return views, nil
+}
+
+func (fp *discovery) GetView(ctx context.Context, cluster, view string) (*config.View, error) {
+	cfg, err := fp.c.Load()
+	if err != nil {
+		return nil, err
+	}
+	return fp.loadViews(cfg, cluster)[view], nil
+}
+
 func (fp *discovery) GetNode(ctx context.Context, cluster, group, node string) (*config.Node, error) {
 	bingo, ok := fp.loadGroup(cluster, group)
 	if !ok {
@@ -498,6 +550,25 @@ func (fp *discovery) loadTables(cfg *config.Configuration, cluster string) map[s
 	return tables
 }
 
+func (fp *discovery) loadViews(cfg *config.Configuration, cluster string) map[string]*config.View {
+	var views map[string]*config.View
+	for _, it := range cfg.Data.Views {
+		db, name, err := parseTable(it.Name)
+		if err != nil {
+			log.Warnf("skip parsing view: %v", err)
+			continue
+		}
+		if db != cluster {
+			continue
+		}
+		if views == nil {
+			views = make(map[string]*config.View)
+		}
+		views[name] = it
+	}
+	return views
+}
+
 var (
 	_regexpTopology     *regexp.Regexp
 	_regexpTopologyOnce sync.Once
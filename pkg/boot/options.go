@@ -18,9 +18,24 @@
 package boot
 
 import (
+	"github.com/arana-db/arana/pkg/breaker"
 	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/health"
+	"github.com/arana-db/arana/pkg/metrics"
+	"github.com/arana-db/arana/pkg/slowlog"
+	"github.com/arana-db/arana/pkg/tracing"
 )
 
 type BootOptions struct {
 	Config *config.ConfigOptions `yaml:"config"`
+	// SlowLog enables the slow log if specified.
+	SlowLog *slowlog.Config `yaml:"slow_log,omitempty"`
+	// Metrics serves the prometheus metrics through http if specified.
+	Metrics *metrics.Config `yaml:"metrics,omitempty"`
+	// Trace exports the traces to OTLP collector if specified.
+	Trace *tracing.Config `yaml:"trace,omitempty"`
+	// HealthCheck enables the health checking of backend nodes if specified.
+	HealthCheck *health.Config `yaml:"health_check,omitempty"`
+	// CircuitBreaker enables the circuit breakers of backend nodes if specified.
+	CircuitBreaker *breaker.Config `yaml:"circuit_breaker,omitempty"`
 }
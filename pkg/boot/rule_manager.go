@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+var _ proto.RuleManager = (*ruleManager)(nil)
+
+// ruleManager updates the sharding rules in config center, then reloads the rule of namespace.
+type ruleManager struct {
+	provider Discovery
+}
+
+func (rm *ruleManager) RenameTable(ctx context.Context, schema, table, newTable string) error {
+	err := rm.provider.GetConfigCenter().Update(ctx, func(cfg *config.Configuration) error {
+		var bingo *config.Table
+		for _, it := range rm.tables(cfg) {
+			db, tb, err := parseTable(it.Name)
+			if err != nil || db != schema {
+				continue
+			}
+			switch tb {
+			case table:
+				bingo = it
+			case newTable:
+				return errors.Errorf("cannot rename table %s.%s: table %s exists already", schema, table, newTable)
+			}
+		}
+
+		if bingo == nil {
+			return errors.Errorf("cannot rename table %s.%s: no such table", schema, table)
+		}
+
+		bingo.Name = fmt.Sprintf("%s.%s", schema, newTable)
+		for _, it := range []*config.Topology{bingo.Topology, bingo.ShadowTopology} {
+			if it != nil {
+				it.TblPattern = rule.RenameTable(it.TblPattern, table, newTable)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = rm.reload(ctx, schema, table, newTable); err != nil {
+		return err
+	}
+
+	log.Infof("[%s] rename table rule %s to %s successfully", schema, table, newTable)
+
+	return nil
+}
+
+func (rm *ruleManager) RenameColumn(ctx context.Context, schema, table, column, newColumn string) error {
+	err := rm.provider.GetConfigCenter().Update(ctx, func(cfg *config.Configuration) error {
+		var bingo *config.Table
+		for _, it := range rm.tables(cfg) {
+			if db, tb, err := parseTable(it.Name); err == nil && db == schema && tb == table {
+				bingo = it
+				break
+			}
+		}
+
+		if bingo == nil {
+			return errors.Errorf("cannot rename column of table %s.%s: no such table", schema, table)
+		}
+
+		var (
+			oldRef = fmt.Sprintf("#%s#", column)
+			newRef = fmt.Sprintf("#%s#", newColumn)
+		)
+		for _, rules := range [][]*config.Rule{bingo.DbRules, bingo.TblRules} {
+			for _, it := range rules {
+				if !strings.EqualFold(it.Column, column) {
+					continue
+				}
+				it.Column = newColumn
+				it.Expr = strings.ReplaceAll(it.Expr, oldRef, newRef)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = rm.reload(ctx, schema, table, table); err != nil {
+		return err
+	}
+
+	log.Infof("[%s] rename sharding column %s.%s to %s successfully", schema, table, column, newColumn)
+
+	return nil
+}
+
+func (rm *ruleManager) CreateView(ctx context.Context, schema, view, definition string, orReplace bool) error {
+	ns := namespace.Load(schema)
+	if ns == nil {
+		return errors.Errorf("no such logical database %s", schema)
+	}
+	if ns.Rule().Has(view) {
+		return errors.Errorf("cannot create view %s.%s: table %s exists already", schema, view, view)
+	}
+
+	name := fmt.Sprintf("%s.%s", schema, view)
+	err := rm.provider.GetConfigCenter().Update(ctx, func(cfg *config.Configuration) error {
+		if cfg.Data == nil {
+			cfg.Data = &config.Data{}
+		}
+		for _, it := range cfg.Data.Views {
+			if it.Name != name {
+				continue
+			}
+			if !orReplace {
+				return errors.Errorf("cannot create view %s: view exists already", name)
+			}
+			it.Definition = definition
+			return nil
+		}
+		cfg.Data.Views = append(cfg.Data.Views, &config.View{
+			Name:       name,
+			Definition: definition,
+		})
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ns.Rule().SetView(view, definition)
+
+	log.Infof("[%s] create view %s successfully: %s", schema, view, definition)
+
+	return nil
+}
+
+func (rm *ruleManager) DropView(ctx context.Context, schema, view string, ifExists bool) error {
+	ns := namespace.Load(schema)
+	if ns == nil {
+		return errors.Errorf("no such logical database %s", schema)
+	}
+
+	name := fmt.Sprintf("%s.%s", schema, view)
+	err := rm.provider.GetConfigCenter().Update(ctx, func(cfg *config.Configuration) error {
+		if cfg.Data != nil {
+			for i, it := range cfg.Data.Views {
+				if it.Name == name {
+					cfg.Data.Views = append(cfg.Data.Views[:i], cfg.Data.Views[i+1:]...)
+					return nil
+				}
+			}
+		}
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("cannot drop view %s: no such view", name)
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ns.Rule().RemoveView(view)
+
+	log.Infof("[%s] drop view %s successfully", schema, view)
+
+	return nil
+}
+
+func (rm *ruleManager) tables(cfg *config.Configuration) []*config.Table {
+	if cfg.Data == nil || cfg.Data.ShardingRule == nil {
+		return nil
+	}
+	return cfg.Data.ShardingRule.Tables
+}
+
+// reload rebuilds the VTable from config center, then replaces the rule of namespace.
+func (rm *ruleManager) reload(ctx context.Context, schema, table, newTable string) error {
+	ns := namespace.Load(schema)
+	if ns == nil {
+		return errors.Errorf("no such logical database %s", schema)
+	}
+
+	vt, err := rm.provider.GetTable(ctx, schema, newTable)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if vt == nil {
+		return errors.Errorf("no such table %s.%s", schema, newTable)
+	}
+
+	var ru rule.Rule
+	ns.Rule().Range(func(name string, it *rule.VTable) bool {
+		if name != table {
+			ru.SetVTable(name, it)
+		}
+		return true
+	})
+	ru.SetVTable(newTable, vt)
+	ns.Rule().RangeViews(func(view, definition string) bool {
+		ru.SetView(view, definition)
+		return true
+	})
+
+	// apply the new rule immediately, the following statements should use the new rule.
+	return namespace.UpdateRule(&ru)(ns)
+}
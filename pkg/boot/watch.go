@@ -0,0 +1,310 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/limiter"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/pkg/selector"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+type nodeKey struct {
+	group, node string
+}
+
+// watchConfiguration subscribes the changes of configuration, then applies them to the live namespaces and tenants.
+func watchConfiguration(ctx context.Context, provider Discovery) error {
+	center := provider.GetConfigCenter()
+	if center == nil {
+		return nil
+	}
+	return center.Subscribe(ctx, func(prev, next *config.Configuration) {
+		applyConfiguration(ctx, provider, prev, next)
+	})
+}
+
+// applyConfiguration translates the differences between two configurations into commands of namespaces and updates of tenants.
+// The provider should return the next configuration already.
+func applyConfiguration(ctx context.Context, provider Discovery, prev, next *config.Configuration) {
+	applyClusters(ctx, provider, prev, next)
+	applyTenants(prev, next)
+}
+
+func applyClusters(ctx context.Context, provider Discovery, prev, next *config.Configuration) {
+	var (
+		tm           = security.DefaultTenantManager()
+		prevClusters = clustersOf(prev)
+		nextClusters = clustersOf(next)
+	)
+
+	for name, it := range prevClusters {
+		if _, ok := nextClusters[name]; ok {
+			continue
+		}
+		tm.RemoveCluster(it.Tenant, name)
+		if err := namespace.Unregister(name); err != nil {
+			log.Errorf("unregister namespace %s failed: %v", name, err)
+			continue
+		}
+		log.Infof("unregister namespace %s successfully", name)
+	}
+
+	for name, it := range nextClusters {
+		before, ok := prevClusters[name]
+		ns := namespace.Load(name)
+
+		if !ok || ns == nil {
+			ns, err := buildNamespace(rcontext.WithTenant(ctx, it.Tenant), provider, name)
+			if err != nil {
+				log.Errorf("build namespace %s failed: %v", name, err)
+				continue
+			}
+			if err = namespace.Register(ns); err != nil {
+				log.Errorf("register namespace %s failed: %v", name, err)
+				continue
+			}
+			tm.PutCluster(it.Tenant, name)
+			log.Infof("register namespace %s successfully", name)
+			continue
+		}
+
+		if before.Tenant != it.Tenant {
+			tm.RemoveCluster(before.Tenant, name)
+			tm.PutCluster(it.Tenant, name)
+		}
+
+		changes, err := diffCluster(ctx, provider, prev, next, before, it)
+		if err != nil {
+			log.Errorf("failed to apply changes of namespace %s: %v", name, err)
+			continue
+		}
+		if changes.IsEmpty() {
+			continue
+		}
+
+		// apply all changes of the namespace at once, the existing DBs are closed after in-flight requests finished.
+		if err = ns.EnqueueCommand(namespace.Apply(changes)); err != nil {
+			changes.Close()
+			log.Errorf("failed to apply changes of namespace %s: %v", name, err)
+			continue
+		}
+		log.Infof("apply changes of namespace %s", name)
+	}
+}
+
+// diffCluster computes the changes which turns the namespace from the previous cluster into the next one,
+// the new DBs will be closed if any error occurs.
+func diffCluster(ctx context.Context, provider Discovery, prev, next *config.Configuration, before, after *config.DataSourceCluster) (ret *namespace.Changes, err error) {
+	var (
+		prevNodes = nodesOf(before)
+		nextNodes = nodesOf(after)
+		changes   = &namespace.Changes{
+			Upserts: make(map[string][]proto.DB),
+			Removes: make(map[string][]string),
+			Weights: make(map[string]map[string]proto.Weight),
+		}
+	)
+
+	defer func() {
+		if err != nil {
+			changes.Close()
+		}
+	}()
+
+	for k, node := range nextNodes {
+		exist, ok := prevNodes[k]
+		switch {
+		case !ok || !isSameNode(exist, node):
+			db := runtime.NewAtomDB(node)
+			if db == nil {
+				return nil, errors.Errorf("invalid node %s.%s", k.group, k.node)
+			}
+			changes.Upserts[k.group] = append(changes.Upserts[k.group], db)
+		case exist.Weight != node.Weight:
+			r, w, err := node.GetReadAndWriteWeight()
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid weight of node %s.%s", k.group, k.node)
+			}
+			if _, ok := changes.Weights[k.group]; !ok {
+				changes.Weights[k.group] = make(map[string]proto.Weight)
+			}
+			changes.Weights[k.group][k.node] = proto.Weight{R: int32(r), W: int32(w)}
+		}
+	}
+
+	for k := range prevNodes {
+		if _, ok := nextNodes[k]; !ok {
+			changes.Removes[k.group] = append(changes.Removes[k.group], k.node)
+		}
+	}
+
+	if before.LoadBalance != after.LoadBalance {
+		strategy, err := selector.ParseStrategy(after.LoadBalance)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster %s", after.Name)
+		}
+		changes.LoadBalance = strategy
+	}
+	if ruleSign(prev, after.Name) != ruleSign(next, after.Name) {
+		ru, err := buildRule(ctx, provider, after.Name)
+		if err != nil {
+			return nil, err
+		}
+		changes.Rule = ru
+	}
+
+	return changes, nil
+}
+
+func applyTenants(prev, next *config.Configuration) {
+	var (
+		tm          = security.DefaultTenantManager()
+		prevTenants = tenantsOf(prev)
+		nextTenants = tenantsOf(next)
+	)
+
+	for name, before := range prevTenants {
+		after := nextTenants[name]
+		for _, it := range before.Users {
+			if findUser(after, it.Username) == nil {
+				tm.RemoveUser(name, it.Username)
+				log.Infof("remove user %s of tenant %s", it.Username, name)
+			}
+		}
+		if after == nil {
+			tm.SetAllowDestructiveDDL(name, false)
+			tm.SetConsistency(name, nil)
+			limiter.Default().SetLimits(name, nil)
+		}
+	}
+
+	for name, after := range nextTenants {
+		before := prevTenants[name]
+		for _, it := range after.Users {
+			if exist := findUser(before, it.Username); exist == nil || *exist != *it {
+				tm.PutUser(name, it)
+				log.Infof("put user %s of tenant %s", it.Username, name)
+			}
+		}
+		if before == nil || before.AllowDestructiveDDL != after.AllowDestructiveDDL {
+			tm.SetAllowDestructiveDDL(name, after.AllowDestructiveDDL)
+		}
+		if before == nil || !reflect.DeepEqual(before.Consistency, after.Consistency) {
+			tm.SetConsistency(name, after.Consistency)
+		}
+		if before == nil || !reflect.DeepEqual(before.Limits, after.Limits) {
+			limiter.Default().SetLimits(name, after.Limits)
+		}
+	}
+}
+
+func clustersOf(cfg *config.Configuration) map[string]*config.DataSourceCluster {
+	ret := make(map[string]*config.DataSourceCluster)
+	if cfg == nil || cfg.Data == nil {
+		return ret
+	}
+	for _, it := range cfg.Data.DataSourceClusters {
+		ret[it.Name] = it
+	}
+	return ret
+}
+
+func tenantsOf(cfg *config.Configuration) map[string]*config.Tenant {
+	ret := make(map[string]*config.Tenant)
+	if cfg == nil || cfg.Data == nil {
+		return ret
+	}
+	for _, it := range cfg.Data.Tenants {
+		ret[it.Name] = it
+	}
+	return ret
+}
+
+// nodesOf returns the copies of nodes in cluster, the parameters of cluster are merged.
+func nodesOf(cluster *config.DataSourceCluster) map[nodeKey]*config.Node {
+	ret := make(map[nodeKey]*config.Node)
+	for _, group := range cluster.Groups {
+		for _, it := range group.Nodes {
+			node := *it
+			node.Parameters = config.ParametersMap{}
+			node.Parameters.Merge(it.Parameters)
+			node.Parameters.Merge(cluster.Parameters)
+			ret[nodeKey{group: group.Name, node: it.Name}] = &node
+		}
+	}
+	return ret
+}
+
+// isSameNode returns true if the two nodes are same except the weight.
+func isSameNode(a, b *config.Node) bool {
+	x, y := *a, *b
+	x.Weight, y.Weight = "", ""
+	return reflect.DeepEqual(x, y)
+}
+
+// ruleSign returns the sign of sharding tables and views of the cluster.
+func ruleSign(cfg *config.Configuration, cluster string) string {
+	var sign struct {
+		Tables []*config.Table `json:"tables,omitempty"`
+		Views  []*config.View  `json:"views,omitempty"`
+	}
+	if cfg != nil && cfg.Data != nil {
+		if cfg.Data.ShardingRule != nil {
+			for _, it := range cfg.Data.ShardingRule.Tables {
+				if db, _, err := parseTable(it.Name); err == nil && db == cluster {
+					sign.Tables = append(sign.Tables, it)
+				}
+			}
+		}
+		for _, it := range cfg.Data.Views {
+			if db, _, err := parseTable(it.Name); err == nil && db == cluster {
+				sign.Views = append(sign.Views, it)
+			}
+		}
+	}
+	b, _ := json.Marshal(sign)
+	return string(b)
+}
+
+func findUser(tenant *config.Tenant, username string) *config.User {
+	if tenant == nil {
+		return nil
+	}
+	for _, it := range tenant.Users {
+		if it.Username == username {
+			return it
+		}
+	}
+	return nil
+}
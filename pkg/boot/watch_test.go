@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/limiter"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/testdata"
+)
+
+func TestApplyConfiguration(t *testing.T) {
+	_ = namespace.Unregister("employee")
+	defer func() {
+		_ = namespace.Unregister("employee")
+		_ = namespace.Unregister("employee2")
+	}()
+
+	ctx := context.Background()
+	provider := NewProvider(testdata.Path("fake_bootstrap.yaml"))
+	assert.NoError(t, Boot(ctx, provider))
+
+	center := provider.GetConfigCenter()
+	prev, err := center.Load()
+	assert.NoError(t, err)
+
+	assert.NoError(t, center.Update(ctx, func(cfg *config.Configuration) error {
+		cluster := cfg.Data.DataSourceClusters[0]
+
+		// update weight of node_1
+		node1 := cluster.Groups[0].Nodes[0]
+		node1.Weight = "r5w5"
+
+		// add new node into group
+		node2 := *node1
+		node2.Name = "node_2"
+		node2.Weight = "r10w0"
+		cluster.Groups[0].Nodes = append(cluster.Groups[0].Nodes, &node2)
+
+		// add new cluster
+		cluster2 := *cluster
+		cluster2.Name = "employee2"
+		cluster2.Groups = []*config.Group{{
+			Name:  "employee2_0000",
+			Nodes: []*config.Node{&node2},
+		}}
+		cfg.Data.DataSourceClusters = append(cfg.Data.DataSourceClusters, &cluster2)
+
+		// add new view
+		cfg.Data.Views = append(cfg.Data.Views, &config.View{
+			Name:       "employee.v_student",
+			Definition: "select * from student",
+		})
+
+		// update users
+		tenant := cfg.Data.Tenants[0]
+		tenant.AllowDestructiveDDL = true
+		tenant.Consistency = &config.Consistency{Mode: config.ConsistencyGTID}
+		tenant.Limits = &config.Limits{Tenant: &config.Quota{MaxConcurrent: 1}}
+		tenant.Users = []*config.User{{Username: "dksl", Password: "123456"}}
+
+		return nil
+	}))
+	next, err := center.Load()
+	assert.NoError(t, err)
+
+	applyConfiguration(ctx, provider, prev, next)
+
+	ns := namespace.Load("employee")
+	assert.NotNil(t, ns)
+
+	assert.Eventually(t, func() bool {
+		_, ok := ns.Rule().View("v_student")
+		return ok && len(ns.DBs("employee_0000")) == 2
+	}, 3*time.Second, 10*time.Millisecond)
+
+	for _, it := range ns.DBs("employee_0000") {
+		switch it.ID() {
+		case "node_1":
+			assert.Equal(t, proto.Weight{R: 5, W: 5}, it.Weight())
+		case "node_2":
+			assert.Equal(t, proto.Weight{R: 10, W: 0}, it.Weight())
+		default:
+			assert.Fail(t, "unexpected node", it.ID())
+		}
+	}
+
+	assert.NotNil(t, namespace.Load("employee2"))
+	assert.ElementsMatch(t, []string{"employee", "employee2"}, security.DefaultTenantManager().GetClusters("arana"))
+
+	_, ok := security.DefaultTenantManager().GetUser("arana", "arana")
+	assert.False(t, ok)
+	_, ok = security.DefaultTenantManager().GetUser("arana", "dksl")
+	assert.True(t, ok)
+	assert.True(t, security.DefaultTenantManager().AllowDestructiveDDL("arana"))
+	assert.Equal(t, config.ConsistencyGTID, security.DefaultTenantManager().Consistency("arana").Mode)
+
+	release, err := limiter.Default().Acquire("arana", "dksl", "select 1")
+	assert.NoError(t, err)
+	_, err = limiter.Default().Acquire("arana", "dksl", "select 1")
+	assert.Error(t, err)
+	release()
+
+	// rollback all changes
+	assert.NoError(t, center.ImportConfiguration(prev))
+	applyConfiguration(ctx, provider, next, prev)
+
+	assert.Eventually(t, func() bool {
+		_, ok := ns.Rule().View("v_student")
+		return !ok && len(ns.DBs("employee_0000")) == 1
+	}, 3*time.Second, 10*time.Millisecond)
+	assert.Nil(t, namespace.Load("employee2"))
+	assert.Equal(t, proto.Weight{R: 10, W: 10}, ns.DBs("employee_0000")[0].Weight())
+	_, ok = security.DefaultTenantManager().GetUser("arana", "arana")
+	assert.True(t, ok)
+	assert.False(t, security.DefaultTenantManager().AllowDestructiveDDL("arana"))
+	assert.Nil(t, security.DefaultTenantManager().Consistency("arana"))
+
+	release, err = limiter.Default().Acquire("arana", "arana", "select 1")
+	assert.NoError(t, err)
+	_, err = limiter.Default().Acquire("arana", "arana", "select 1")
+	assert.NoError(t, err)
+	release()
+}
@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package breaker implements the circuit breaker of backend nodes, which rejects the requests to a node
+// quickly when its error rate is too high, and probes it after a while to see if it has recovered.
+package breaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	_defaultErrorThreshold   = 0.5
+	_defaultMinRequests      = 20
+	_defaultWindow           = 10 * time.Second
+	_defaultOpenTimeout      = 30 * time.Second
+	_defaultHalfOpenRequests = 1
+)
+
+// ErrOpen is returned when the circuit breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+var _config atomic.Value // *Config
+
+// Config represents the config of circuit breaker.
+type Config struct {
+	// ErrorThreshold is the error rate to open the breaker, default is 0.5.
+	ErrorThreshold float64 `yaml:"error_threshold"`
+	// MinRequests is the minimal count of requests in the window before the error rate is considered, default is 20.
+	MinRequests int `yaml:"min_requests"`
+	// Window is the duration of statistics, default is 10s.
+	Window time.Duration `yaml:"window"`
+	// OpenTimeout is the duration of open state before probing, default is 30s.
+	OpenTimeout time.Duration `yaml:"open_timeout"`
+	// HalfOpenRequests is the max count of probing requests in half-open state, default is 1.
+	HalfOpenRequests int `yaml:"half_open_requests"`
+}
+
+// Init enables the circuit breakers of all backend nodes.
+func Init(c *Config) {
+	conf := *c
+	if conf.ErrorThreshold <= 0 {
+		conf.ErrorThreshold = _defaultErrorThreshold
+	}
+	if conf.MinRequests <= 0 {
+		conf.MinRequests = _defaultMinRequests
+	}
+	if conf.Window <= 0 {
+		conf.Window = _defaultWindow
+	}
+	if conf.OpenTimeout <= 0 {
+		conf.OpenTimeout = _defaultOpenTimeout
+	}
+	if conf.HalfOpenRequests <= 0 {
+		conf.HalfOpenRequests = _defaultHalfOpenRequests
+	}
+	_config.Store(&conf)
+}
+
+func loadConfig() *Config {
+	c, _ := _config.Load().(*Config)
+	return c
+}
+
+// State represents the state of circuit breaker.
+type State uint8
+
+const (
+	StateClosed   State = iota // all requests are allowed
+	StateOpen                  // all requests are rejected
+	StateHalfOpen              // a few requests are allowed to probe
+)
+
+var _stateNames = [...]string{
+	StateClosed:   "closed",
+	StateOpen:     "open",
+	StateHalfOpen: "half-open",
+}
+
+// String returns the display string.
+func (s State) String() string {
+	return _stateNames[s]
+}
+
+// Outcome represents the outcome of an allowed request.
+type Outcome uint8
+
+const (
+	Success Outcome = iota // the request is served by backend
+	Failure                // the request is failed by backend
+	Ignored                // the request is abandoned before reaching backend, such as canceled by client
+)
+
+// Breaker is the circuit breaker of a backend node, it takes no effect until Init is called.
+type Breaker struct {
+	mu sync.Mutex
+
+	now func() time.Time
+
+	state       State
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+
+	// OnStateChange will be called when the state is changed.
+	OnStateChange func(from, to State)
+}
+
+// New creates a Breaker.
+func New() *Breaker {
+	return &Breaker{now: time.Now}
+}
+
+// State returns the current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c := loadConfig(); c != nil {
+		b.refresh(c)
+	}
+	return b.state
+}
+
+// Allow checks if a request is allowed, returns ErrOpen if rejected.
+// The done callback must be called with the outcome of request if allowed, the ignored request
+// is not counted, and it doesn't occupy the probe quota in half-open state any more.
+func (b *Breaker) Allow() (done func(outcome Outcome), err error) {
+	c := loadConfig()
+	if c == nil {
+		return func(Outcome) {}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(c)
+
+	switch b.state {
+	case StateOpen:
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.probes >= c.HalfOpenRequests {
+			return nil, ErrOpen
+		}
+		b.probes++
+		return b.onProbeDone, nil
+	default:
+		return func(outcome Outcome) {
+			b.onDone(c, outcome)
+		}, nil
+	}
+}
+
+// refresh switches to half-open if the open state is timeout, and resets the statistics of expired window.
+func (b *Breaker) refresh(c *Config) {
+	now := b.now()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) >= c.OpenTimeout {
+			b.probes = 0
+			b.setState(StateHalfOpen)
+		}
+	case StateClosed:
+		if now.Sub(b.windowStart) >= c.Window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+	}
+}
+
+func (b *Breaker) onDone(c *Config, outcome Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != StateClosed || outcome == Ignored {
+		return
+	}
+
+	b.requests++
+	if outcome == Failure {
+		b.failures++
+	}
+
+	if b.requests >= c.MinRequests && float64(b.failures)/float64(b.requests) >= c.ErrorThreshold {
+		b.open()
+	}
+}
+
+func (b *Breaker) onProbeDone(outcome Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != StateHalfOpen {
+		return
+	}
+
+	switch outcome {
+	case Ignored:
+		// the probe proves nothing, give the quota to the next request
+		b.probes--
+		return
+	case Failure:
+		b.open()
+		return
+	}
+
+	b.windowStart = b.now()
+	b.requests = 0
+	b.failures = 0
+	b.setState(StateClosed)
+}
+
+func (b *Breaker) open() {
+	b.openedAt = b.now()
+	b.setState(StateOpen)
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.OnStateChange != nil {
+		b.OnStateChange(from, state)
+	}
+}
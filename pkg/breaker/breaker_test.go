@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	// disabled before init
+	b := New()
+	for i := 0; i < 100; i++ {
+		done, err := b.Allow()
+		assert.NoError(t, err)
+		done(Failure)
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	Init(&Config{
+		MinRequests: 4,
+		OpenTimeout: time.Minute,
+	})
+	defer _config.Store((*Config)(nil))
+
+	now := time.Now()
+	b = New()
+	b.now = func() time.Time {
+		return now
+	}
+
+	var transitions []string
+	b.OnStateChange = func(from, to State) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	}
+
+	call := func(outcome Outcome) error {
+		done, err := b.Allow()
+		if err != nil {
+			return err
+		}
+		done(outcome)
+		return nil
+	}
+
+	// too few requests to open, the ignored requests are not counted
+	assert.NoError(t, call(Ignored))
+	assert.NoError(t, call(Ignored))
+	assert.NoError(t, call(Failure))
+	assert.NoError(t, call(Failure))
+	assert.NoError(t, call(Success))
+	assert.Equal(t, StateClosed, b.State())
+
+	// error rate 3/4 reaches the threshold
+	assert.NoError(t, call(Failure))
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, call(Success), ErrOpen)
+
+	// half-open after timeout, only one probe is allowed
+	now = now.Add(time.Minute)
+	probe, err := b.Allow()
+	assert.NoError(t, err)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, call(Success), ErrOpen)
+
+	// ignored probe returns the quota and keeps half-open
+	probe(Ignored)
+	assert.Equal(t, StateHalfOpen, b.State())
+	probe, err = b.Allow()
+	assert.NoError(t, err)
+
+	// failed probe opens again
+	probe(Failure)
+	assert.Equal(t, StateOpen, b.State())
+
+	// successful probe closes
+	now = now.Add(time.Minute)
+	assert.NoError(t, call(Success))
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, transitions)
+}
+
+func TestBreaker_Window(t *testing.T) {
+	Init(&Config{
+		MinRequests: 2,
+		Window:      time.Second,
+	})
+	defer _config.Store((*Config)(nil))
+
+	now := time.Now()
+	b := New()
+	b.now = func() time.Time {
+		return now
+	}
+
+	done, _ := b.Allow()
+	done(Failure)
+
+	// statistics are reset in the next window
+	now = now.Add(time.Second)
+	done, _ = b.Allow()
+	done(Failure)
+	assert.Equal(t, StateClosed, b.State())
+
+	done, _ = b.Allow()
+	done(Failure)
+	assert.Equal(t, StateOpen, b.State())
+}
@@ -39,6 +39,7 @@ const (
 	DefaultConfigDataSourceClustersPath PathKey = "/arana-db/config/data/dataSourceClusters"
 	DefaultConfigDataShardingRulePath   PathKey = "/arana-db/config/data/shardingRule"
 	DefaultConfigDataTenantsPath        PathKey = "/arana-db/config/data/tenants"
+	DefaultConfigDataViewsPath          PathKey = "/arana-db/config/data/views"
 )
 
 const (
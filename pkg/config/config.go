@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"sync/atomic"
 )
@@ -48,6 +49,7 @@ var (
 		DefaultConfigDataListenersPath:      "data.listeners",
 		DefaultConfigDataSourceClustersPath: "data.clusters",
 		DefaultConfigDataShardingRulePath:   "data.sharding_rule",
+		DefaultConfigDataViewsPath:          "data.views",
 	}
 
 	_configValSupplier map[PathKey]func(cfg *Configuration) interface{} = map[PathKey]func(cfg *Configuration) interface{}{
@@ -69,6 +71,9 @@ var (
 		DefaultConfigDataShardingRulePath: func(cfg *Configuration) interface{} {
 			return &cfg.Data.ShardingRule
 		},
+		DefaultConfigDataViewsPath: func(cfg *Configuration) interface{} {
+			return &cfg.Data.Views
+		},
 	}
 )
 
@@ -77,7 +82,8 @@ type Changeable interface {
 	Sign() string
 }
 
-type Observer func()
+// Observer observes the changes of configuration, it will be called with the previous and the next configuration.
+type Observer func(prev, next *Configuration)
 
 type ConfigOptions struct {
 	StoreName string                 `yaml:"name"`
@@ -89,6 +95,7 @@ type Center struct {
 	storeOperate StoreOperate
 	confHolder   atomic.Value // 里面持有了最新的 *Configuration 对象
 	lock         sync.RWMutex
+	notifyLock   sync.Mutex // keeps the observers notified in order of changes
 	observers    []Observer
 	watchCancels []context.CancelFunc
 }
@@ -152,6 +159,67 @@ func (c *Center) ImportConfiguration(cfg *Configuration) error {
 	return c.Persist()
 }
 
+// Update modifies a copy of current configuration, then replaces and persists it.
+// The current configuration will be kept if the modifier returns an error.
+func (c *Center) Update(ctx context.Context, modifier func(cfg *Configuration) error) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	current, err := c.LoadContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	clone, err := cloneConfiguration(current)
+	if err != nil {
+		return err
+	}
+
+	if err = modifier(clone); err != nil {
+		return err
+	}
+
+	// persist before replacing, so the memory and the store never diverge
+	if err = c.persist(clone); err != nil {
+		// restore the keys which have been saved already
+		if rErr := c.persist(current); rErr != nil {
+			log.Errorf("failed to restore configuration after persisting failed: %v", rErr)
+		}
+		return err
+	}
+
+	c.confHolder.Store(clone)
+
+	return nil
+}
+
+// Subscribe registers an observer which will be notified when the configuration is changed in store, then starts watching the store.
+// NOTICE: observers are called in order of changes after the lock of center is released.
+func (c *Center) Subscribe(ctx context.Context, observer Observer) error {
+	if _, err := c.LoadContext(ctx); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.observers = append(c.observers, observer)
+	c.lock.Unlock()
+
+	return c.watchFromStore()
+}
+
+func cloneConfiguration(cfg *Configuration) (*Configuration, error) {
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config json.marshal failed %v", err)
+	}
+
+	var clone Configuration
+	if err = json.Unmarshal(b, &clone); err != nil {
+		return nil, fmt.Errorf("config json.unmarshal failed %v", err)
+	}
+	return &clone, nil
+}
+
 func (c *Center) loadFromStore(ctx context.Context) (*Configuration, error) {
 	operate := c.storeOperate
 
@@ -163,6 +231,7 @@ func (c *Center) loadFromStore(ctx context.Context) (*Configuration, error) {
 			Tenants:            make([]*Tenant, 0),
 			DataSourceClusters: make([]*DataSourceCluster, 0),
 			ShardingRule:       &ShardingRule{},
+			Views:              make([]*View, 0),
 		},
 	}
 
@@ -209,33 +278,66 @@ func (c *Center) watchFromStore() error {
 }
 
 func (c *Center) watchKey(ctx context.Context, key PathKey, ch <-chan []byte) {
-	consumer := func(ret []byte) {
-		c.lock.Lock()
-		defer c.lock.Unlock()
-
+	update := func(ret []byte) (prev, next *Configuration, ok bool) {
 		supplier, ok := _configValSupplier[key]
 		if !ok {
 			log.Errorf("%s not register val supplier", key)
-			return
+			return nil, nil, false
 		}
 
-		cfg := c.confHolder.Load().(*Configuration)
+		prev = c.confHolder.Load().(*Configuration)
+
+		// never modify the current configuration in place, it may be in use.
+		next, err := cloneConfiguration(prev)
+		if err != nil {
+			log.Errorf("failed to clone configuration: %v", err)
+			return nil, nil, false
+		}
 
 		if len(ret) != 0 {
-			if err := json.Unmarshal(ret, supplier(cfg)); err != nil {
-				log.Errorf("", err)
+			target := reflect.ValueOf(supplier(next)).Elem()
+			target.Set(reflect.Zero(target.Type()))
+			if err = json.Unmarshal(ret, supplier(next)); err != nil {
+				log.Errorf("failed to unmarshal configuration %s: %v", key, err)
+				return nil, nil, false
 			}
 		}
 
-		c.confHolder.Store(cfg)
+		c.confHolder.Store(next)
+
+		return prev, next, true
+	}
+
+	consumer := func(ret []byte) {
+		c.lock.Lock()
+		prev, next, ok := update(ret)
+		if !ok {
+			c.lock.Unlock()
+			return
+		}
+		observers := append([]Observer(nil), c.observers...)
+		// hold the notify lock before releasing the center, so the observers see the changes in order,
+		// and they can update the center without dead lock.
+		c.notifyLock.Lock()
+		c.lock.Unlock()
+
+		defer c.notifyLock.Unlock()
+		for _, observer := range observers {
+			observer(prev, next)
+		}
 	}
 
 	for {
 		select {
-		case ret := <-ch:
+		case ret, ok := <-ch:
+			if !ok {
+				log.Infof("stop watch : %s", key)
+				return
+			}
 			consumer(ret)
 		case <-ctx.Done():
 			log.Infof("stop watch : %s", key)
+			return
 		}
 	}
 }
@@ -250,8 +352,10 @@ func (c *Center) PersistContext(ctx context.Context) error {
 		return errors.New("ConfHolder.load is nil")
 	}
 
-	conf := val.(*Configuration)
+	return c.persist(val.(*Configuration))
+}
 
+func (c *Center) persist(conf *Configuration) error {
 	configJson, err := json.Marshal(conf)
 	if err != nil {
 		return fmt.Errorf("config json.marshal failed  %v err:", err)
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_test
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/testdata"
+)
+
+func TestCenter_Subscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channels := make(map[config.PathKey]chan []byte)
+	for k := range config.ConfigKeyMapping {
+		channels[k] = make(chan []byte)
+	}
+
+	store := testdata.NewMockStoreOperate(ctrl)
+	store.EXPECT().Name().AnyTimes().Return("fake_subscribe")
+	store.EXPECT().Init(gomock.Any()).Return(nil)
+	store.EXPECT().Get(gomock.Any()).AnyTimes().DoAndReturn(func(key config.PathKey) ([]byte, error) {
+		if key == config.DefaultConfigDataTenantsPath {
+			return []byte(`[{"name":"arana","users":[{"username":"root","password":"123456"}]}]`), nil
+		}
+		return nil, nil
+	})
+	store.EXPECT().Watch(gomock.Any()).AnyTimes().DoAndReturn(func(key config.PathKey) (<-chan []byte, error) {
+		return channels[key], nil
+	})
+	store.EXPECT().Close().Return(nil)
+	config.Register(store)
+
+	center, err := config.NewCenter(config.ConfigOptions{StoreName: "fake_subscribe"})
+	assert.NoError(t, err)
+	defer func() {
+		_ = center.Close()
+	}()
+
+	type change struct {
+		prev, next *config.Configuration
+	}
+	changes := make(chan change, 1)
+	err = center.Subscribe(context.Background(), func(prev, next *config.Configuration) {
+		changes <- change{prev: prev, next: next}
+	})
+	assert.NoError(t, err)
+
+	channels[config.DefaultConfigDataTenantsPath] <- []byte(`[{"name":"arana","users":[{"username":"arana","password":"123456"}]}]`)
+
+	select {
+	case c := <-changes:
+		assert.NotSame(t, c.prev, c.next)
+		assert.Equal(t, "root", c.prev.Data.Tenants[0].Users[0].Username)
+		assert.Len(t, c.next.Data.Tenants[0].Users, 1)
+		assert.Equal(t, "arana", c.next.Data.Tenants[0].Users[0].Username)
+
+		current, err := center.Load()
+		assert.NoError(t, err)
+		assert.Same(t, c.next, current)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "no changes observed")
+	}
+}
@@ -47,6 +47,7 @@ var (
 		config.DefaultConfigDataSourceClustersPath: "",
 		config.DefaultConfigDataShardingRulePath:   "",
 		config.DefaultConfigDataTenantsPath:        "",
+		config.DefaultConfigDataViewsPath:          "",
 	}
 
 	cfg *config.Configuration
@@ -68,6 +68,7 @@ type (
 		DataSourceClusters []*DataSourceCluster `validate:"required,dive" yaml:"clusters" json:"clusters"`
 		ShardingRule       *ShardingRule        `validate:"required,dive" yaml:"sharding_rule,omitempty" json:"sharding_rule,omitempty"`
 		ShadowRule         *ShadowRule          `yaml:"shadow_rule,omitempty" json:"shadow_rule,omitempty"`
+		Views              []*View              `yaml:"views,omitempty" json:"views,omitempty"`
 	}
 
 	Filter struct {
@@ -78,7 +79,62 @@ type (
 	Tenant struct {
 		Name  string  `validate:"required" yaml:"name" json:"name"`
 		Users []*User `validate:"required" yaml:"users" json:"users"`
-	}
+		// AllowDestructiveDDL allows executing TRUNCATE/DROP on sharding tables without the CONFIRM hint.
+		AllowDestructiveDDL bool `yaml:"allow_destructive_ddl" json:"allow_destructive_ddl,omitempty"`
+		// Consistency is the read-your-writes consistency of read/write splitting, disabled if absent.
+		Consistency *Consistency `yaml:"consistency,omitempty" json:"consistency,omitempty"`
+		// Limits is the rate limits and concurrency quotas of statements, unlimited if absent.
+		Limits *Limits `yaml:"limits,omitempty" json:"limits,omitempty"`
+	}
+
+	// Limits restricts the statements of a tenant, a statement is rejected if any quota is exceeded.
+	Limits struct {
+		// Tenant is the quota of all statements of the tenant.
+		Tenant *Quota `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+		// User is the quota of statements of each user, it is overridden by Users.
+		User *Quota `yaml:"user,omitempty" json:"user,omitempty"`
+		// Users is the quota of statements of the specified users, username -> quota.
+		Users map[string]*Quota `yaml:"users,omitempty" json:"users,omitempty"`
+		// Fingerprints is the quota of statements with the specified fingerprints, shared by all users.
+		Fingerprints []*FingerprintQuota `yaml:"fingerprints,omitempty" json:"fingerprints,omitempty"`
+		// MaxConnections is the max count of frontend connections of the tenant, zero means unlimited.
+		MaxConnections int `yaml:"max_connections" json:"max_connections,omitempty"`
+		// MaxUserConnections is the max count of frontend connections of each user, zero means unlimited.
+		MaxUserConnections int `yaml:"max_user_connections" json:"max_user_connections,omitempty"`
+	}
+
+	// Quota represents the rate limit and concurrency quota, zero means unlimited.
+	Quota struct {
+		// QPS is the max count of statements per second.
+		QPS float64 `yaml:"qps" json:"qps,omitempty"`
+		// Burst is the max count of statements allowed at once, default is the ceiling of QPS.
+		Burst int `yaml:"burst" json:"burst,omitempty"`
+		// MaxConcurrent is the max count of executing statements.
+		MaxConcurrent int `yaml:"max_concurrent" json:"max_concurrent,omitempty"`
+	}
+
+	// FingerprintQuota is the quota of statements with the same fingerprint.
+	FingerprintQuota struct {
+		// Fingerprint is the normalized SQL or the digest of normalized SQL.
+		Fingerprint string `yaml:"fingerprint" json:"fingerprint"`
+		Quota       `yaml:",inline"`
+	}
+
+	// Consistency guarantees the reads after a write on the same connection can see the write.
+	Consistency struct {
+		// Mode is one of:
+		//   - master: route reads to master within the window after a write
+		//   - gtid: wait the replica to catch up master by WAIT_FOR_EXECUTED_GTID_SET within the window after a write
+		Mode ConsistencyMode `yaml:"mode" json:"mode"`
+		// Window is the duration after a write in which the reads should be consistent, default is 1s.
+		Window string `yaml:"window" json:"window,omitempty"`
+		// Timeout is the max duration of waiting for replica in gtid mode, the read will be routed to master
+		// if timeout, default is 500ms.
+		Timeout string `yaml:"timeout" json:"timeout,omitempty"`
+	}
+
+	// ConsistencyMode represents the mode of read-your-writes consistency.
+	ConsistencyMode string
 
 	DataSourceCluster struct {
 		Name        string         `yaml:"name" json:"name"`
@@ -87,6 +143,8 @@ type (
 		Tenant      string         `yaml:"tenant" json:"tenant"`
 		Parameters  ParametersMap  `yaml:"parameters" json:"parameters"`
 		Groups      []*Group       `yaml:"groups" json:"groups"`
+		// LoadBalance is the strategy of selecting nodes: weight_random(default), round_robin, least_active or least_latency.
+		LoadBalance string `yaml:"load_balance" json:"load_balance,omitempty"`
 	}
 
 	Group struct {
@@ -145,11 +203,23 @@ type (
 		ProtocolType  string         `yaml:"protocol_type" json:"protocol_type"`
 		SocketAddress *SocketAddress `yaml:"socket_address" json:"socket_address"`
 		ServerVersion string         `yaml:"server_version" json:"server_version"`
+		// MaxConnections is the max count of frontend connections, zero means unlimited.
+		MaxConnections int `yaml:"max_connections" json:"max_connections,omitempty"`
+		// WaitTimeout is the idle timeout of non-interactive frontend connections, default is 8h.
+		WaitTimeout string `yaml:"wait_timeout" json:"wait_timeout,omitempty"`
+		// InteractiveTimeout is the idle timeout of interactive frontend connections, default is 8h.
+		InteractiveTimeout string `yaml:"interactive_timeout" json:"interactive_timeout,omitempty"`
+		// DrainTimeout is the max duration of waiting for the transactions to finish on shutdown, default is 30s.
+		DrainTimeout string `yaml:"drain_timeout" json:"drain_timeout,omitempty"`
 	}
 
 	User struct {
 		Username string `yaml:"username" json:"username"`
 		Password string `yaml:"password" json:"password"`
+		// NodeLabel is the preferred label of backend nodes for reading, eg: zone=shanghai.
+		NodeLabel string `yaml:"node_label" json:"node_label,omitempty"`
+		// Admin grants the administrative privileges, eg: killing the connections of other users, switching the master of group.
+		Admin bool `yaml:"admin" json:"admin,omitempty"`
 	}
 
 	Table struct {
@@ -163,6 +233,12 @@ type (
 		Attributes     map[string]string `yaml:"attributes" json:"attributes"`
 	}
 
+	// View represents a logical view, the definition will be expanded when querying.
+	View struct {
+		Name       string `validate:"required" yaml:"name" json:"name"`
+		Definition string `validate:"required" yaml:"definition" json:"definition"`
+	}
+
 	Sequence struct {
 		Type   string            `yaml:"type" json:"type"`
 		Option map[string]string `yaml:"option" json:"option"`
@@ -252,6 +328,59 @@ func Load(path string) (*Configuration, error) {
 	return &cfg, nil
 }
 
+const (
+	ConsistencyMaster ConsistencyMode = "master" // route reads to master after writes
+	ConsistencyGTID   ConsistencyMode = "gtid"   // wait replica to catch up master after writes
+)
+
+const (
+	_defaultConsistencyWindow  = time.Second
+	_defaultConsistencyTimeout = 500 * time.Millisecond
+
+	_defaultWaitTimeout  = 8 * time.Hour
+	_defaultDrainTimeout = 30 * time.Second
+)
+
+// GetWaitTimeout returns the idle timeout of non-interactive frontend connections.
+func (l *Listener) GetWaitTimeout() time.Duration {
+	if d, err := time.ParseDuration(l.WaitTimeout); err == nil && d > 0 {
+		return d
+	}
+	return _defaultWaitTimeout
+}
+
+// GetInteractiveTimeout returns the idle timeout of interactive frontend connections.
+func (l *Listener) GetInteractiveTimeout() time.Duration {
+	if d, err := time.ParseDuration(l.InteractiveTimeout); err == nil && d > 0 {
+		return d
+	}
+	return _defaultWaitTimeout
+}
+
+// GetDrainTimeout returns the max duration of waiting for the transactions to finish on shutdown.
+func (l *Listener) GetDrainTimeout() time.Duration {
+	if d, err := time.ParseDuration(l.DrainTimeout); err == nil && d > 0 {
+		return d
+	}
+	return _defaultDrainTimeout
+}
+
+// GetWindow returns the consistency window after a write.
+func (c *Consistency) GetWindow() time.Duration {
+	if d, err := time.ParseDuration(c.Window); err == nil && d > 0 {
+		return d
+	}
+	return _defaultConsistencyWindow
+}
+
+// GetTimeout returns the max duration of waiting for replica.
+func (c *Consistency) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return _defaultConsistencyTimeout
+}
+
 var _weightRegexp = regexp.MustCompile(`^[rR]([0-9]+)[wW]([0-9]+)$`)
 
 func (d *Node) GetReadAndWriteWeight() (int, int, error) {
@@ -276,6 +405,30 @@ func (d *Node) String() string {
 	return string(b)
 }
 
+// UnmarshalYAML decodes the filter from YAML, the config of filter will be converted to JSON.
+func (f *Filter) UnmarshalYAML(value *yaml.Node) error {
+	var raw struct {
+		Name   string      `yaml:"name"`
+		Config interface{} `yaml:"config"`
+	}
+	if err := value.Decode(&raw); err != nil {
+		return errors.WithStack(err)
+	}
+
+	f.Name = raw.Name
+	f.Config = nil
+	if raw.Config == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(raw.Config)
+	if err != nil {
+		return errors.Wrapf(err, "invalid config of filter '%s'", raw.Name)
+	}
+	f.Config = b
+	return nil
+}
+
 func (t *ProtocolType) UnmarshalText(text []byte) error {
 	if t == nil {
 		return errors.New("can't unmarshal a nil *ProtocolType")
@@ -363,3 +516,88 @@ func GetConnPropIdleTime(connProps map[string]interface{}, defaultValue time.Dur
 
 	return time.Duration(n) * time.Second
 }
+
+// GetConnPropStatementTimeout parses the statement timeout of backend node, return default value if failed.
+// The value without unit is in milliseconds.
+func GetConnPropStatementTimeout(connProps map[string]interface{}, defaultValue time.Duration) time.Duration {
+	return getConnPropDuration(connProps, time.Millisecond, defaultValue, "statement_timeout", "statementTimeout")
+}
+
+// GetConnPropMinIdle parses the count of connections which will be opened in advance, return default value if failed.
+func GetConnPropMinIdle(connProps map[string]interface{}, defaultValue int) int {
+	var (
+		minIdle interface{}
+		ok      bool
+	)
+
+	if minIdle, ok = connProps["min_idle"]; !ok {
+		if minIdle, ok = connProps["minIdle"]; !ok {
+			return defaultValue
+		}
+	}
+	n, err := strconv.Atoi(fmt.Sprint(minIdle))
+	if err != nil || n < 0 {
+		return defaultValue
+	}
+	return n
+}
+
+// GetConnPropTestOnBorrow parses whether to validate the connection when borrowing from pool, return default value if failed.
+func GetConnPropTestOnBorrow(connProps map[string]interface{}, defaultValue bool) bool {
+	var (
+		testOnBorrow interface{}
+		ok           bool
+	)
+
+	if testOnBorrow, ok = connProps["test_on_borrow"]; !ok {
+		if testOnBorrow, ok = connProps["testOnBorrow"]; !ok {
+			return defaultValue
+		}
+	}
+	b, err := strconv.ParseBool(fmt.Sprint(testOnBorrow))
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+// GetConnPropPingOnIdle parses the idle time after which the connection will be validated when borrowing from pool,
+// return default value if failed. The value without unit is in seconds.
+func GetConnPropPingOnIdle(connProps map[string]interface{}, defaultValue time.Duration) time.Duration {
+	return getConnPropDuration(connProps, time.Second, defaultValue, "ping_on_idle", "pingOnIdle")
+}
+
+// GetConnPropMaxLifetime parses the max lifetime of backend connection, return default value if failed.
+// The value without unit is in seconds.
+func GetConnPropMaxLifetime(connProps map[string]interface{}, defaultValue time.Duration) time.Duration {
+	return getConnPropDuration(connProps, time.Second, defaultValue, "max_lifetime", "maxLifetime")
+}
+
+func getConnPropDuration(connProps map[string]interface{}, unit, defaultValue time.Duration, keys ...string) time.Duration {
+	var (
+		value interface{}
+		ok    bool
+	)
+
+	for _, key := range keys {
+		if value, ok = connProps[key]; ok {
+			break
+		}
+	}
+	if !ok {
+		return defaultValue
+	}
+
+	s := fmt.Sprint(value)
+	d, _ := time.ParseDuration(s)
+	if d > 0 {
+		return d
+	}
+
+	n, _ := strconv.Atoi(s)
+	if n < 1 {
+		return defaultValue
+	}
+
+	return time.Duration(n) * unit
+}
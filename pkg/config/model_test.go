@@ -19,10 +19,13 @@ package config_test
 
 import (
 	"testing"
+	"time"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/yaml.v3"
 )
 
 import (
@@ -129,3 +132,33 @@ func TestUnmarshalText(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, config.MySQL, protocolType)
 }
+
+func TestUnmarshalFilter(t *testing.T) {
+	var filters []*config.Filter
+	err := yaml.Unmarshal([]byte(`
+- name: firewall
+  config:
+    rules:
+      - tenant: arana
+        deny_no_where: true
+- name: noop
+`), &filters)
+	assert.NoError(t, err)
+	assert.Len(t, filters, 2)
+	assert.Equal(t, "firewall", filters[0].Name)
+	assert.JSONEq(t, `{"rules":[{"tenant":"arana","deny_no_where":true}]}`, string(filters[0].Config))
+	assert.Equal(t, "noop", filters[1].Name)
+	assert.Nil(t, filters[1].Config)
+}
+
+func TestListenerTimeouts(t *testing.T) {
+	l := &config.Listener{}
+	assert.Equal(t, 8*time.Hour, l.GetWaitTimeout())
+	assert.Equal(t, 8*time.Hour, l.GetInteractiveTimeout())
+	assert.Equal(t, 30*time.Second, l.GetDrainTimeout())
+
+	l = &config.Listener{WaitTimeout: "10m", InteractiveTimeout: "1h", DrainTimeout: "5s"}
+	assert.Equal(t, 10*time.Minute, l.GetWaitTimeout())
+	assert.Equal(t, time.Hour, l.GetInteractiveTimeout())
+	assert.Equal(t, 5*time.Second, l.GetDrainTimeout())
+}
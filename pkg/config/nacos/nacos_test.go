@@ -49,6 +49,7 @@ var (
 		config.DefaultConfigDataSourceClustersPath: "",
 		config.DefaultConfigDataShardingRulePath:   "",
 		config.DefaultConfigDataTenantsPath:        "",
+		config.DefaultConfigDataViewsPath:          "",
 	}
 
 	cfg *config.Configuration
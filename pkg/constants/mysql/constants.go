@@ -101,8 +101,9 @@ const (
 	// New 4.1 protocol. Enforced everywhere.
 	CapabilityClientProtocol41 = 1 << 9
 
-	// CLIENT_INTERACTIVE 1 << 10
-	// Not specified, ignored.
+	// CapabilityClientInteractive is CLIENT_INTERACTIVE.
+	// The idle timeout of connection is interactive_timeout instead of wait_timeout.
+	CapabilityClientInteractive = 1 << 10
 
 	// CapabilityClientSSL is CLIENT_SSL.
 	// Switch to SSL after handshake.
@@ -413,6 +414,7 @@ const (
 	ERDelayedInsertTableLocked      = 1165
 	ERDupUnique                     = 1169
 	ERRequiresPrimaryKey            = 1173
+	ERUpdateWithoutKeyInSafeMode    = 1175
 	ERCantDoThisDuringAnTransaction = 1179
 	ERReadOnlyTransaction           = 1207
 	ERCannotAddForeign              = 1215
@@ -540,6 +542,8 @@ const (
 	ERTruncatedWrongValueForField  = 1366
 	ERDataTooLong                  = 1406
 	ERDataOutOfRange               = 1690
+	ERQueryTimeout                 = 3024
+	ERClientInteractionTimeout     = 4031
 )
 
 // Sql states for errors.
@@ -579,9 +583,15 @@ const (
 	// ER_CANT_DO_THIS_DURING_AN_TRANSACTION
 	SSCantDoThisDuringAnTransaction = "25000"
 
+	// SSConCount is ER_CON_COUNT_ERROR
+	SSConCount = "08004"
+
 	// SSAccessDeniedError is ER_ACCESS_DENIED_ERROR
 	SSAccessDeniedError = "28000"
 
+	// SSDBAccessDenied is ER_DBACCESS_DENIED_ERROR
+	SSDBAccessDenied = "42000"
+
 	// SSLockDeadlock is ER_LOCK_DEADLOCK
 	SSLockDeadlock = "40001"
 
@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+import (
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/dataset"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// _alterGroupRegexp matches the admin statement which is not supported by parser, eg:
+//
+//	ALTER GROUP employees_0000 SET MASTER node0
+//	ALTER GROUP employees_0000 SET MASTER = DEFAULT
+var _alterGroupRegexp = regexp.MustCompile("(?is)^\\s*ALTER\\s+GROUP\\s+`?([^`\\s;]+)`?\\s+SET\\s+MASTER\\s*(?:=\\s*)?`?([^`\\s;]+)`?\\s*;?\\s*$")
+
+// _showPoolStatusRegexp matches the statement which shows the stats of backend connection pools, eg:
+//
+//	SHOW POOL STATUS
+var _showPoolStatusRegexp = regexp.MustCompile("(?is)^\\s*SHOW\\s+POOL\\s+STATUS\\s*;?\\s*$")
+
+// _showDDLJobsRegexp matches the statement which shows the sharding DDL jobs without the ADMIN keyword, eg:
+//
+//	SHOW DDL JOBS
+//	SHOW DDL JOBS 10
+var _showDDLJobsRegexp = regexp.MustCompile("(?is)^\\s*SHOW\\s+DDL\\s+JOBS\\b")
+
+// alterGroupStatement represents the statement which specifies the master of group manually.
+type alterGroupStatement struct {
+	group  string
+	master string // empty if DEFAULT, which means the master will be elected by weight again
+}
+
+// parseAlterGroup parses the ALTER GROUP statement, returns false if the query doesn't match.
+func parseAlterGroup(query string) (*alterGroupStatement, bool) {
+	matches := _alterGroupRegexp.FindStringSubmatch(query)
+	if matches == nil {
+		return nil, false
+	}
+	stmt := &alterGroupStatement{
+		group:  matches[1],
+		master: matches[2],
+	}
+	if strings.EqualFold(stmt.master, "DEFAULT") {
+		stmt.master = ""
+	}
+	return stmt, true
+}
+
+// executeAlterGroup pins the master of group in current schema, the master will not be switched by failover
+// detection until it is reset to DEFAULT. Only the admin is allowed to do it.
+//
+// NOTICE: the pin is kept in the memory of current proxy instance only, it is neither persisted into the
+// configuration nor shared with other instances, and it will be lost after restart.
+func executeAlterGroup(ctx *proto.Context, stmt *alterGroupStatement) (proto.Result, uint16, error) {
+	if !security.IsAdmin(ctx.Tenant, ctx.Username) {
+		return nil, 0, mysqlErrors.NewSQLError(mConstants.ERSpecifiedAccessDenied, mConstants.SSDBAccessDenied,
+			"Access denied; you need the admin privilege for this operation")
+	}
+	if len(ctx.Schema) < 1 {
+		return nil, 0, errNoDatabaseSelected
+	}
+	if _, err := checkSchema(ctx.Tenant, ctx.Schema); err != nil {
+		return nil, 0, err
+	}
+
+	ns := namespace.Load(ctx.Schema)
+	if ns == nil {
+		return nil, 0, mysqlErrors.NewSQLError(mConstants.ERBadDb, mConstants.SSUnknownSQLState,
+			"Unknown database '%s'", ctx.Schema)
+	}
+
+	dbs := ns.DBs(stmt.group)
+	if len(dbs) < 1 {
+		return nil, 0, mysqlErrors.NewSQLError(mConstants.ERWrongArguments, mConstants.SSUnknownSQLState,
+			"no such group '%s' in database '%s'", stmt.group, ctx.Schema)
+	}
+
+	if len(stmt.master) > 0 {
+		var found bool
+		for _, db := range dbs {
+			if db.ID() == stmt.master {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, 0, mysqlErrors.NewSQLError(mConstants.ERWrongArguments, mConstants.SSUnknownSQLState,
+				"no such node '%s' in group '%s'", stmt.master, stmt.group)
+		}
+	}
+
+	if err := ns.EnqueueCommand(namespace.PinMaster(stmt.group, stmt.master)); err != nil {
+		return nil, 0, err
+	}
+
+	return resultx.New(), 0, nil
+}
+
+// isShowPoolStatus returns true if the query is the SHOW POOL STATUS statement.
+func isShowPoolStatus(query string) bool {
+	return _showPoolStatusRegexp.MatchString(query)
+}
+
+// executeShowPoolStatus shows the stats of backend connection pools in current schema.
+func executeShowPoolStatus(ctx *proto.Context) (proto.Result, uint16, error) {
+	if len(ctx.Schema) < 1 {
+		return nil, 0, errNoDatabaseSelected
+	}
+	if _, err := checkSchema(ctx.Tenant, ctx.Schema); err != nil {
+		return nil, 0, err
+	}
+
+	ns := namespace.Load(ctx.Schema)
+	if ns == nil {
+		return nil, 0, mysqlErrors.NewSQLError(mConstants.ERBadDb, mConstants.SSUnknownSQLState,
+			"Unknown database '%s'", ctx.Schema)
+	}
+
+	fields := thead.PoolStatus.ToFields()
+	ds := &dataset.VirtualDataset{
+		Columns: fields,
+	}
+	for _, it := range runtime.PoolStats(ns) {
+		ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+			it.Group,
+			it.Node,
+			it.Capacity,
+			it.MaxCapacity,
+			it.Available,
+			it.Active,
+			it.InUse,
+			it.WaitCount,
+			it.WaitTime.Milliseconds(),
+			it.IdleClosed,
+			it.Expired,
+			it.Invalid,
+		}))
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), 0, nil
+}
+
+// rewriteShowDDLJobs rewrites SHOW DDL JOBS to ADMIN SHOW DDL JOBS which is supported by parser.
+func rewriteShowDDLJobs(query string) string {
+	if _showDDLJobsRegexp.MatchString(query) {
+		return "ADMIN " + strings.TrimLeftFunc(query, unicode.IsSpace)
+	}
+	return query
+}
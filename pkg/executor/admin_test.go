@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/testdata"
+)
+
+func TestParseAlterGroup(t *testing.T) {
+	type tt struct {
+		sql    string
+		ok     bool
+		group  string
+		master string
+	}
+
+	for _, it := range []tt{
+		{"ALTER GROUP employees_0000 SET MASTER node0", true, "employees_0000", "node0"},
+		{"alter group `employees_0000` set master = `node-1`;", true, "employees_0000", "node-1"},
+		{"  ALTER GROUP employees_0000\n SET MASTER default ", true, "employees_0000", ""},
+		{"ALTER TABLE student ADD COLUMN age INT", false, "", ""},
+		{"ALTER GROUP employees_0000 SET MASTER", false, "", ""},
+		{"ALTER GROUP employees_0000 SET MASTER node0 node1", false, "", ""},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmt, ok := parseAlterGroup(it.sql)
+			assert.Equal(t, it.ok, ok)
+			if ok {
+				assert.Equal(t, it.group, stmt.group)
+				assert.Equal(t, it.master, stmt.master)
+			}
+		})
+	}
+}
+
+func TestExecuteAlterGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		tenant = "fake-tenant-alter-group"
+		schema = "alter_group"
+		group  = "alter_group_0000"
+	)
+
+	security.DefaultTenantManager().PutCluster(tenant, schema)
+	defer security.DefaultTenantManager().RemoveCluster(tenant, schema)
+	security.DefaultTenantManager().PutUser(tenant, &config.User{Username: "admin", Admin: true})
+	security.DefaultTenantManager().PutUser(tenant, &config.User{Username: "guest"})
+	defer security.DefaultTenantManager().RemoveUser(tenant, "admin")
+	defer security.DefaultTenantManager().RemoveUser(tenant, "guest")
+
+	getDB := func(id string, w int32) proto.DB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(id).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: 10, W: w}).AnyTimes()
+		db.EXPECT().Close().AnyTimes()
+		return db
+	}
+
+	ns, err := namespace.New(schema, namespace.UpsertDB(group, getDB("node0", 10)), namespace.UpsertDB(group, getDB("node1", 0)))
+	assert.NoError(t, err)
+	assert.NoError(t, namespace.Register(ns))
+	defer func() {
+		_ = namespace.Unregister(schema)
+	}()
+
+	executeAs := func(username, schema, sql string) error {
+		stmt, ok := parseAlterGroup(sql)
+		assert.True(t, ok)
+		_, _, err := executeAlterGroup(&proto.Context{Tenant: tenant, Username: username, Schema: schema}, stmt)
+		return err
+	}
+	execute := func(schema, sql string) error {
+		return executeAs("admin", schema, sql)
+	}
+	errno := func(err error) int {
+		if assert.IsType(t, (*mysqlErrors.SQLError)(nil), err) {
+			return err.(*mysqlErrors.SQLError).Num
+		}
+		return 0
+	}
+
+	assert.Equal(t, mConstants.ERSpecifiedAccessDenied, errno(executeAs("guest", schema, "ALTER GROUP alter_group_0000 SET MASTER node1")))
+	assert.False(t, ns.MasterPinned(group))
+	assert.Equal(t, mConstants.ERNoDb, errno(execute("", "ALTER GROUP alter_group_0000 SET MASTER node1")))
+	assert.Equal(t, mConstants.ERWrongArguments, errno(execute(schema, "ALTER GROUP alter_group_0001 SET MASTER node1")))
+	assert.Equal(t, mConstants.ERWrongArguments, errno(execute(schema, "ALTER GROUP alter_group_0000 SET MASTER node2")))
+
+	assert.NoError(t, execute(schema, "ALTER GROUP alter_group_0000 SET MASTER node1"))
+	assert.Eventually(t, func() bool {
+		return ns.DBMaster(context.Background(), group).ID() == "node1" && ns.MasterPinned(group)
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, execute(schema, "ALTER GROUP alter_group_0000 SET MASTER DEFAULT"))
+	assert.Eventually(t, func() bool {
+		return ns.DBMaster(context.Background(), group).ID() == "node0" && !ns.MasterPinned(group)
+	}, time.Second, time.Millisecond)
+}
+
+func TestIsShowPoolStatus(t *testing.T) {
+	assert.True(t, isShowPoolStatus("SHOW POOL STATUS"))
+	assert.True(t, isShowPoolStatus(" show pool\n status; "))
+	assert.False(t, isShowPoolStatus("SHOW STATUS"))
+	assert.False(t, isShowPoolStatus("SHOW POOL STATUS LIKE 'node0'"))
+}
+
+func TestRewriteShowDDLJobs(t *testing.T) {
+	assert.Equal(t, "ADMIN SHOW DDL JOBS", rewriteShowDDLJobs("SHOW DDL JOBS"))
+	assert.Equal(t, "ADMIN show ddl jobs 10", rewriteShowDDLJobs("  show ddl jobs 10"))
+	assert.Equal(t, "ADMIN SHOW DDL JOBS", rewriteShowDDLJobs("ADMIN SHOW DDL JOBS"))
+	assert.Equal(t, "SHOW DDL JOBSX", rewriteShowDDLJobs("SHOW DDL JOBSX"))
+}
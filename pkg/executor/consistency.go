@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+)
+
+import (
+	"github.com/arana-db/parser/ast"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/consistency"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// withConsistency binds the consistency session of frontend connection if the tenant requires
+// read-your-writes consistency.
+func (executor *RedirectExecutor) withConsistency(ctx *proto.Context) context.Context {
+	c := security.DefaultTenantManager().Consistency(ctx.Tenant)
+	if c == nil || len(c.Mode) < 1 {
+		return ctx.Context
+	}
+
+	exist, ok := executor.sessions.Load(ctx.ConnectionID)
+	if !ok {
+		exist, _ = executor.sessions.LoadOrStore(ctx.ConnectionID, consistency.NewSession())
+	}
+
+	return consistency.WithSession(ctx.Context, c, exist.(*consistency.Session))
+}
+
+// markWrite marks the consistency session of frontend connection if the statement is a write.
+func (executor *RedirectExecutor) markWrite(ctx *proto.Context, stmt ast.StmtNode) {
+	if !isWrite(stmt) {
+		return
+	}
+	if exist, ok := executor.sessions.Load(ctx.ConnectionID); ok {
+		exist.(*consistency.Session).MarkWrite()
+	}
+}
+
+// isWrite returns true if the statement may change the data of backends.
+func isWrite(stmt ast.StmtNode) bool {
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt, *ast.ShowStmt, *ast.ExplainStmt, *ast.SetStmt, *ast.UseStmt,
+		*ast.BeginStmt, *ast.RollbackStmt:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/consistency"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+func TestIsWrite(t *testing.T) {
+	for _, it := range []struct {
+		sql    string
+		expect bool
+	}{
+		{"select * from student", false},
+		{"select 1 union select 2", false},
+		{"show tables", false},
+		{"begin", false},
+		{"rollback", false},
+		{"set names utf8mb4", false},
+		{"insert into student(id) values(1)", true},
+		{"update student set age = 18", true},
+		{"delete from student", true},
+		{"commit", true},
+		{"alter table student add column age int", true},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(it.sql, "", "")
+			assert.NoError(t, err)
+			assert.Equal(t, it.expect, isWrite(stmt))
+		})
+	}
+}
+
+func TestWithConsistency(t *testing.T) {
+	const tenant = "fake-tenant-consistency"
+
+	executor := NewRedirectExecutor()
+	ctx := &proto.Context{
+		Context:      context.Background(),
+		Tenant:       tenant,
+		ConnectionID: 1,
+	}
+
+	// disabled
+	assert.Equal(t, ctx.Context, executor.withConsistency(ctx))
+	_, ok := executor.sessions.Load(ctx.ConnectionID)
+	assert.False(t, ok)
+
+	security.DefaultTenantManager().SetConsistency(tenant, &config.Consistency{Mode: config.ConsistencyMaster})
+	defer security.DefaultTenantManager().SetConsistency(tenant, nil)
+
+	assert.NotEqual(t, ctx.Context, executor.withConsistency(ctx))
+	s, ok := executor.sessions.Load(ctx.ConnectionID)
+	assert.True(t, ok)
+	assert.IsType(t, (*consistency.Session)(nil), s)
+
+	// reuse the session of connection
+	executor.withConsistency(ctx)
+	exist, _ := executor.sessions.Load(ctx.ConnectionID)
+	assert.Same(t, s, exist)
+
+	executor.ConnectionClose(ctx)
+	_, ok = executor.sessions.Load(ctx.ConnectionID)
+	assert.False(t, ok)
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// _attrNodeLabel is the connection attribute which specifies the preferred label of backend nodes, eg: zone=shanghai.
+const _attrNodeLabel = "node_label"
+
+// withNodeLabel binds the preferred node label from the connection attributes or the user config,
+// the connection attribute takes precedence.
+func withNodeLabel(ctx *proto.Context) context.Context {
+	if label := ctx.Attributes[_attrNodeLabel]; len(label) > 0 {
+		return rcontext.WithNodeLabel(ctx.Context, label)
+	}
+	if user, ok := security.DefaultTenantManager().GetUser(ctx.Tenant, ctx.Username); ok && len(user.NodeLabel) > 0 {
+		return rcontext.WithNodeLabel(ctx.Context, user.NodeLabel)
+	}
+	return ctx.Context
+}
@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+func TestWithNodeLabel(t *testing.T) {
+	const tenant = "fake-tenant-node-label"
+
+	security.DefaultTenantManager().PutUser(tenant, &config.User{Username: "foo", NodeLabel: "zone=shanghai"})
+	security.DefaultTenantManager().PutUser(tenant, &config.User{Username: "bar"})
+	defer func() {
+		security.DefaultTenantManager().RemoveUser(tenant, "foo")
+		security.DefaultTenantManager().RemoveUser(tenant, "bar")
+	}()
+
+	label := func(username string, attributes map[string]string) string {
+		return rcontext.NodeLabel(withNodeLabel(&proto.Context{
+			Context:    context.Background(),
+			Tenant:     tenant,
+			Username:   username,
+			Attributes: attributes,
+		}))
+	}
+
+	assert.Equal(t, "zone=shanghai", label("foo", nil))
+	assert.Equal(t, "", label("bar", nil))
+	assert.Equal(t, "zone=beijing", label("foo", map[string]string{"node_label": "zone=beijing"}))
+	assert.Equal(t, "zone=beijing", label("bar", map[string]string{"node_label": "zone=beijing"}))
+}
@@ -36,14 +36,16 @@ import (
 
 import (
 	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/limiter"
 	"github.com/arana-db/arana/pkg/metrics"
 	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/process"
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/hint"
 	"github.com/arana-db/arana/pkg/resultx"
 	"github.com/arana-db/arana/pkg/runtime"
 	rcontext "github.com/arana-db/arana/pkg/runtime/context"
-	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/pkg/tracing"
 	"github.com/arana-db/arana/pkg/util/log"
 )
 
@@ -63,6 +65,7 @@ type RedirectExecutor struct {
 	preFilters          []proto.PreFilter
 	postFilters         []proto.PostFilter
 	localTransactionMap sync.Map // map[uint32]proto.Tx, (ConnectionID,Tx)
+	sessions            sync.Map // map[uint32]*consistency.Session, (ConnectionID,Session)
 }
 
 func NewRedirectExecutor() *RedirectExecutor {
@@ -99,21 +102,12 @@ func (executor *RedirectExecutor) InGlobalTransaction(ctx *proto.Context) bool {
 }
 
 func (executor *RedirectExecutor) ExecuteUseDB(ctx *proto.Context) error {
-	// TODO: check permission, target database should belong to same tenant.
 	// TODO: process transactions when database switched?
-
-	// do nothing.
-	//resourcePool := resource.GetDataSourceManager().GetMasterResourcePool(executor.dataSources[0].Master.Name)
-	//r, err := resourcePool.Get(ctx)
-	//defer func() {
-	//	resourcePool.Put(r)
-	//}()
-	//if err != nil {
-	//	return err
-	//}
-	//backendConn := r.(*mysql.BackendConnection)
-	//db := string(ctx.Data[1:])
-	//return backendConn.WriteComInitDB(db)
+	schema, err := checkSchema(ctx.Tenant, string(ctx.Data[1:]))
+	if err != nil {
+		return err
+	}
+	ctx.Schema = schema
 	return nil
 }
 
@@ -138,23 +132,45 @@ func (executor *RedirectExecutor) ExecuteFieldList(ctx *proto.Context) ([]proto.
 		return nil, errors.New("cannot get physical backend connection")
 	}
 
-	return db.CallFieldList(ctx.Context, table, wildcard)
+	return db.CallFieldList(rcontext.WithVariables(ctx.Context, ctx.Variables), table, wildcard)
 }
 
 func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Result, uint16, error) {
 	var span trace.Span
-	ctx.Context, span = Tracer.Start(ctx.Context, "ExecutorComQuery")
+	// join the trace of application if the traceparent is specified in the leading comments
+	ctx.Context = tracing.Extract(ctx.Context, ctx.GetQuery())
+	ctx.Context, span = Tracer.Start(ctx.Context, "ExecutorComQuery", trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	var (
-		schemaless bool // true if schema is not specified
-		err        error
-	)
+	query := ctx.GetQuery()
+
+	// the admin statements which are not supported by parser, they are still checked by filters
+	if stmt, ok := parseAlterGroup(query); ok {
+		return executor.executeUnparsed(ctx, func() (proto.Result, uint16, error) {
+			return executeAlterGroup(ctx, stmt)
+		})
+	}
+	if isShowPoolStatus(query) {
+		return executor.executeUnparsed(ctx, func() (proto.Result, uint16, error) {
+			return executeShowPoolStatus(ctx)
+		})
+	}
+
+	release, err := limiter.Default().Acquire(ctx.Tenant, ctx.Username, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, warn, err := executor.executeQuery(ctx, query)
+	return releaseOnClose(res, warn, err, release)
+}
+
+// executeQuery parses and executes the query of COM_QUERY.
+func (executor *RedirectExecutor) executeQuery(ctx *proto.Context, query string) (proto.Result, uint16, error) {
+	var schemaless bool // true if schema is not specified
 
 	p := parser.New()
-	query := ctx.GetQuery()
 	start := time.Now()
-	act, hts, err := p.ParseOneStmtHints(query, "", "")
+	act, hts, err := p.ParseOneStmtHints(rewriteShowDDLJobs(query), "", "")
 	if err != nil {
 		return nil, 0, errors.WithStack(err)
 	}
@@ -171,21 +187,38 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 	metrics.ParserDuration.Observe(time.Since(start).Seconds())
 	log.Debugf("ComQuery: %s", query)
 
-	if len(ctx.Schema) < 1 {
-		// TODO: handle multiple clusters
-		clusters := security.DefaultTenantManager().GetClusters(ctx.Tenant)
-		if len(clusters) != 1 {
-			// reject if no schema specified
-			return nil, 0, mysqlErrors.NewSQLError(mConstants.ERNoDb, mConstants.SSNoDatabaseSelected, "No database selected")
+	if use, ok := act.(*ast.UseStmt); ok {
+		var schema string
+		if schema, err = checkSchema(ctx.Tenant, use.DBName); err != nil {
+			return nil, 0, err
 		}
-		schemaless = true
-		ctx.Schema = security.DefaultTenantManager().GetClusters(ctx.Tenant)[0]
+		// switch the schema of frontend connection
+		ctx.Schema = schema
+		return resultx.New(), 0, nil
+	}
+
+	// the resolved schema only takes effect on current statement
+	schema := ctx.Schema
+	defer func() {
+		ctx.Schema = schema
+	}()
+
+	if ctx.Schema, schemaless, err = resolveSchema(ctx, act); err != nil {
+		return nil, 0, err
+	}
+
+	if _, ok := executor.getTx(ctx); ok && ctx.Schema != schema {
+		return nil, 0, mysqlErrors.NewSQLError(mConstants.ERNotSupportedYet, mConstants.SSUnknownSQLState,
+			"cannot access database '%s' in the transaction of '%s'", ctx.Schema, schema)
 	}
 
 	ctx.Stmt = &proto.Stmt{
 		Hints:    hints,
 		StmtNode: act,
 	}
+	ctx.Context = rcontext.WithVariables(ctx.Context, ctx.Variables)
+	ctx.Context = withNodeLabel(ctx)
+	ctx.Context = executor.withConsistency(ctx)
 
 	rt, err := runtime.Load(ctx.Schema)
 	if err != nil {
@@ -197,7 +230,12 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 		warn uint16
 	)
 
-	executor.doPreFilter(ctx)
+	if err = executor.doPreFilter(ctx); err != nil {
+		executor.doPostFilter(ctx, nil, err)
+		return nil, 0, err
+	}
+	// the statement may be rewritten by filters
+	act = ctx.Stmt.StmtNode
 
 	switch stmt := act.(type) {
 	case *ast.BeginStmt:
@@ -217,7 +255,9 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 		} else {
 			// remove existing tx, and commit it
 			if tx, ok := executor.removeTx(ctx); ok {
-				res, warn, err = tx.Commit(ctx.Context)
+				if res, warn, err = tx.Commit(ctx.Context); err == nil {
+					metrics.Transactions.WithLabelValues(ctx.Tenant, ctx.Schema, metrics.TxCommit).Inc()
+				}
 			} else {
 				res, warn, err = nil, 0, errMissingTx
 			}
@@ -228,7 +268,9 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 		} else {
 			// remove existing tx, and rollback it
 			if tx, ok := executor.removeTx(ctx); ok {
-				res, warn, err = tx.Rollback(ctx.Context)
+				if res, warn, err = tx.Rollback(ctx.Context); err == nil {
+					metrics.Transactions.WithLabelValues(ctx.Tenant, ctx.Schema, metrics.TxRollback).Inc()
+				}
 			} else {
 				res, warn, err = nil, 0, errMissingTx
 			}
@@ -259,7 +301,7 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 	case *ast.ShowStmt:
 		allowSchemaless := func(stmt *ast.ShowStmt) bool {
 			switch stmt.Tp {
-			case ast.ShowDatabases, ast.ShowVariables, ast.ShowTopology, ast.ShowStatus:
+			case ast.ShowDatabases, ast.ShowVariables, ast.ShowTopology, ast.ShowStatus, ast.ShowProcessList:
 				return true
 			default:
 				return false
@@ -271,10 +313,17 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 		} else {
 			err = errNoDatabaseSelected
 		}
-	case *ast.TruncateTableStmt, *ast.DropTableStmt, *ast.ExplainStmt, *ast.DropIndexStmt, *ast.CreateIndexStmt:
+	case *ast.TruncateTableStmt, *ast.DropTableStmt, *ast.ExplainStmt, *ast.DropIndexStmt, *ast.CreateIndexStmt, *ast.RenameTableStmt, *ast.AdminStmt,
+		*ast.CreateViewStmt:
 		res, warn, err = executeStmt(ctx, schemaless, rt)
-	case *ast.DropTriggerStmt:
+	case *ast.DropTriggerStmt, *ast.KillStmt:
 		res, warn, err = rt.Execute(ctx)
+	case *ast.SetStmt:
+		if schemaless {
+			err = errNoDatabaseSelected
+		} else {
+			res, warn, err = executor.executeSet(ctx, stmt, rt)
+		}
 	default:
 		if schemaless {
 			err = errNoDatabaseSelected
@@ -289,11 +338,54 @@ func (executor *RedirectExecutor) ExecutorComQuery(ctx *proto.Context) (proto.Re
 		}
 	}
 
-	executor.doPostFilter(ctx, res)
+	if err == nil {
+		executor.markWrite(ctx, act)
+	}
+
+	res = executor.doPostFilter(ctx, res, err)
 
 	return res, warn, err
 }
 
+// executeSet executes the SET statement, the session variables will be tracked if succeed,
+// then they will be replayed when the backend connections are borrowed.
+func (executor *RedirectExecutor) executeSet(ctx *proto.Context, stmt *ast.SetStmt, rt runtime.Runtime) (proto.Result, uint16, error) {
+	variables, err := applySetStmt(ctx.Variables, stmt)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the new variables will be validated when the backend connection is borrowed
+	ctx.Context = rcontext.WithDirect(rcontext.WithVariables(ctx.Context, variables))
+
+	var (
+		res  proto.Result
+		warn uint16
+	)
+	if tx, ok := executor.getTx(ctx); ok {
+		res, warn, err = tx.Execute(ctx)
+	} else {
+		res, warn, err = rt.Execute(ctx)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// never modify the variables in place, they may be still in use by the backend connections
+	ctx.Variables = variables
+	return res, warn, nil
+}
+
+// releaseOnClose holds the quota of limiter until the result is closed, since the rows may be still read from
+// the backend after the statement returns, the quota is released at once if the statement is failed.
+func releaseOnClose(res proto.Result, warn uint16, err error, release func()) (proto.Result, uint16, error) {
+	if err != nil || res == nil {
+		release()
+		return res, warn, err
+	}
+	return resultx.OnClose(res, release), warn, nil
+}
+
 func executeStmt(ctx *proto.Context, schemaless bool, rt runtime.Runtime) (proto.Result, uint16, error) {
 	if schemaless {
 		return nil, 0, errNoDatabaseSelected
@@ -302,6 +394,11 @@ func executeStmt(ctx *proto.Context, schemaless bool, rt runtime.Runtime) (proto
 }
 
 func (executor *RedirectExecutor) ExecutorComStmtExecute(ctx *proto.Context) (proto.Result, uint16, error) {
+	var span trace.Span
+	ctx.Context = tracing.Extract(ctx.Context, ctx.Stmt.PrepareStmt)
+	ctx.Context, span = Tracer.Start(ctx.Context, "ExecutorComStmtExecute", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
 	var (
 		executable proto.Executable
 		result     proto.Result
@@ -309,16 +406,26 @@ func (executor *RedirectExecutor) ExecutorComStmtExecute(ctx *proto.Context) (pr
 		err        error
 	)
 
+	release, err := limiter.Default().Acquire(ctx.Tenant, ctx.Username, ctx.Stmt.PrepareStmt)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if tx, ok := executor.getTx(ctx); ok {
 		executable = tx
 	} else {
 		var rt runtime.Runtime
 		if rt, err = runtime.Load(ctx.Schema); err != nil {
+			release()
 			return nil, 0, err
 		}
 		executable = rt
 	}
 
+	ctx.Context = rcontext.WithVariables(ctx.Context, ctx.Variables)
+	ctx.Context = withNodeLabel(ctx)
+	ctx.Context = executor.withConsistency(ctx)
+
 	switch ctx.Stmt.StmtNode.(type) {
 	case *ast.SelectStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.AlterTableStmt:
 	default:
@@ -328,20 +435,31 @@ func (executor *RedirectExecutor) ExecutorComStmtExecute(ctx *proto.Context) (pr
 	query := ctx.Stmt.StmtNode.Text()
 	log.Debugf("ComStmtExecute: %s", query)
 
-	executor.doPreFilter(ctx)
+	if err = executor.doPreFilter(ctx); err != nil {
+		release()
+		executor.doPostFilter(ctx, nil, err)
+		return nil, 0, err
+	}
 	result, warn, err = executable.Execute(ctx)
-	executor.doPostFilter(ctx, result)
-	return result, warn, err
+	if err == nil {
+		executor.markWrite(ctx, ctx.Stmt.StmtNode)
+	}
+	result = executor.doPostFilter(ctx, result, err)
+	return releaseOnClose(result, warn, err, release)
 }
 
 func (executor *RedirectExecutor) ConnectionClose(ctx *proto.Context) {
+	executor.sessions.Delete(ctx.ConnectionID)
+
 	tx, ok := executor.removeTx(ctx)
 	if !ok {
 		return
 	}
 	if _, _, err := tx.Rollback(ctx); err != nil {
 		log.Errorf("failed to rollback tx: %s", err)
+		return
 	}
+	metrics.Transactions.WithLabelValues(ctx.Tenant, ctx.Schema, metrics.TxRollback).Inc()
 
 	//resourcePool := resource.GetDataSourceManager().GetMasterResourcePool(executor.dataSources[0].Master.Name)
 	//r, ok := executor.localTransactionMap[ctx.ConnectionID]
@@ -359,6 +477,9 @@ func (executor *RedirectExecutor) ConnectionClose(ctx *proto.Context) {
 
 func (executor *RedirectExecutor) putTx(ctx *proto.Context, tx proto.Tx) {
 	executor.localTransactionMap.Store(ctx.ConnectionID, tx)
+	if p, ok := process.Load(ctx.ConnectionID); ok {
+		p.SetTxID(tx.ID())
+	}
 }
 
 func (executor *RedirectExecutor) removeTx(ctx *proto.Context) (proto.Tx, bool) {
@@ -366,6 +487,9 @@ func (executor *RedirectExecutor) removeTx(ctx *proto.Context) (proto.Tx, bool)
 	if !ok {
 		return nil, false
 	}
+	if p, ok := process.Load(ctx.ConnectionID); ok {
+		p.SetTxID(0)
+	}
 	return exist.(proto.Tx), true
 }
 
@@ -377,21 +501,42 @@ func (executor *RedirectExecutor) getTx(ctx *proto.Context) (proto.Tx, bool) {
 	return exist.(proto.Tx), true
 }
 
-func (executor *RedirectExecutor) doPreFilter(ctx *proto.Context) {
+// executeUnparsed executes the statement which is not supported by parser between the pre-filters and the post-filters.
+func (executor *RedirectExecutor) executeUnparsed(ctx *proto.Context, execute func() (proto.Result, uint16, error)) (proto.Result, uint16, error) {
+	if err := executor.doPreFilter(ctx); err != nil {
+		executor.doPostFilter(ctx, nil, err)
+		return nil, 0, err
+	}
+	res, warn, err := execute()
+	res = executor.doPostFilter(ctx, res, err)
+	return res, warn, err
+}
+
+// doPreFilter executes the pre-filters in order, the statement will be rejected if any filter fails.
+func (executor *RedirectExecutor) doPreFilter(ctx *proto.Context) error {
+	// the statements rejected by pre-filters are measured from here too
+	ctx.Context = rcontext.WithStartTime(ctx.Context, time.Now())
 	for i := 0; i < len(executor.preFilters); i++ {
-		func(ctx *proto.Context) {
-			defer func() {
-				if err := recover(); err != nil {
-					log.Errorf("failed to execute filter: %s, err: %v", executor.preFilters[i].GetName(), err)
-				}
-			}()
-			filter := executor.preFilters[i]
-			filter.PreHandle(ctx)
-		}(ctx)
+		if err := executor.preHandle(ctx, executor.preFilters[i]); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (executor *RedirectExecutor) doPostFilter(ctx *proto.Context, result proto.Result) {
+func (executor *RedirectExecutor) preHandle(ctx *proto.Context, filter proto.PreFilter) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Errorf("failed to execute filter: %s, err: %v", filter.GetName(), rec)
+			err = mysqlErrors.NewSQLError(mConstants.ERUnknownError, mConstants.SSUnknownSQLState,
+				"failed to execute filter '%s'", filter.GetName())
+		}
+	}()
+	return filter.PreHandle(ctx)
+}
+
+// doPostFilter executes the post-filters in order, and returns the result which may be wrapped by filters.
+func (executor *RedirectExecutor) doPostFilter(ctx *proto.Context, result proto.Result, failure error) proto.Result {
 	for i := 0; i < len(executor.postFilters); i++ {
 		func(ctx *proto.Context) {
 			defer func() {
@@ -400,7 +545,10 @@ func (executor *RedirectExecutor) doPostFilter(ctx *proto.Context, result proto.
 				}
 			}()
 			filter := executor.postFilters[i]
-			filter.PostHandle(ctx, result)
+			if next := filter.PostHandle(ctx, result, failure); next != nil {
+				result = next
+			}
 		}(ctx)
 	}
+	return result
 }
@@ -28,7 +28,9 @@ import (
 )
 
 import (
+	"github.com/arana-db/arana/pkg/dataset"
 	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
 )
 
 func TestIsErrMissingTx(t *testing.T) {
@@ -36,6 +38,28 @@ func TestIsErrMissingTx(t *testing.T) {
 	assert.True(t, IsErrMissingTx(err))
 }
 
+func TestReleaseOnClose(t *testing.T) {
+	var released int
+	release := func() {
+		released++
+	}
+
+	// released at once if failed
+	_, _, err := releaseOnClose(nil, 0, errors.New("failed"), release)
+	assert.Error(t, err)
+	assert.Equal(t, 1, released)
+
+	// held until the dataset is closed
+	res, _, err := releaseOnClose(resultx.New(resultx.WithDataset(&dataset.VirtualDataset{})), 0, nil, release)
+	assert.NoError(t, err)
+	ds, err := res.Dataset()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.NoError(t, ds.Close())
+	assert.NoError(t, ds.Close())
+	assert.Equal(t, 2, released)
+}
+
 func TestAddPreFilter(t *testing.T) {
 	redirect := NewRedirectExecutor()
 	redirect.AddPreFilter(&PreFilterTest{})
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/arana-db/parser/ast"
+	"github.com/arana-db/parser/model"
+)
+
+import (
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// _systemSchemas are the schemas which are provided by the backend databases.
+var _systemSchemas = map[string]struct{}{
+	"information_schema": {},
+	"mysql":              {},
+	"performance_schema": {},
+	"sys":                {},
+}
+
+// checkSchema returns the cluster of tenant which matches the schema, the schema names are case-insensitive.
+// ER_DBACCESS_DENIED will be returned if the schema doesn't belong to the tenant.
+func checkSchema(tenant, schema string) (string, error) {
+	for _, it := range security.DefaultTenantManager().GetClusters(tenant) {
+		if strings.EqualFold(it, schema) {
+			return it, nil
+		}
+	}
+	return "", mysqlErrors.NewSQLError(mConstants.ERDBAccessDenied, mConstants.SSDBAccessDenied,
+		"Access denied for tenant '%s' to database '%s'", tenant, schema)
+}
+
+// resolveSchema resolves the schema which the statement should be executed in.
+// The tables qualified by schema are allowed to be accessed across the clusters of tenant, but all of them
+// should belong to the same one, and the qualifiers will be removed since they are logical names.
+// If neither qualifier nor current schema exists, the only cluster of tenant will be returned with schemaless flag,
+// ER_NO_DB_ERROR will be returned if the tenant owns several clusters.
+func resolveSchema(ctx *proto.Context, stmt ast.StmtNode) (schema string, schemaless bool, err error) {
+	var collector schemaCollector
+	stmt.Accept(&collector)
+
+	var target string
+	for _, it := range collector.tables {
+		name := it.Schema.O
+		if len(name) < 1 {
+			continue
+		}
+		if _, ok := _systemSchemas[strings.ToLower(name)]; ok {
+			continue
+		}
+		if name, err = checkSchema(ctx.Tenant, name); err != nil {
+			return
+		}
+		if len(target) > 0 && target != name {
+			err = mysqlErrors.NewSQLError(mConstants.ERNotSupportedYet, mConstants.SSUnknownSQLState,
+				"cross-database access is not supported: '%s' and '%s'", target, name)
+			return
+		}
+		target = name
+	}
+
+	if len(target) < 1 {
+		if len(ctx.Schema) > 0 {
+			return ctx.Schema, false, nil
+		}
+		// never guess the schema if the tenant owns several clusters
+		clusters := security.DefaultTenantManager().GetClusters(ctx.Tenant)
+		if len(clusters) != 1 {
+			return "", true, errNoDatabaseSelected
+		}
+		return clusters[0], true, nil
+	}
+
+	// the unqualified tables belong to current schema
+	if collector.unqualified && !strings.EqualFold(target, ctx.Schema) {
+		if len(ctx.Schema) < 1 {
+			return "", false, errNoDatabaseSelected
+		}
+		err = mysqlErrors.NewSQLError(mConstants.ERNotSupportedYet, mConstants.SSUnknownSQLState,
+			"cross-database access is not supported: '%s' and '%s'", ctx.Schema, target)
+		return "", false, err
+	}
+
+	for _, it := range collector.tables {
+		if strings.EqualFold(it.Schema.O, target) {
+			it.Schema = model.CIStr{}
+		}
+	}
+	for _, it := range collector.columns {
+		if strings.EqualFold(it.Schema.O, target) {
+			it.Schema = model.CIStr{}
+		}
+	}
+
+	return target, false, nil
+}
+
+// schemaCollector collects the table names and column names in the statement.
+type schemaCollector struct {
+	tables      []*ast.TableName
+	columns     []*ast.ColumnName
+	unqualified bool // true if any table is not qualified by schema
+}
+
+func (sc *schemaCollector) Enter(n ast.Node) (ast.Node, bool) {
+	switch t := n.(type) {
+	case *ast.TableName:
+		sc.tables = append(sc.tables, t)
+		if len(t.Schema.O) < 1 {
+			sc.unqualified = true
+		}
+	case *ast.ColumnName:
+		if len(t.Schema.O) > 0 {
+			sc.columns = append(sc.columns, t)
+		}
+	}
+	return n, false
+}
+
+func (sc *schemaCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
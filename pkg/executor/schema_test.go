@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+	"github.com/arana-db/parser/format"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+func TestCheckSchema(t *testing.T) {
+	security.DefaultTenantManager().PutCluster("fake-tenant-1", "employees")
+	defer security.DefaultTenantManager().RemoveCluster("fake-tenant-1", "employees")
+
+	schema, err := checkSchema("fake-tenant-1", "employees")
+	assert.NoError(t, err)
+	assert.Equal(t, "employees", schema)
+
+	// the schema names are case-insensitive
+	schema, err = checkSchema("fake-tenant-1", "EMPLOYEES")
+	assert.NoError(t, err)
+	assert.Equal(t, "employees", schema)
+
+	_, err = checkSchema("fake-tenant-1", "salaries")
+	assert.Error(t, err)
+	assert.Equal(t, mConstants.ERDBAccessDenied, err.(*mysqlErrors.SQLError).Num)
+}
+
+func TestResolveSchema(t *testing.T) {
+	const tenant = "fake-tenant-2"
+	for _, it := range []string{"employees", "salaries"} {
+		security.DefaultTenantManager().PutCluster(tenant, it)
+		defer security.DefaultTenantManager().RemoveCluster(tenant, it)
+	}
+
+	type tt struct {
+		schema     string
+		sql        string
+		expect     string
+		schemaless bool
+		restored   string
+		errno      int
+	}
+
+	for _, it := range []tt{
+		{"", "select 1", "", true, "", mConstants.ERNoDb},
+		{"salaries", "select * from student", "salaries", false, "SELECT * FROM `student`", 0},
+		{"", "select employees.student.uid from employees.student", "employees", false, "SELECT `student`.`uid` FROM `student`", 0},
+		{"employees", "select * from salaries.salary where uid in (select uid from information_schema.tables)", "salaries", false,
+			"SELECT * FROM `salary` WHERE `uid` IN (SELECT `uid` FROM `information_schema`.`tables`)", 0},
+		{"employees", "select * from student join salaries.salary", "", false, "", mConstants.ERNotSupportedYet},
+		{"", "select * from employees.student a join salaries.salary b", "", false, "", mConstants.ERNotSupportedYet},
+		{"", "select * from student join employees.salary", "", false, "", mConstants.ERNoDb},
+		{"employees", "select * from other.student", "", false, "", mConstants.ERDBAccessDenied},
+		{"", "select * from EMPLOYEES.student a join employees.salary b", "employees", false, "SELECT * FROM `student` AS `a` JOIN `salary` AS `b`", 0},
+		{"Employees", "select * from student join EMPLOYEES.salary", "employees", false, "SELECT * FROM `student` JOIN `salary`", 0},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(it.sql, "", "")
+			assert.NoError(t, err)
+
+			ctx := &proto.Context{Tenant: tenant, Schema: it.schema}
+			schema, schemaless, err := resolveSchema(ctx, stmt)
+			if it.errno != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, it.errno, err.(*mysqlErrors.SQLError).Num)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, it.expect, schema)
+			assert.Equal(t, it.schemaless, schemaless)
+
+			var sb strings.Builder
+			assert.NoError(t, stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)))
+			assert.Equal(t, it.restored, sb.String())
+		})
+	}
+
+	// the only cluster of tenant will be used if no schema specified
+	security.DefaultTenantManager().PutCluster("fake-tenant-3", "employees")
+	defer security.DefaultTenantManager().RemoveCluster("fake-tenant-3", "employees")
+
+	stmt, err := parser.New().ParseOneStmt("select 1", "", "")
+	assert.NoError(t, err)
+	schema, schemaless, err := resolveSchema(&proto.Context{Tenant: "fake-tenant-3"}, stmt)
+	assert.NoError(t, err)
+	assert.Equal(t, "employees", schema)
+	assert.True(t, schemaless)
+}
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/arana-db/parser/ast"
+	"github.com/arana-db/parser/format"
+
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/mysql"
+)
+
+// _untrackedVariables are the session variables which should not be replayed on other backend connections.
+var _untrackedVariables = map[string]struct{}{
+	"autocommit":            {}, // transactions are managed by proxy
+	"tx_isolation_one_shot": {}, // only affects the next transaction
+}
+
+// applySetStmt returns the session variables after executing the SET statement, the origin variables won't be changed.
+func applySetStmt(variables map[string]string, stmt *ast.SetStmt) (map[string]string, error) {
+	ret := make(map[string]string, len(variables)+len(stmt.Variables))
+	for k, v := range variables {
+		ret[k] = v
+	}
+
+	restore := func(node ast.Node) (string, error) {
+		var sb strings.Builder
+		if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags|format.RestoreStringWithoutCharset, &sb)); err != nil {
+			return "", errors.WithStack(err)
+		}
+		return sb.String(), nil
+	}
+
+	for _, it := range stmt.Variables {
+		var name string
+		switch {
+		case it.Name == ast.SetNames:
+			name = mysql.VariableNames
+		case it.Name == ast.SetCharset:
+			name = mysql.VariableCharset
+		case it.IsSystem && !it.IsGlobal:
+			name = strings.ToLower(it.Name)
+		default: // user variables and global variables
+			continue
+		}
+
+		if _, ok := _untrackedVariables[name]; ok {
+			continue
+		}
+
+		if _, ok := it.Value.(*ast.DefaultExpr); ok {
+			delete(ret, name)
+			continue
+		}
+
+		value, err := restore(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		if it.ExtendValue != nil {
+			var collation string
+			if collation, err = restore(it.ExtendValue); err != nil {
+				return nil, err
+			}
+			value += " COLLATE " + collation
+		}
+		ret[name] = value
+	}
+
+	return ret, nil
+}
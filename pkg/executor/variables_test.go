@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+	"github.com/arana-db/parser/ast"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySetStmt(t *testing.T) {
+	variables := map[string]string{
+		"sql_mode": "''",
+	}
+
+	for _, it := range []struct {
+		sql    string
+		expect map[string]string
+	}{
+		{
+			"SET NAMES utf8mb4",
+			map[string]string{"names": "'utf8mb4'", "sql_mode": "''"},
+		},
+		{
+			"SET NAMES 'utf8mb4' COLLATE 'utf8mb4_bin', time_zone = '+08:00', @a = 1",
+			map[string]string{"names": "'utf8mb4' COLLATE 'utf8mb4_bin'", "time_zone": "_UTF8MB4'+08:00'", "sql_mode": "''"},
+		},
+		{
+			"SET @@SESSION.sql_mode = DEFAULT, @@GLOBAL.wait_timeout = 10, autocommit = 0",
+			map[string]string{},
+		},
+		{
+			"SET CHARACTER SET gbk, SESSION Wait_Timeout = 10*2",
+			map[string]string{"charset": "'gbk'", "wait_timeout": "10*2", "sql_mode": "''"},
+		},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(it.sql, "", "")
+			assert.NoError(t, err)
+
+			actual, err := applySetStmt(variables, stmt.(*ast.SetStmt))
+			assert.NoError(t, err)
+			assert.Equal(t, it.expect, actual)
+		})
+	}
+
+	// the origin variables should not be changed
+	assert.Equal(t, map[string]string{"sql_mode": "''"}, variables)
+}
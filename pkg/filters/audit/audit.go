@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/arana-db/parser"
+
+	"github.com/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+import (
+	filter "github.com/arana-db/arana/pkg/filters"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+// Name is the name of audit filter.
+const Name = "audit"
+
+const _redacted = "***"
+
+var (
+	_ proto.PostFilter    = (*Filter)(nil)
+	_ proto.FilterFactory = (*factory)(nil)
+)
+
+func init() {
+	filter.RegistryFilterFactory(Name, &factory{})
+}
+
+// Config is the configuration of audit filter.
+type Config struct {
+	log.RotateConfig
+	// SampleRate is the ratio of successful statements to be recorded, all statements will be recorded if it is not in (0,1).
+	// Failed statements are always recorded.
+	SampleRate float64 `json:"sample_rate,omitempty"`
+	// RedactArgs replaces the bind args with '***'.
+	RedactArgs bool `json:"redact_args,omitempty"`
+	// Tenants is the switches of tenants, the tenants which are not in the switches follow DisableByDefault.
+	Tenants map[string]bool `json:"tenants,omitempty"`
+	// DisableByDefault disables the audit log of tenants which are not in the switches.
+	DisableByDefault bool `json:"disable_by_default,omitempty"`
+}
+
+type factory struct{}
+
+func (f *factory) NewFilter(config json.RawMessage) (proto.Filter, error) {
+	var c Config
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &c); err != nil {
+			return nil, errors.Wrap(err, "failed to parse audit config")
+		}
+	}
+	if len(c.Filename) < 1 {
+		return nil, errors.New("the filename of audit log is required")
+	}
+	return New(&c, log.NewRotateJSONLogger(c.RotateConfig)), nil
+}
+
+// Filter records the statements as JSON lines.
+type Filter struct {
+	c      Config
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	rand *rand.Rand // for sampling, rand.Rand is not safe for concurrent use
+}
+
+// New creates an audit filter which writes records to the logger.
+func New(c *Config, logger *zap.Logger) *Filter {
+	return &Filter{
+		c:      *c,
+		logger: logger,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (f *Filter) GetName() string {
+	return Name
+}
+
+func (f *Filter) PostHandle(ctx *proto.Context, result proto.Result, err error) proto.Result {
+	if !f.enabled(ctx.Tenant) || !f.sample(err) {
+		return result
+	}
+
+	fingerprint, digest := parser.NormalizeDigest(ctx.GetQuery())
+
+	fields := []zap.Field{
+		zap.Uint32("connection_id", ctx.ConnectionID),
+		zap.String("tenant", ctx.Tenant),
+		zap.String("user", ctx.Username),
+		zap.String("client", ctx.RemoteAddr),
+		zap.String("schema", ctx.Schema),
+		zap.String("fingerprint", fingerprint),
+		zap.String("digest", digest.String()),
+	}
+
+	if args := ctx.GetArgs(); len(args) > 0 {
+		fields = append(fields, zap.Any("args", f.args(args)))
+	}
+
+	// the start time is bound before all pre-filters, so the statements rejected by them are measured too
+	start := rcontext.StartTime(ctx.Context)
+
+	if err != nil || result == nil {
+		if err != nil {
+			fields = append(fields, zap.String("error", err.Error()))
+		}
+		f.record(start, fields)
+		return result
+	}
+
+	if affected, err := result.RowsAffected(); err == nil {
+		fields = append(fields, zap.Uint64("affected_rows", affected))
+	}
+
+	// the record is written after the rows are sent to client, so the duration covers the whole statement
+	return resultx.OnClose(result, func() {
+		f.record(start, fields)
+	})
+}
+
+func (f *Filter) record(start time.Time, fields []zap.Field) {
+	if !start.IsZero() {
+		fields = append(fields, log.Duration(time.Since(start)))
+	}
+	f.logger.Info(Name, fields...)
+}
+
+func (f *Filter) enabled(tenant string) bool {
+	if enabled, ok := f.c.Tenants[tenant]; ok {
+		return enabled
+	}
+	return !f.c.DisableByDefault
+}
+
+func (f *Filter) sample(err error) bool {
+	if err != nil || f.c.SampleRate <= 0 || f.c.SampleRate >= 1 {
+		return true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rand.Float64() < f.c.SampleRate
+}
+
+func (f *Filter) args(args []interface{}) []interface{} {
+	ret := make([]interface{}, 0, len(args))
+	for _, it := range args {
+		if f.c.RedactArgs {
+			ret = append(ret, _redacted)
+			continue
+		}
+		// avoid encoding bytes as base64
+		if b, ok := it.([]byte); ok {
+			it = string(b)
+		}
+		ret = append(ret, it)
+	}
+	return ret
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/arana-db/parser"
+	_ "github.com/arana-db/parser/test_driver"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+)
+
+func TestAudit(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	f, err := (&factory{}).NewFilter([]byte(`{"filename":"` + filename + `","redact_args":true,"tenants":{"hidden":false}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, Name, f.GetName())
+
+	_, err = (&factory{}).NewFilter([]byte(`{}`))
+	assert.Error(t, err)
+
+	execute := func(tenant, sql string, args map[string]interface{}, res proto.Result, failure error) proto.Result {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		ctx := &proto.Context{
+			Context:      rcontext.WithStartTime(context.Background(), time.Now()),
+			Tenant:       tenant,
+			Schema:       "employees",
+			ConnectionID: 1,
+			Username:     "root",
+			RemoteAddr:   "127.0.0.1:54321",
+			Stmt:         &proto.Stmt{StmtNode: stmt, BindVars: args},
+		}
+		return f.(proto.PostFilter).PostHandle(ctx, res, failure)
+	}
+
+	load := func() []map[string]interface{} {
+		_ = f.(*Filter).logger.Sync()
+
+		file, err := os.Open(filename)
+		assert.NoError(t, err)
+		defer file.Close()
+
+		var records []map[string]interface{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record map[string]interface{}
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+
+	resultx.Drain(execute("arana", "update student set name = 'foo' where uid = 1", nil, resultx.New(resultx.WithRowsAffected(3)), nil))
+
+	// the query is recorded after the dataset is closed
+	res := execute("arana", "select * from student where uid = ?", map[string]interface{}{"v1": int64(1)},
+		resultx.New(resultx.WithDataset(&dataset.VirtualDataset{})), nil)
+	ds, err := res.Dataset()
+	assert.NoError(t, err)
+	assert.Len(t, load(), 1)
+	assert.NoError(t, ds.Close())
+	assert.Len(t, load(), 2)
+
+	execute("arana", "delete from student", nil, nil, errors.New("blocked"))
+	resultx.Drain(execute("hidden", "select 1", nil, resultx.New(), nil))
+
+	records := load()
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, "arana", records[0]["tenant"])
+	assert.Equal(t, "root", records[0]["user"])
+	assert.Equal(t, "127.0.0.1:54321", records[0]["client"])
+	assert.Equal(t, "employees", records[0]["schema"])
+	assert.Equal(t, "update `student` set `name` = ? where `uid` = ?", records[0]["fingerprint"])
+	assert.EqualValues(t, 3, records[0]["affected_rows"])
+	assert.Contains(t, records[0], "duration_ms")
+
+	assert.Equal(t, []interface{}{"***"}, records[1]["args"])
+
+	assert.Equal(t, "blocked", records[2]["error"])
+	assert.NotContains(t, records[2], "affected_rows")
+	assert.Contains(t, records[2], "duration_ms")
+}
+
+func TestAudit_Sample(t *testing.T) {
+	f := New(&Config{SampleRate: 0.000001}, nil)
+	assert.True(t, f.sample(errors.New("failed")))
+
+	f = New(&Config{}, nil)
+	assert.True(t, f.sample(nil))
+}
@@ -0,0 +1,201 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package firewall
+
+import (
+	"encoding/json"
+)
+
+import (
+	"github.com/arana-db/parser/ast"
+
+	"github.com/pkg/errors"
+)
+
+import (
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	filter "github.com/arana-db/arana/pkg/filters"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/util/fingerprint"
+)
+
+// Name is the name of firewall filter.
+const Name = "firewall"
+
+var (
+	_ proto.PreFilter     = (*Filter)(nil)
+	_ proto.FilterFactory = (*factory)(nil)
+)
+
+func init() {
+	filter.RegistryFilterFactory(Name, &factory{})
+}
+
+type (
+	// Config is the configuration of firewall filter.
+	Config struct {
+		Rules []*Rule `json:"rules"`
+	}
+
+	// Rule is the firewall rule of a tenant.
+	Rule struct {
+		// Tenant is the tenant which the rule applies to, the rule applies to all other tenants if empty.
+		Tenant string `json:"tenant,omitempty"`
+		// AllowList is the fingerprints of allowed DML statements, all statements will be allowed if empty.
+		AllowList []string `json:"allow_list,omitempty"`
+		// DenyList is the fingerprints of denied DML statements.
+		DenyList []string `json:"deny_list,omitempty"`
+		// DenyNoWhere denies DELETE/UPDATE without WHERE.
+		DenyNoWhere bool `json:"deny_no_where,omitempty"`
+		// DenyFullScan denies full-scan on sharding tables, even if it is allowed by the sharding rule.
+		DenyFullScan bool `json:"deny_full_scan,omitempty"`
+	}
+)
+
+type factory struct{}
+
+func (f *factory) NewFilter(config json.RawMessage) (proto.Filter, error) {
+	var c Config
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &c); err != nil {
+			return nil, errors.Wrap(err, "failed to parse firewall config")
+		}
+	}
+	return New(&c), nil
+}
+
+// Filter is a SQL firewall which rejects statements before execution.
+type Filter struct {
+	rules map[string]*rule // tenant -> rule
+}
+
+// New creates a firewall filter.
+func New(c *Config) *Filter {
+	f := &Filter{
+		rules: make(map[string]*rule, len(c.Rules)),
+	}
+	for _, it := range c.Rules {
+		f.rules[it.Tenant] = newRule(it)
+	}
+	return f
+}
+
+func (f *Filter) GetName() string {
+	return Name
+}
+
+func (f *Filter) PreHandle(ctx *proto.Context) error {
+	if ctx.Stmt == nil || ctx.Stmt.StmtNode == nil {
+		return nil
+	}
+
+	r, ok := f.rules[ctx.Tenant]
+	if !ok {
+		if r, ok = f.rules[""]; !ok {
+			return nil
+		}
+	}
+
+	stmt := ctx.Stmt.StmtNode
+	if _, ok := stmt.(ast.DMLNode); ok {
+		if err := r.checkFingerprint(stmt.Text()); err != nil {
+			return err
+		}
+	}
+
+	switch stmt := stmt.(type) {
+	case *ast.DeleteStmt:
+		if r.denyNoWhere && stmt.Where == nil {
+			return errNoWhere("DELETE")
+		}
+	case *ast.UpdateStmt:
+		if r.denyNoWhere && stmt.Where == nil {
+			return errNoWhere("UPDATE")
+		}
+	case *ast.SelectStmt:
+	default:
+		return nil
+	}
+
+	if r.denyFullScan {
+		ctx.Context = rcontext.WithDenyFullScan(ctx.Context)
+	}
+
+	return nil
+}
+
+type rule struct {
+	allows, denies fingerprints
+	denyNoWhere    bool
+	denyFullScan   bool
+}
+
+func newRule(c *Rule) *rule {
+	return &rule{
+		allows:       newFingerprints(c.AllowList),
+		denies:       newFingerprints(c.DenyList),
+		denyNoWhere:  c.DenyNoWhere,
+		denyFullScan: c.DenyFullScan,
+	}
+}
+
+func (r *rule) checkFingerprint(sql string) error {
+	if len(r.allows) < 1 && len(r.denies) < 1 {
+		return nil
+	}
+
+	normalized, digest := fingerprint.Of(sql)
+	if r.denies.contains(normalized, digest) {
+		return errBlocked(normalized)
+	}
+	if len(r.allows) > 0 && !r.allows.contains(normalized, digest) {
+		return errBlocked(normalized)
+	}
+	return nil
+}
+
+// fingerprints is a set of normalized SQL or digests of normalized SQL.
+type fingerprints map[string]struct{}
+
+func newFingerprints(items []string) fingerprints {
+	ret := make(fingerprints, len(items))
+	for _, it := range items {
+		ret[fingerprint.Key(it)] = struct{}{}
+	}
+	return ret
+}
+
+func (fp fingerprints) contains(normalized, digest string) bool {
+	if _, ok := fp[normalized]; ok {
+		return true
+	}
+	_, ok := fp[digest]
+	return ok
+}
+
+func errBlocked(fingerprint string) error {
+	return mysqlErrors.NewSQLError(mConstants.ERSpecifiedAccessDenied, mConstants.SSDBAccessDenied,
+		"Access denied; statement '%s' is blocked by firewall", fingerprint)
+}
+
+func errNoWhere(action string) error {
+	return mysqlErrors.NewSQLError(mConstants.ERUpdateWithoutKeyInSafeMode, mConstants.SSUnknownSQLState,
+		"%s without WHERE is blocked by firewall", action)
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package firewall
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+	_ "github.com/arana-db/parser/test_driver"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+)
+
+func TestFirewall(t *testing.T) {
+	_, digest := parser.NormalizeDigest("select name from student where uid = 1")
+
+	f, err := (&factory{}).NewFilter([]byte(`{
+  "rules": [
+    {
+      "tenant": "arana",
+      "deny_list": ["delete from student where uid = 1"],
+      "deny_no_where": true,
+      "deny_full_scan": true
+    },
+    {
+      "tenant": "strict",
+      "allow_list": ["SELECT * FROM student WHERE uid = ?", "` + digest.String() + `"]
+    }
+  ]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, Name, f.GetName())
+
+	handle := func(tenant, sql string) (*proto.Context, error) {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		ctx := &proto.Context{
+			Context: context.Background(),
+			Tenant:  tenant,
+			Stmt:    &proto.Stmt{StmtNode: stmt},
+		}
+		return ctx, f.(proto.PreFilter).PreHandle(ctx)
+	}
+
+	checkErr := func(err error, num int) {
+		var sqlErr *mysqlErrors.SQLError
+		assert.ErrorAs(t, err, &sqlErr)
+		assert.Equal(t, num, sqlErr.Num)
+	}
+
+	t.Run("deny list", func(t *testing.T) {
+		_, err := handle("arana", "DELETE FROM student WHERE uid = 42")
+		checkErr(err, mConstants.ERSpecifiedAccessDenied)
+		_, err = handle("arana", "DELETE FROM student WHERE name = 'foo'")
+		assert.NoError(t, err)
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		_, err := handle("strict", "select * from student where uid = 1")
+		assert.NoError(t, err)
+		_, err = handle("strict", "select name from student where uid = 2")
+		assert.NoError(t, err)
+		_, err = handle("strict", "select * from student")
+		checkErr(err, mConstants.ERSpecifiedAccessDenied)
+		_, err = handle("strict", "set names utf8mb4")
+		assert.NoError(t, err)
+	})
+
+	t.Run("no where", func(t *testing.T) {
+		_, err := handle("arana", "delete from student")
+		checkErr(err, mConstants.ERUpdateWithoutKeyInSafeMode)
+		_, err = handle("arana", "update student set name = 'foo'")
+		checkErr(err, mConstants.ERUpdateWithoutKeyInSafeMode)
+		_, err = handle("other", "delete from student")
+		assert.NoError(t, err)
+	})
+
+	t.Run("full scan", func(t *testing.T) {
+		ctx, err := handle("arana", "select * from student where name = 'foo'")
+		assert.NoError(t, err)
+		assert.True(t, rcontext.IsDenyFullScan(ctx.Context))
+
+		ctx, err = handle("arana", "insert into student(uid) values(1)")
+		assert.NoError(t, err)
+		assert.False(t, rcontext.IsDenyFullScan(ctx.Context))
+
+		ctx, err = handle("strict", "select * from student where uid = 1")
+		assert.NoError(t, err)
+		assert.False(t, rcontext.IsDenyFullScan(ctx.Context))
+	})
+}
@@ -0,0 +1,432 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/metrics"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+const (
+	_defaultInterval = 5 * time.Second
+	_defaultTimeout  = 3 * time.Second
+	_defaultFall     = 3
+	_defaultRise     = 2
+)
+
+// Config represents the config of backend health checking.
+type Config struct {
+	// Interval is the interval between two checks, default is 5s.
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// Timeout is the timeout of each check, default is 3s.
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+	// Fall is the count of consecutive failures to eject a node, default is 3.
+	Fall int `yaml:"fall" json:"fall"`
+	// Rise is the count of consecutive successes to restore an ejected node, default is 2.
+	Rise int `yaml:"rise" json:"rise"`
+	// MaxReplicationLag ejects the slave whose replication lag exceeds it, zero means no limit.
+	MaxReplicationLag time.Duration `yaml:"max_replication_lag" json:"max_replication_lag"`
+	// Failover detects the promotion of primary by polling read_only/super_read_only of each node, and switches
+	// the master of group to the only writable node automatically.
+	Failover bool `yaml:"failover" json:"failover"`
+}
+
+// Checker pings the backend nodes of all namespaces periodically, it ejects the unhealthy nodes from the
+// selection of reads, and restores them when they become healthy again. The weights of nodes are never changed.
+type Checker struct {
+	c Config
+
+	mu     sync.Mutex
+	states map[proto.DB]*nodeState
+}
+
+type nodeState struct {
+	schema, group string
+	failures      int
+	successes     int
+	checking      bool
+	ejected       bool
+	writable      bool // true if both read_only and super_read_only are off
+}
+
+// Start creates a health checker and runs it in background.
+func Start(c *Config) *Checker {
+	hc := NewChecker(c)
+	go hc.Run(context.Background())
+	return hc
+}
+
+// NewChecker creates a health checker.
+func NewChecker(c *Config) *Checker {
+	var conf Config
+	if c != nil {
+		conf = *c
+	}
+	if conf.Interval <= 0 {
+		conf.Interval = _defaultInterval
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = _defaultTimeout
+	}
+	if conf.Fall <= 0 {
+		conf.Fall = _defaultFall
+	}
+	if conf.Rise <= 0 {
+		conf.Rise = _defaultRise
+	}
+	return &Checker{
+		c:      conf,
+		states: make(map[proto.DB]*nodeState),
+	}
+}
+
+// Run checks all backend nodes periodically until the context is done.
+func (hc *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(hc.c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hc.CheckOnce(ctx)
+		}
+	}
+}
+
+// CheckOnce checks all backend nodes once, and waits until all checks are finished or timeout.
+func (hc *Checker) CheckOnce(ctx context.Context) {
+	type nsGroup struct {
+		ns    *namespace.Namespace
+		group string
+	}
+
+	var (
+		wg     sync.WaitGroup
+		alive  = make(map[proto.DB]struct{})
+		groups []nsGroup
+	)
+
+	namespace.Range(func(ns *namespace.Namespace) bool {
+		for _, group := range ns.DBGroups() {
+			groups = append(groups, nsGroup{ns: ns, group: group})
+			for _, db := range ns.DBs(group) {
+				alive[db] = struct{}{}
+				wg.Add(1)
+				go func(ns *namespace.Namespace, group string, db proto.DB) {
+					defer wg.Done()
+					hc.checkNode(ctx, ns, group, db)
+				}(ns, group, db)
+			}
+		}
+		return true
+	})
+
+	wg.Wait()
+
+	if hc.c.Failover {
+		for _, it := range groups {
+			hc.elect(it.ns, it.group)
+		}
+	}
+
+	// cleanup the states of removed nodes
+	hc.mu.Lock()
+	for db := range hc.states {
+		if _, ok := alive[db]; !ok {
+			delete(hc.states, db)
+		}
+	}
+	hc.mu.Unlock()
+}
+
+func (hc *Checker) checkNode(ctx context.Context, ns *namespace.Namespace, group string, db proto.DB) {
+	hc.mu.Lock()
+	st, ok := hc.states[db]
+	if !ok {
+		st = &nodeState{
+			schema: ns.Name(),
+			group:  group,
+		}
+		hc.states[db] = st
+	}
+	checking := st.checking
+	st.checking = true
+	hc.mu.Unlock()
+
+	type result struct {
+		writable bool
+		err      error
+	}
+
+	var res result
+	if checking {
+		// the previous check is still hanging
+		res.err = errors.New("the previous health check is not finished yet")
+	} else {
+		done := make(chan result, 1)
+		go func() {
+			c, cancel := context.WithTimeout(ctx, hc.c.Timeout)
+			defer cancel()
+
+			writable, err := hc.check(c, db, ns.Weight(db).W == 0)
+
+			hc.mu.Lock()
+			st.checking = false
+			hc.mu.Unlock()
+
+			done <- result{writable: writable, err: err}
+		}()
+
+		select {
+		case res = <-done:
+		case <-time.After(hc.c.Timeout):
+			res.err = errors.Errorf("health check timeout after %s", hc.c.Timeout)
+		}
+	}
+
+	hc.report(ns, group, db, st, res.writable, res.err)
+}
+
+// check pings the node, and checks the replication status if it is a slave.
+// The writable flag is detected only if failover is enabled.
+func (hc *Checker) check(ctx context.Context, db proto.DB, slave bool) (writable bool, err error) {
+	if _, err = query(ctx, db, "SELECT 1"); err != nil {
+		return
+	}
+
+	if hc.c.Failover {
+		if writable, err = isWritable(ctx, db); err != nil {
+			return
+		}
+	}
+
+	if !slave || hc.c.MaxReplicationLag <= 0 {
+		return
+	}
+
+	err = checkReplication(ctx, db, hc.c.MaxReplicationLag)
+	return
+}
+
+// isWritable returns true if both read_only and super_read_only of the node are off.
+func isWritable(ctx context.Context, db proto.DB) (bool, error) {
+	rows, err := query(ctx, db, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('read_only', 'super_read_only')")
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		switch strings.ToUpper(row["Value"]) {
+		case "OFF", "0":
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// checkReplication checks the replication status and lag of a slave.
+func checkReplication(ctx context.Context, db proto.DB, maxLag time.Duration) error {
+	rows, err := query(ctx, db, "SHOW SLAVE STATUS")
+	if err != nil {
+		return err
+	}
+
+	// not a slave actually
+	if len(rows) < 1 {
+		return nil
+	}
+
+	status := rows[0]
+	for _, it := range []string{"Slave_IO_Running", "Slave_SQL_Running"} {
+		if !strings.EqualFold(status[it], "Yes") {
+			return errors.Errorf("replication is not running: %s=%s", it, status[it])
+		}
+	}
+
+	lag, err := strconv.ParseInt(status["Seconds_Behind_Master"], 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid replication lag '%s'", status["Seconds_Behind_Master"])
+	}
+	if d := time.Duration(lag) * time.Second; d > maxLag {
+		return errors.Errorf("replication lag %s exceeds %s", d, maxLag)
+	}
+
+	return nil
+}
+
+// elect switches the master of group if the current master is not writable any more, and there is exactly one
+// healthy node which is writable. Nothing will be changed if the roles are ambiguous, eg: split brain.
+func (hc *Checker) elect(ns *namespace.Namespace, group string) {
+	if ns.MasterPinned(group) {
+		return
+	}
+
+	current := ns.DBMaster(context.Background(), group)
+
+	var writable []proto.DB
+
+	hc.mu.Lock()
+	for _, db := range ns.DBs(group) {
+		if st, ok := hc.states[db]; ok && st.failures == 0 && st.successes > 0 && st.writable {
+			writable = append(writable, db)
+		}
+	}
+	hc.mu.Unlock()
+
+	for _, db := range writable {
+		if db == current {
+			return
+		}
+	}
+
+	switch len(writable) {
+	case 0:
+		return
+	case 1:
+	default:
+		log.Warnf("[%s] skip switching master of group %s: %d writable datasources found", ns.Name(), group, len(writable))
+		return
+	}
+
+	next := writable[0].ID()
+	log.Warnf("[%s] primary of group %s has been changed to %s", ns.Name(), group, next)
+	if err := ns.EnqueueCommand(namespace.SwitchMaster(group, next)); err != nil {
+		log.Errorf("[%s] failed to switch master of group %s to %s: %v", ns.Name(), group, next, err)
+	}
+}
+
+func (hc *Checker) report(ns *namespace.Namespace, group string, db proto.DB, st *nodeState, writable bool, err error) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	var (
+		id  = db.ID()
+		cmd namespace.Command
+	)
+
+	st.writable = writable
+
+	if err != nil {
+		st.failures++
+		st.successes = 0
+		log.Debugf("[%s] health check of %s.%s failed(%d): %v", st.schema, group, id, st.failures, err)
+
+		if !st.ejected && st.failures >= hc.c.Fall {
+			st.ejected = true
+			cmd = namespace.SetEjected(group, id, true)
+			log.Warnf("[%s] eject unhealthy datasource %s.%s: %v", st.schema, group, id, err)
+		}
+	} else {
+		st.successes++
+		st.failures = 0
+
+		if st.ejected && st.successes >= hc.c.Rise {
+			st.ejected = false
+			cmd = namespace.SetEjected(group, id, false)
+			log.Infof("[%s] restore healthy datasource %s.%s", st.schema, group, id)
+		}
+	}
+
+	healthy := 1.0
+	if st.ejected {
+		healthy = 0
+	}
+	metrics.BackendHealthy.WithLabelValues(st.schema, group, id).Set(healthy)
+
+	if cmd == nil {
+		return
+	}
+	if err := ns.EnqueueCommand(cmd); err != nil {
+		log.Errorf("[%s] failed to eject or restore datasource %s.%s: %v", st.schema, group, id, err)
+	}
+}
+
+// query executes the sql, and returns all rows as maps from column name to string value.
+func query(ctx context.Context, db proto.DB, sql string) ([]map[string]string, error) {
+	res, _, err := db.Call(ctx, sql)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ds, err := res.Dataset()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if ds == nil {
+		return nil, nil
+	}
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	fields, err := ds.Fields()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var rows []map[string]string
+	for {
+		next, err := ds.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		dest := make([]proto.Value, len(fields))
+		if err = next.Scan(dest); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		row := make(map[string]string, len(fields))
+		for i, it := range fields {
+			if dest[i] == nil {
+				row[it.Name()] = ""
+				continue
+			}
+			switch val := dest[i].(type) {
+			case []byte:
+				row[it.Name()] = string(val)
+			default:
+				row[it.Name()] = fmt.Sprint(val)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
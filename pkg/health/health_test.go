@@ -0,0 +1,299 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	consts "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+)
+
+type fakeDB struct {
+	proto.DB
+
+	id string
+
+	mu       sync.Mutex
+	weight   proto.Weight
+	down     bool
+	lag      string
+	readOnly bool
+}
+
+func (f *fakeDB) ID() string {
+	return f.id
+}
+
+func (f *fakeDB) Weight() proto.Weight {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.weight
+}
+
+func (f *fakeDB) SetWeight(weight proto.Weight) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.weight = weight
+	return nil
+}
+
+func (f *fakeDB) Close() error {
+	return nil
+}
+
+func (f *fakeDB) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeDB) setLag(lag string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lag = lag
+}
+
+func (f *fakeDB) setReadOnly(readOnly bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readOnly = readOnly
+}
+
+func (f *fakeDB) Call(_ context.Context, sql string, _ ...interface{}) (proto.Result, uint16, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down {
+		return nil, 0, errors.New("connection refused")
+	}
+
+	switch sql {
+	case "SELECT 1":
+		fields := []proto.Field{mysql.NewField("1", consts.FieldTypeLongLong)}
+		return resultx.New(resultx.WithDataset(&dataset.VirtualDataset{
+			Columns: fields,
+			Rows:    []proto.Row{rows.NewTextVirtualRow(fields, []proto.Value{int64(1)})},
+		})), 0, nil
+	case "SHOW SLAVE STATUS":
+		fields := []proto.Field{
+			mysql.NewField("Slave_IO_Running", consts.FieldTypeVarString),
+			mysql.NewField("Slave_SQL_Running", consts.FieldTypeVarString),
+			mysql.NewField("Seconds_Behind_Master", consts.FieldTypeLongLong),
+		}
+		var lag proto.Value
+		if len(f.lag) > 0 {
+			lag = f.lag
+		}
+		return resultx.New(resultx.WithDataset(&dataset.VirtualDataset{
+			Columns: fields,
+			Rows:    []proto.Row{rows.NewTextVirtualRow(fields, []proto.Value{"Yes", "Yes", lag})},
+		})), 0, nil
+	case "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('read_only', 'super_read_only')":
+		fields := []proto.Field{
+			mysql.NewField("Variable_name", consts.FieldTypeVarString),
+			mysql.NewField("Value", consts.FieldTypeVarString),
+		}
+		value := "OFF"
+		if f.readOnly {
+			value = "ON"
+		}
+		return resultx.New(resultx.WithDataset(&dataset.VirtualDataset{
+			Columns: fields,
+			Rows: []proto.Row{
+				rows.NewTextVirtualRow(fields, []proto.Value{"read_only", value}),
+				rows.NewTextVirtualRow(fields, []proto.Value{"super_read_only", value}),
+			},
+		})), 0, nil
+	}
+
+	return nil, 0, errors.Errorf("unexpected sql: %s", sql)
+}
+
+func TestChecker(t *testing.T) {
+	var (
+		master = &fakeDB{id: "master", weight: proto.Weight{R: 10, W: 10}}
+		slave  = &fakeDB{id: "slave", weight: proto.Weight{R: 10, W: 0}, lag: "0"}
+	)
+
+	ns, err := namespace.New("health_check", namespace.UpsertDB("health_0000", master), namespace.UpsertDB("health_0000", slave))
+	assert.NoError(t, err)
+	assert.NoError(t, namespace.Register(ns))
+	defer func() {
+		_ = namespace.Unregister("health_check")
+	}()
+
+	hc := NewChecker(&Config{
+		Timeout:           time.Second,
+		Fall:              2,
+		Rise:              2,
+		MaxReplicationLag: 10 * time.Second,
+	})
+
+	waitEjected := func(db *fakeDB, expect bool) {
+		assert.Eventually(t, func() bool {
+			return ns.IsEjected(db) == expect
+		}, time.Second, time.Millisecond)
+	}
+
+	ctx := context.Background()
+
+	// eject the slave after 2 consecutive failures
+	slave.setDown(true)
+	hc.CheckOnce(ctx)
+	assert.False(t, ns.IsEjected(slave))
+	hc.CheckOnce(ctx)
+	waitEjected(slave, true)
+	// the weight is never changed
+	assert.Equal(t, proto.Weight{R: 10, W: 0}, slave.Weight())
+
+	// all reads should go to the master
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "master", ns.DB(rcontext.WithRead(ctx), "health_0000").ID())
+	}
+	assert.Nil(t, ns.DBSlave(ctx, "health_0000"))
+
+	// restore the slave after 2 consecutive successes
+	slave.setDown(false)
+	hc.CheckOnce(ctx)
+	assert.True(t, ns.IsEjected(slave))
+	hc.CheckOnce(ctx)
+	waitEjected(slave, false)
+	assert.Equal(t, "slave", ns.DBSlave(ctx, "health_0000").ID())
+
+	// eject the slave if the replication lag is too large
+	slave.setLag("30")
+	hc.CheckOnce(ctx)
+	hc.CheckOnce(ctx)
+	waitEjected(slave, true)
+
+	// eject the slave if the replication is broken
+	slave.setLag("0")
+	hc.CheckOnce(ctx)
+	hc.CheckOnce(ctx)
+	waitEjected(slave, false)
+	slave.setLag("")
+	hc.CheckOnce(ctx)
+	hc.CheckOnce(ctx)
+	waitEjected(slave, true)
+
+	// the master keeps receiving writes when ejected
+	master.setDown(true)
+	hc.CheckOnce(ctx)
+	hc.CheckOnce(ctx)
+	waitEjected(master, true)
+	assert.Equal(t, proto.Weight{R: 10, W: 10}, master.Weight())
+	assert.Equal(t, "master", ns.DBMaster(ctx, "health_0000").ID())
+	assert.NotNil(t, ns.DB(rcontext.WithRead(ctx), "health_0000"))
+}
+
+func TestChecker_Failover(t *testing.T) {
+	var (
+		primary = &fakeDB{id: "primary", weight: proto.Weight{R: 10, W: 10}}
+		replica = &fakeDB{id: "replica", weight: proto.Weight{R: 10, W: 0}, readOnly: true}
+		other   = &fakeDB{id: "other", weight: proto.Weight{R: 10, W: 0}, readOnly: true}
+	)
+
+	ns, err := namespace.New("failover",
+		namespace.UpsertDB("failover_0000", primary),
+		namespace.UpsertDB("failover_0000", replica),
+		namespace.UpsertDB("failover_0000", other),
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, namespace.Register(ns))
+	defer func() {
+		_ = namespace.Unregister("failover")
+	}()
+
+	var (
+		mu       sync.Mutex
+		switches []string
+	)
+	namespace.SubscribeMaster(func(namespace, group, prev, next string) {
+		if namespace != "failover" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switches = append(switches, prev+"->"+next)
+	})
+
+	hc := NewChecker(&Config{
+		Timeout:  time.Second,
+		Failover: true,
+	})
+
+	ctx := context.Background()
+	waitMaster := func(expect string) {
+		assert.Eventually(t, func() bool {
+			return ns.DBMaster(ctx, "failover_0000").ID() == expect
+		}, time.Second, time.Millisecond)
+	}
+
+	hc.CheckOnce(ctx)
+	waitMaster("primary")
+
+	// the replica is promoted
+	primary.setReadOnly(true)
+	replica.setReadOnly(false)
+	hc.CheckOnce(ctx)
+	waitMaster("replica")
+
+	// ambiguous roles, nothing changed
+	replica.setReadOnly(true)
+	hc.CheckOnce(ctx)
+	primary.setReadOnly(false)
+	other.setReadOnly(false)
+	hc.CheckOnce(ctx)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "replica", ns.DBMaster(ctx, "failover_0000").ID())
+
+	// the pinned master will not be switched
+	other.setReadOnly(true)
+	assert.NoError(t, ns.EnqueueCommand(namespace.PinMaster("failover_0000", "other")))
+	waitMaster("other")
+	hc.CheckOnce(ctx)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "other", ns.DBMaster(ctx, "failover_0000").ID())
+
+	// unpin, the writable one will be elected
+	assert.NoError(t, ns.EnqueueCommand(namespace.PinMaster("failover_0000", "")))
+	hc.CheckOnce(ctx)
+	waitMaster("primary")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"primary->replica", "replica->other", "other->primary"}, switches)
+}
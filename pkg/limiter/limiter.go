@@ -0,0 +1,329 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package limiter enforces the rate limits and concurrency quotas of statements per tenant, user and fingerprint,
+// and the max frontend connections per tenant and user, so that a noisy tenant cannot exhaust the backend
+// connection pools shared with others.
+package limiter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/util/fingerprint"
+)
+
+var _default = New()
+
+// Default returns the default limiter.
+func Default() *Limiter {
+	return _default
+}
+
+// Limiter enforces the quotas of statements and frontend connections of tenants.
+type Limiter struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenantLimiter
+
+	connections     sync.Map // tenant -> *atomic.Int64
+	userConnections sync.Map // tenant and user -> *atomic.Int64
+}
+
+// New creates a Limiter.
+func New() *Limiter {
+	return &Limiter{
+		tenants: make(map[string]*tenantLimiter),
+	}
+}
+
+// SetLimits sets the limits of tenant, nil means unlimited. The states of the existing quotas are kept,
+// so the limits can be adjusted at runtime.
+func (l *Limiter) SetLimits(tenant string, limits *config.Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits == nil {
+		delete(l.tenants, tenant)
+		return
+	}
+	l.tenants[tenant] = newTenantLimiter(tenant, limits, l.tenants[tenant])
+}
+
+// Acquire acquires the quotas of tenant, user and fingerprint of the statement, returns an error if any quota is
+// exceeded. The release function must be called after the statement is executed.
+func (l *Limiter) Acquire(tenant, user, query string) (release func(), err error) {
+	l.mu.RLock()
+	tl, ok := l.tenants[tenant]
+	l.mu.RUnlock()
+
+	if !ok {
+		return func() {}, nil
+	}
+	return tl.acquire(user, query)
+}
+
+// Connect acquires a frontend connection of the user, returns an error if the max connections of tenant or user
+// is exceeded. The release function must be called after the connection is closed.
+func (l *Limiter) Connect(tenant, user string) (release func(), err error) {
+	var maxConnections, maxUserConnections int64
+
+	l.mu.RLock()
+	if tl, ok := l.tenants[tenant]; ok {
+		maxConnections, maxUserConnections = tl.maxConnections, tl.maxUserConnections
+	}
+	l.mu.RUnlock()
+
+	var (
+		connections     = loadCounter(&l.connections, tenant)
+		userConnections = loadCounter(&l.userConnections, tenant+"/"+user)
+	)
+
+	if n := connections.Inc(); maxConnections > 0 && n > maxConnections {
+		connections.Dec()
+		return nil, mysqlErrors.NewSQLError(mConstants.ERConCount, mConstants.SSConCount,
+			"Too many connections of tenant '%s'", tenant)
+	}
+	if n := userConnections.Inc(); maxUserConnections > 0 && n > maxUserConnections {
+		userConnections.Dec()
+		connections.Dec()
+		return nil, mysqlErrors.NewSQLError(mConstants.ERTooManyUserConnections, mConstants.SSDBAccessDenied,
+			"User '%s' has exceeded the 'max_user_connections' resource", user)
+	}
+
+	return func() {
+		userConnections.Dec()
+		connections.Dec()
+	}, nil
+}
+
+func loadCounter(counters *sync.Map, key string) *atomic.Int64 {
+	if exist, ok := counters.Load(key); ok {
+		return exist.(*atomic.Int64)
+	}
+	actual, _ := counters.LoadOrStore(key, atomic.NewInt64(0))
+	return actual.(*atomic.Int64)
+}
+
+type tenantLimiter struct {
+	name         string
+	tenant       *quota
+	user         *config.Quota
+	users        sync.Map // username -> *quota
+	explicit     map[string]*config.Quota
+	fingerprints map[string]*quota // normalized SQL or digest -> quota
+
+	maxConnections     int64
+	maxUserConnections int64
+}
+
+func newTenantLimiter(name string, c *config.Limits, prev *tenantLimiter) *tenantLimiter {
+	tl := &tenantLimiter{
+		name:         name,
+		user:         c.User,
+		explicit:     c.Users,
+		fingerprints: make(map[string]*quota, len(c.Fingerprints)),
+
+		maxConnections:     int64(c.MaxConnections),
+		maxUserConnections: int64(c.MaxUserConnections),
+	}
+
+	if c.Tenant != nil {
+		if prev != nil && prev.tenant != nil {
+			tl.tenant = prev.tenant
+			tl.tenant.update(c.Tenant)
+		} else {
+			tl.tenant = newQuota(describeTenant(name), c.Tenant)
+		}
+	}
+
+	// reuse the quotas of users which are still limited
+	if prev != nil {
+		prev.users.Range(func(key, value interface{}) bool {
+			if qc := tl.quotaOfUser(key.(string)); qc != nil {
+				q := value.(*quota)
+				q.update(qc)
+				tl.users.Store(key, q)
+			}
+			return true
+		})
+	}
+
+	for _, it := range c.Fingerprints {
+		key := fingerprint.Key(it.Fingerprint)
+		qc := it.Quota
+		if prev != nil {
+			if q, ok := prev.fingerprints[key]; ok {
+				q.update(&qc)
+				tl.fingerprints[key] = q
+				continue
+			}
+		}
+		tl.fingerprints[key] = newQuota(describeFingerprint(it.Fingerprint), &qc)
+	}
+
+	return tl
+}
+
+// quotaOfUser returns the quota config of user, nil if unlimited.
+func (tl *tenantLimiter) quotaOfUser(user string) *config.Quota {
+	if qc, ok := tl.explicit[user]; ok {
+		return qc
+	}
+	return tl.user
+}
+
+func (tl *tenantLimiter) acquire(user, query string) (func(), error) {
+	quotas := make([]*quota, 0, 3)
+	if tl.tenant != nil {
+		quotas = append(quotas, tl.tenant)
+	}
+	if q := tl.userQuota(user); q != nil {
+		quotas = append(quotas, q)
+	}
+	if q := tl.fingerprintQuota(query); q != nil {
+		quotas = append(quotas, q)
+	}
+
+	var (
+		now     = time.Now()
+		cancels = make([]func(), 0, len(quotas))
+	)
+	for _, q := range quotas {
+		cancel, err := q.acquire(now)
+		if err != nil {
+			for _, it := range cancels {
+				it()
+			}
+			return nil, err
+		}
+		cancels = append(cancels, cancel)
+	}
+
+	return func() {
+		for _, q := range quotas {
+			q.release()
+		}
+	}, nil
+}
+
+func (tl *tenantLimiter) userQuota(user string) *quota {
+	if exist, ok := tl.users.Load(user); ok {
+		return exist.(*quota)
+	}
+	qc := tl.quotaOfUser(user)
+	if qc == nil {
+		return nil
+	}
+	actual, _ := tl.users.LoadOrStore(user, newQuota(describeUser(user), qc))
+	return actual.(*quota)
+}
+
+func (tl *tenantLimiter) fingerprintQuota(query string) *quota {
+	if len(tl.fingerprints) < 1 {
+		return nil
+	}
+	normalized, digest := fingerprint.Of(query)
+	if q, ok := tl.fingerprints[normalized]; ok {
+		return q
+	}
+	return tl.fingerprints[digest]
+}
+
+// quota is the state of a rate limit and concurrency quota.
+type quota struct {
+	desc          string
+	rate          atomic.Value // *rate.Limiter
+	maxConcurrent atomic.Int64
+	concurrent    atomic.Int64
+}
+
+func newQuota(desc string, c *config.Quota) *quota {
+	q := &quota{
+		desc: desc,
+	}
+	q.update(c)
+	return q
+}
+
+func (q *quota) update(c *config.Quota) {
+	var (
+		limit = rate.Inf
+		burst int
+	)
+	if c.QPS > 0 {
+		limit = rate.Limit(c.QPS)
+		if burst = c.Burst; burst < 1 {
+			burst = int(math.Ceil(c.QPS))
+		}
+	}
+	// the bucket is refilled only if the rate limit is changed
+	if prev, ok := q.rate.Load().(*rate.Limiter); !ok || prev.Limit() != limit || prev.Burst() != burst {
+		q.rate.Store(rate.NewLimiter(limit, burst))
+	}
+	q.maxConcurrent.Store(int64(c.MaxConcurrent))
+}
+
+// acquire occupies the quota, the returned function cancels the acquisition if the statement is rejected by other quotas.
+func (q *quota) acquire(now time.Time) (cancel func(), err error) {
+	if maxConcurrent := q.maxConcurrent.Load(); q.concurrent.Inc() > maxConcurrent && maxConcurrent > 0 {
+		q.concurrent.Dec()
+		return nil, mysqlErrors.NewSQLError(mConstants.ERTooManyUserConnections, mConstants.SSDBAccessDenied,
+			"%s has more than %d executing statements", q.desc, maxConcurrent)
+	}
+
+	limiter := q.rate.Load().(*rate.Limiter)
+	r := limiter.ReserveN(now, 1)
+	if !r.OK() || r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		q.concurrent.Dec()
+		return nil, mysqlErrors.NewSQLError(mConstants.ERTooManyUserConnections, mConstants.SSDBAccessDenied,
+			"%s exceeds the rate limit of %v statements per second", q.desc, float64(limiter.Limit()))
+	}
+
+	return func() {
+		r.CancelAt(now)
+		q.concurrent.Dec()
+	}, nil
+}
+
+func (q *quota) release() {
+	q.concurrent.Dec()
+}
+
+func describeTenant(name string) string {
+	return "tenant '" + name + "'"
+}
+
+func describeUser(name string) string {
+	return "user '" + name + "'"
+}
+
+func describeFingerprint(fingerprint string) string {
+	return "statement '" + fingerprint + "'"
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+)
+
+func assertRejected(t *testing.T, err error) {
+	var sqlErr *mysqlErrors.SQLError
+	if assert.ErrorAs(t, err, &sqlErr) {
+		assert.Equal(t, mConstants.ERTooManyUserConnections, sqlErr.Num)
+	}
+}
+
+func TestLimiter_Concurrency(t *testing.T) {
+	l := New()
+
+	// unlimited
+	release, err := l.Acquire("fake-tenant", "root", "select 1")
+	assert.NoError(t, err)
+	release()
+
+	l.SetLimits("fake-tenant", &config.Limits{
+		Tenant: &config.Quota{MaxConcurrent: 3},
+		User:   &config.Quota{MaxConcurrent: 2},
+		Users: map[string]*config.Quota{
+			"admin": {},
+		},
+	})
+
+	r1, err := l.Acquire("fake-tenant", "root", "select 1")
+	assert.NoError(t, err)
+	r2, err := l.Acquire("fake-tenant", "root", "select 1")
+	assert.NoError(t, err)
+
+	// exceeds the quota of user
+	_, err = l.Acquire("fake-tenant", "root", "select 1")
+	assertRejected(t, err)
+
+	// the quota of admin is overridden
+	r3, err := l.Acquire("fake-tenant", "admin", "select 1")
+	assert.NoError(t, err)
+
+	// exceeds the quota of tenant
+	_, err = l.Acquire("fake-tenant", "admin", "select 1")
+	assertRejected(t, err)
+
+	r1()
+	r4, err := l.Acquire("fake-tenant", "admin", "select 1")
+	assert.NoError(t, err)
+
+	// the executing statements are kept after limits are changed
+	l.SetLimits("fake-tenant", &config.Limits{
+		Tenant: &config.Quota{MaxConcurrent: 4},
+	})
+	r5, err := l.Acquire("fake-tenant", "root", "select 1")
+	assert.NoError(t, err)
+	_, err = l.Acquire("fake-tenant", "root", "select 1")
+	assertRejected(t, err)
+
+	for _, it := range []func(){r2, r3, r4, r5} {
+		it()
+	}
+
+	l.SetLimits("fake-tenant", nil)
+	for i := 0; i < 10; i++ {
+		_, err = l.Acquire("fake-tenant", "root", "select 1")
+		assert.NoError(t, err)
+	}
+}
+
+func TestLimiter_Rate(t *testing.T) {
+	l := New()
+	l.SetLimits("fake-tenant", &config.Limits{
+		Fingerprints: []*config.FingerprintQuota{
+			{Fingerprint: "select * from student where id = 1", Quota: config.Quota{QPS: 0.001, Burst: 2}},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		release, err := l.Acquire("fake-tenant", "root", "SELECT * FROM student WHERE id = ?")
+		assert.NoError(t, err)
+		release()
+	}
+
+	// the burst is used up
+	_, err := l.Acquire("fake-tenant", "root", "select * from student where id = 42")
+	assertRejected(t, err)
+
+	// other statements are not limited
+	_, err = l.Acquire("fake-tenant", "root", "select * from employee where id = 42")
+	assert.NoError(t, err)
+}
+
+func TestLimiter_Digest(t *testing.T) {
+	_, digest := parser.NormalizeDigest("delete from student where id = 1")
+
+	l := New()
+	l.SetLimits("fake-tenant", &config.Limits{
+		Tenant: &config.Quota{QPS: 0.001, Burst: 10},
+		Fingerprints: []*config.FingerprintQuota{
+			{Fingerprint: digest.String(), Quota: config.Quota{MaxConcurrent: 1}},
+		},
+	})
+
+	release, err := l.Acquire("fake-tenant", "root", "delete from student where id = 2")
+	assert.NoError(t, err)
+	_, err = l.Acquire("fake-tenant", "root", "delete from student where id = 3")
+	assertRejected(t, err)
+	release()
+
+	// the rate tokens are returned when rejected by other quotas, so 9 of 10 are left
+	for i := 0; i < 9; i++ {
+		_, err = l.Acquire("fake-tenant", "root", "select 1")
+		assert.NoError(t, err)
+	}
+	_, err = l.Acquire("fake-tenant", "root", "select 1")
+	assertRejected(t, err)
+}
+
+func TestLimiter_Connect(t *testing.T) {
+	l := New()
+
+	l.SetLimits("fake-tenant", &config.Limits{
+		MaxConnections:     3,
+		MaxUserConnections: 2,
+	})
+
+	r1, err := l.Connect("fake-tenant", "root")
+	assert.NoError(t, err)
+	r2, err := l.Connect("fake-tenant", "root")
+	assert.NoError(t, err)
+
+	_, err = l.Connect("fake-tenant", "root")
+	assertRejected(t, err)
+
+	r3, err := l.Connect("fake-tenant", "admin")
+	assert.NoError(t, err)
+
+	_, err = l.Connect("fake-tenant", "admin")
+	var sqlErr *mysqlErrors.SQLError
+	if assert.ErrorAs(t, err, &sqlErr) {
+		assert.Equal(t, mConstants.ERConCount, sqlErr.Num)
+	}
+
+	r1()
+	r4, err := l.Connect("fake-tenant", "root")
+	assert.NoError(t, err)
+
+	for _, release := range []func(){r2, r3, r4} {
+		release()
+	}
+
+	// other tenants are unlimited
+	_, err = l.Connect("other-tenant", "root")
+	assert.NoError(t, err)
+}
@@ -17,10 +17,32 @@
 
 package metrics
 
+import (
+	"sync"
+)
+
 import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// labels of metrics
+const (
+	LabelTenant = "tenant"
+	LabelSchema = "schema"
+	LabelType   = "type"
+	LabelTable  = "table"
+	LabelGroup  = "group"
+	LabelNode   = "node"
+	LabelCode   = "code"
+	LabelAction = "action"
+)
+
+// actions of transaction
+const (
+	TxCommit   = "commit"
+	TxRollback = "rollback"
+)
+
 var (
 	ParserDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "arana",
@@ -45,10 +67,90 @@ var (
 		Help:      "histogram of processing time (s) in execute.",
 		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 30), // 100us ~ 15h,
 	})
+
+	StatementDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "arana",
+		Subsystem: "statement",
+		Name:      "duration_seconds",
+		Help:      "histogram of processing time (s) of frontend statements.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 30), // 100us ~ 15h,
+	}, []string{LabelTenant, LabelSchema, LabelType, LabelTable})
+
+	StatementErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "arana",
+		Subsystem: "statement",
+		Name:      "errors_total",
+		Help:      "counter of failed frontend statements.",
+	}, []string{LabelTenant, LabelSchema, LabelType, LabelCode})
+
+	RowsReturned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "arana",
+		Subsystem: "statement",
+		Name:      "rows_returned_total",
+		Help:      "counter of rows returned to frontend clients.",
+	}, []string{LabelTenant, LabelSchema, LabelType, LabelTable})
+
+	BackendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "arana",
+		Subsystem: "backend",
+		Name:      "duration_seconds",
+		Help:      "histogram of processing time (s) of physical statements.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 30), // 100us ~ 15h,
+	}, []string{LabelSchema, LabelGroup, LabelNode})
+
+	BackendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "arana",
+		Subsystem: "backend",
+		Name:      "errors_total",
+		Help:      "counter of failed physical statements.",
+	}, []string{LabelSchema, LabelGroup, LabelNode})
+
+	BackendHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "arana",
+		Subsystem: "backend",
+		Name:      "healthy",
+		Help:      "health status of backend nodes, 1 means healthy and 0 means ejected.",
+	}, []string{LabelSchema, LabelGroup, LabelNode})
+
+	MasterSwitches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "arana",
+		Subsystem: "backend",
+		Name:      "master_switches_total",
+		Help:      "total count of master switches of groups, the node label is the new master.",
+	}, []string{LabelSchema, LabelGroup, LabelNode})
+
+	FrontendConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "arana",
+		Subsystem: "frontend",
+		Name:      "connections",
+		Help:      "number of active frontend connections.",
+	}, []string{LabelTenant})
+
+	Transactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "arana",
+		Subsystem: "transaction",
+		Name:      "total",
+		Help:      "counter of finished transactions.",
+	}, []string{LabelTenant, LabelSchema, LabelAction})
 )
 
+var _registerOnce sync.Once
+
+// RegisterMetrics registers all metrics of arana into the default registry, it is safe to be called more than once.
 func RegisterMetrics() {
-	prometheus.MustRegister(ParserDuration)
-	prometheus.MustRegister(OptimizeDuration)
-	prometheus.MustRegister(ExecuteDuration)
+	_registerOnce.Do(func() {
+		prometheus.MustRegister(ParserDuration)
+		prometheus.MustRegister(OptimizeDuration)
+		prometheus.MustRegister(ExecuteDuration)
+		prometheus.MustRegister(StatementDuration)
+		prometheus.MustRegister(StatementErrors)
+		prometheus.MustRegister(RowsReturned)
+		prometheus.MustRegister(BackendDuration)
+		prometheus.MustRegister(BackendErrors)
+		prometheus.MustRegister(BackendHealthy)
+		prometheus.MustRegister(MasterSwitches)
+		prometheus.MustRegister(FrontendConnections)
+		prometheus.MustRegister(Transactions)
+		prometheus.MustRegister(_poolCollector)
+	})
 }
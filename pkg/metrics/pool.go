@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _poolCollector = newPoolCollector()
+
+var _poolStatsProvider atomic.Value // func() []PoolStats
+
+// PoolStats represents the stats of a backend connection pool.
+type PoolStats struct {
+	Schema      string
+	Group       string
+	Node        string
+	Capacity    int64
+	MaxCapacity int64
+	Available   int64
+	Active      int64
+	InUse       int64
+	WaitCount   int64
+	WaitTime    time.Duration
+	IdleClosed  int64 // count of connections closed due to idle timeout
+	Expired     int64 // count of connections closed due to max lifetime
+	Invalid     int64 // count of connections failed in validation
+}
+
+// SetPoolStatsProvider sets the provider which reports the stats of all backend connection pools when scraping.
+func SetPoolStatsProvider(provider func() []PoolStats) {
+	_poolStatsProvider.Store(provider)
+}
+
+type poolCollector struct {
+	capacity, available, active, inUse, waitCount, waitTime *prometheus.Desc
+	idleClosed, expired, invalid                            *prometheus.Desc
+}
+
+func newPoolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName("arana", "pool", name),
+		help,
+		[]string{LabelSchema, LabelGroup, LabelNode},
+		nil,
+	)
+}
+
+func newPoolCollector() *poolCollector {
+	return &poolCollector{
+		capacity:  newPoolDesc("capacity", "capacity of backend connection pool."),
+		available: newPoolDesc("available", "number of available resources in backend connection pool."),
+		active:    newPoolDesc("active", "number of opened resources in backend connection pool."),
+		inUse:     newPoolDesc("in_use", "number of borrowed resources in backend connection pool."),
+		waitCount: newPoolDesc("wait_count_total", "counter of waits when borrowing from backend connection pool."),
+		waitTime:  newPoolDesc("wait_seconds_total", "total time (s) waited when borrowing from backend connection pool."),
+
+		idleClosed: newPoolDesc("idle_closed_total", "counter of connections closed due to idle timeout."),
+		expired:    newPoolDesc("expired_total", "counter of connections closed due to max lifetime."),
+		invalid:    newPoolDesc("invalid_total", "counter of connections failed in validation."),
+	}
+}
+
+func (pc *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pc.capacity
+	ch <- pc.available
+	ch <- pc.active
+	ch <- pc.inUse
+	ch <- pc.waitCount
+	ch <- pc.waitTime
+	ch <- pc.idleClosed
+	ch <- pc.expired
+	ch <- pc.invalid
+}
+
+func (pc *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	provider, ok := _poolStatsProvider.Load().(func() []PoolStats)
+	if !ok || provider == nil {
+		return
+	}
+	for _, it := range provider() {
+		labels := []string{it.Schema, it.Group, it.Node}
+		ch <- prometheus.MustNewConstMetric(pc.capacity, prometheus.GaugeValue, float64(it.Capacity), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.available, prometheus.GaugeValue, float64(it.Available), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.active, prometheus.GaugeValue, float64(it.Active), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.inUse, prometheus.GaugeValue, float64(it.InUse), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.waitCount, prometheus.CounterValue, float64(it.WaitCount), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.waitTime, prometheus.CounterValue, it.WaitTime.Seconds(), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.idleClosed, prometheus.CounterValue, float64(it.IdleClosed), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.expired, prometheus.CounterValue, float64(it.Expired), labels...)
+		ch <- prometheus.MustNewConstMetric(pc.invalid, prometheus.CounterValue, float64(it.Invalid), labels...)
+	}
+}
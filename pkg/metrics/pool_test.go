@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolCollector(t *testing.T) {
+	assert.Equal(t, 0, testutil.CollectAndCount(_poolCollector))
+
+	SetPoolStatsProvider(func() []PoolStats {
+		return []PoolStats{
+			{
+				Schema:    "employees",
+				Group:     "employees_0000",
+				Node:      "node0",
+				Capacity:  8,
+				Available: 6,
+				Active:    3,
+				InUse:     2,
+				WaitCount: 1,
+				WaitTime:  1500 * time.Millisecond,
+				Expired:   4,
+			},
+		}
+	})
+	defer SetPoolStatsProvider(nil)
+
+	expect := `
+# HELP arana_pool_in_use number of borrowed resources in backend connection pool.
+# TYPE arana_pool_in_use gauge
+arana_pool_in_use{group="employees_0000",node="node0",schema="employees"} 2
+# HELP arana_pool_wait_seconds_total total time (s) waited when borrowing from backend connection pool.
+# TYPE arana_pool_wait_seconds_total counter
+arana_pool_wait_seconds_total{group="employees_0000",node="node0",schema="employees"} 1.5
+# HELP arana_pool_expired_total counter of connections closed due to max lifetime.
+# TYPE arana_pool_expired_total counter
+arana_pool_expired_total{group="employees_0000",node="node0",schema="employees"} 4
+`
+	assert.NoError(t, testutil.CollectAndCompare(_poolCollector, strings.NewReader(expect), "arana_pool_in_use", "arana_pool_wait_seconds_total", "arana_pool_expired_total"))
+	assert.Equal(t, 9, testutil.CollectAndCount(_poolCollector))
+}
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http"
+)
+
+import (
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+const _defaultPath = "/metrics"
+
+// Config represents the config of metrics endpoint.
+type Config struct {
+	// Address is the listen address of http server, eg: ':9090'.
+	Address string `yaml:"address" json:"address"`
+	// Path is the http path of metrics, default is '/metrics'.
+	Path string `yaml:"path" json:"path"`
+}
+
+// Init registers all metrics and serves them through http in background.
+func Init(c *Config) {
+	RegisterMetrics()
+
+	if c == nil || len(c.Address) < 1 {
+		return
+	}
+
+	path := c.Path
+	if len(path) < 1 {
+		path = _defaultPath
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, promhttp.Handler())
+
+	go func() {
+		log.Infof("start metrics server on %s%s", c.Address, path)
+		if err := http.ListenAndServe(c.Address, mux); err != nil {
+			log.Errorf("failed to serve metrics on %s: %v", c.Address, err)
+		}
+	}()
+}
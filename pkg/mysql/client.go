@@ -465,7 +465,8 @@ func NewConnector(config json.RawMessage) (*Connector, error) {
 }
 
 func (c *Connector) NewBackendConnection(ctx context.Context) (pools.Resource, error) {
-	conn := &BackendConnection{conf: c.conf}
+	now := time.Now()
+	conn := &BackendConnection{conf: c.conf, createdAt: now, idleSince: now}
 	if err := conn.Connect(ctx); err != nil {
 		return conn, err
 	}
@@ -489,6 +490,29 @@ type BackendConnection struct {
 	serverVersion string
 
 	characterSet uint8
+
+	// variables is the session variables which are applied on the connection, name -> value.
+	variables map[string]string
+
+	// createdAt is the time when the connection is opened.
+	createdAt time.Time
+	// idleSince is the time when the connection is returned to pool last time.
+	idleSince time.Time
+}
+
+// CreatedAt returns the time when the connection is opened.
+func (conn *BackendConnection) CreatedAt() time.Time {
+	return conn.createdAt
+}
+
+// IdleSince returns the time when the connection became idle last time.
+func (conn *BackendConnection) IdleSince() time.Time {
+	return conn.idleSince
+}
+
+// MarkIdle marks the connection as idle, it should be called when the connection is returned to pool.
+func (conn *BackendConnection) MarkIdle() {
+	conn.idleSince = time.Now()
 }
 
 func (conn *BackendConnection) DBName() string {
@@ -508,7 +532,8 @@ func (conn *BackendConnection) Connect(ctx context.Context) error {
 	} else {
 		typ = conn.conf.Net
 	}
-	netConn, err := net.Dial(typ, conn.conf.Addr)
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, typ, conn.conf.Addr)
 	if err != nil {
 		return err
 	}
@@ -526,6 +551,16 @@ func (conn *BackendConnection) Connect(ctx context.Context) error {
 
 	conn.c = newConn(tcpConn)
 
+	// the handshake is bounded by the deadline of context too
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = tcpConn.SetDeadline(deadline); err != nil {
+			return err
+		}
+		defer func() {
+			_ = tcpConn.SetDeadline(time.Time{})
+		}()
+	}
+
 	return conn.clientHandshake()
 }
 
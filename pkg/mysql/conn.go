@@ -93,6 +93,18 @@ type Conn struct {
 	// Tenant is the current tenant login.
 	Tenant string
 
+	// Username is the current user login.
+	Username string
+
+	// Attributes is the connection attributes sent by client.
+	Attributes map[string]string
+
+	// Interactive is true if the client is interactive, eg: mysql command line.
+	Interactive bool
+
+	// Variables is the session variables set by client, it is only used by the server.
+	Variables map[string]string
+
 	// ConnectionID is set:
 	// - at Connect() time for clients, with the value returned by
 	// the server.
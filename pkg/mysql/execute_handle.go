@@ -28,37 +28,28 @@ import (
 import (
 	"github.com/arana-db/arana/pkg/constants/mysql"
 	"github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/process"
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/hint"
+	"github.com/arana-db/arana/pkg/resultx"
 	"github.com/arana-db/arana/pkg/security"
+	"github.com/arana-db/arana/pkg/slowlog"
 	"github.com/arana-db/arana/pkg/util/log"
 )
 
 func (l *Listener) handleInitDB(c *Conn, ctx *proto.Context) error {
-	db := string(ctx.Data[1:])
+	err := l.executor.ExecuteUseDB(ctx)
 	c.recycleReadPacket()
-
-	var allow bool
-	for _, it := range security.DefaultTenantManager().GetClusters(c.Tenant) {
-		if db == it {
-			allow = true
-			break
-		}
-	}
-
-	if !allow {
-		if err := c.writeErrorPacketFromError(errors.NewSQLError(mysql.ERBadDb, "", "Unknown database '%s'", db)); err != nil {
-			log.Errorf("failed to write ComInitDB error to %s: %v", c, err)
-			return err
+	if err != nil {
+		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
+			log.Errorf("failed to write ComInitDB error to %s: %v", c, wErr)
+			return wErr
 		}
 		return nil
 	}
 
-	c.Schema = db
-	err := l.executor.ExecuteUseDB(ctx)
-	if err != nil {
-		return err
-	}
+	l.switchSchema(c, ctx.Schema)
+
 	if err = c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
 		log.Errorf("Error writing ComInitDB result to %s: %v", c, err)
 		return err
@@ -67,6 +58,13 @@ func (l *Listener) handleInitDB(c *Conn, ctx *proto.Context) error {
 	return nil
 }
 
+func (l *Listener) switchSchema(c *Conn, schema string) {
+	c.Schema = schema
+	if p, ok := process.Load(c.ConnectionID); ok {
+		p.SetSchema(schema)
+	}
+}
+
 func (l *Listener) handleQuery(c *Conn, ctx *proto.Context) error {
 	c.startWriterBuffering()
 	defer func() {
@@ -77,13 +75,45 @@ func (l *Listener) handleQuery(c *Conn, ctx *proto.Context) error {
 
 	c.recycleReadPacket()
 
+	if p, ok := process.Load(c.ConnectionID); ok {
+		var done func()
+		ctx.Context, done = p.Begin(ctx.Context, process.CommandQuery, ctx.GetQuery())
+		defer done()
+	}
+
 	var (
-		result proto.Result
-		err    error
-		warn   uint16
+		result  proto.Result
+		err     error
+		warn    uint16
+		rows    int64
+		finish  func(error)
+		observe = observeStatement(ctx)
 	)
 
-	if result, warn, err = l.executor.ExecutorComQuery(ctx); err != nil {
+	ctx.Context, finish = slowlog.Begin(ctx)
+	defer func() {
+		finish(err)
+		observe(rows, err)
+	}()
+
+	result, warn, err = l.executor.ExecutorComQuery(ctx)
+	if err == nil && result != nil {
+		// the slow log is recorded after the rows are sent, so that the physical statements are released
+		result = resultx.OnClose(result, func() {
+			finish(nil)
+		})
+	}
+
+	// the schema may be switched by USE statement
+	if err == nil && ctx.Schema != c.Schema {
+		l.switchSchema(c, ctx.Schema)
+	}
+	// the session variables may be replaced by SET statement
+	if err == nil {
+		c.Variables = ctx.Variables
+	}
+
+	if err != nil {
 		log.Errorf("executor com_query error %v: %+v", ctx.ConnectionID, err)
 		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
 			log.Errorf("Error writing query error to client %v: %v", ctx.ConnectionID, wErr)
@@ -125,13 +155,17 @@ func (l *Listener) handleQuery(c *Conn, ctx *proto.Context) error {
 		return c.writeOKPacket(affected, insertId, c.StatusFlags, warn)
 	}
 
+	defer func() {
+		_ = ds.Close()
+	}()
+
 	fields, _ := ds.Fields()
 
 	if err = c.writeFields(fields); err != nil {
 		log.Errorf("write fields error %v: %v", ctx.ConnectionID, err)
 		return err
 	}
-	if err = c.writeDataset(ds); err != nil {
+	if rows, err = c.writeDataset(ds); err != nil {
 		log.Errorf("write dataset error %v: %v", ctx.ConnectionID, err)
 		return err
 	}
@@ -142,6 +176,33 @@ func (l *Listener) handleQuery(c *Conn, ctx *proto.Context) error {
 	return nil
 }
 
+func (l *Listener) handleProcessKill(c *Conn, ctx *proto.Context) error {
+	id, _, ok := readUint32(ctx.Data, 1)
+	c.recycleReadPacket()
+
+	if !ok {
+		if err := c.writeErrorPacketFromError(errors.NewSQLError(mysql.CRMalformedPacket, mysql.SSUnknownSQLState, "malformed COM_PROCESS_KILL packet")); err != nil {
+			log.Errorf("failed to write ComProcessKill error to %s: %v", c, err)
+			return err
+		}
+		return nil
+	}
+
+	if err := process.Kill(ctx.Context, c.Tenant, c.Username, security.IsAdmin(c.Tenant, c.Username), id, false); err != nil {
+		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
+			log.Errorf("failed to write ComProcessKill error to %s: %v", c, wErr)
+			return wErr
+		}
+		return nil
+	}
+
+	if err := c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
+		log.Errorf("Error writing ComProcessKill result to %s: %v", c, err)
+		return err
+	}
+	return nil
+}
+
 func (l *Listener) handleFieldList(c *Conn, ctx *proto.Context) error {
 	c.recycleReadPacket()
 	fields, err := l.executor.ExecuteFieldList(ctx)
@@ -208,11 +269,26 @@ func (l *Listener) handleStmtExecute(c *Conn, ctx *proto.Context) error {
 	prepareStmt, _ := l.stmts.Load(stmtID)
 	ctx.Stmt = prepareStmt.(*proto.Stmt)
 
+	if p, ok := process.Load(c.ConnectionID); ok {
+		var done func()
+		ctx.Context, done = p.Begin(ctx.Context, process.CommandExecute, ctx.GetQuery())
+		defer done()
+	}
+
 	var (
-		result proto.Result
-		warn   uint16
+		result  proto.Result
+		warn    uint16
+		rows    int64
+		finish  func(error)
+		observe = observeStatement(ctx)
 	)
 
+	ctx.Context, finish = slowlog.Begin(ctx)
+	defer func() {
+		finish(err)
+		observe(rows, err)
+	}()
+
 	if result, warn, err = l.executor.ExecutorComStmtExecute(ctx); err != nil {
 		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
 			log.Errorf("Error writing query error to client %v: %v, executor error: %v", ctx.ConnectionID, wErr, err)
@@ -220,6 +296,11 @@ func (l *Listener) handleStmtExecute(c *Conn, ctx *proto.Context) error {
 		}
 		return nil
 	}
+	if result != nil {
+		result = resultx.OnClose(result, func() {
+			finish(nil)
+		})
+	}
 
 	var ds proto.Dataset
 	if ds, err = result.Dataset(); err != nil {
@@ -251,7 +332,7 @@ func (l *Listener) handleStmtExecute(c *Conn, ctx *proto.Context) error {
 	if err = c.writeFields(fields); err != nil {
 		return err
 	}
-	if err = c.writeDatasetBinary(ds); err != nil {
+	if rows, err = c.writeDatasetBinary(ds); err != nil {
 		return err
 	}
 	if err = c.writeEndResult(false, 0, 0, warn); err != nil {
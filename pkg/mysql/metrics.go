@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql
+
+import (
+	"strconv"
+	"time"
+)
+
+import (
+	"github.com/arana-db/parser/ast"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/metrics"
+	"github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+)
+
+// types of statement in metrics
+const (
+	stmtTypeSelect   = "select"
+	stmtTypeInsert   = "insert"
+	stmtTypeReplace  = "replace"
+	stmtTypeUpdate   = "update"
+	stmtTypeDelete   = "delete"
+	stmtTypeDDL      = "ddl"
+	stmtTypeShow     = "show"
+	stmtTypeSet      = "set"
+	stmtTypeUse      = "use"
+	stmtTypeExplain  = "explain"
+	stmtTypeBegin    = "begin"
+	stmtTypeCommit   = "commit"
+	stmtTypeRollback = "rollback"
+	stmtTypeOther    = "other"
+	stmtTypeUnknown  = "unknown"
+)
+
+var _ ast.Visitor = (*tableNameFinder)(nil)
+
+// tableNameFinder finds the first table name in a statement.
+type tableNameFinder struct {
+	name string
+}
+
+func (f *tableNameFinder) Enter(n ast.Node) (ast.Node, bool) {
+	if len(f.name) > 0 {
+		return n, true
+	}
+	if tn, ok := n.(*ast.TableName); ok {
+		f.name = tn.Name.O
+		return n, true
+	}
+	return n, false
+}
+
+func (f *tableNameFinder) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// statementLabels returns the type and the first logical table of the statement.
+func statementLabels(stmt *proto.Stmt) (typ, table string) {
+	if stmt == nil || stmt.StmtNode == nil {
+		return stmtTypeUnknown, ""
+	}
+
+	switch it := stmt.StmtNode.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		typ = stmtTypeSelect
+	case *ast.InsertStmt:
+		if it.IsReplace {
+			typ = stmtTypeReplace
+		} else {
+			typ = stmtTypeInsert
+		}
+	case *ast.UpdateStmt:
+		typ = stmtTypeUpdate
+	case *ast.DeleteStmt:
+		typ = stmtTypeDelete
+	case ast.DDLNode:
+		typ = stmtTypeDDL
+	case *ast.ShowStmt:
+		typ = stmtTypeShow
+	case *ast.SetStmt:
+		typ = stmtTypeSet
+	case *ast.UseStmt:
+		typ = stmtTypeUse
+	case *ast.ExplainStmt:
+		typ = stmtTypeExplain
+	case *ast.BeginStmt:
+		typ = stmtTypeBegin
+	case *ast.CommitStmt:
+		typ = stmtTypeCommit
+	case *ast.RollbackStmt:
+		typ = stmtTypeRollback
+	default:
+		typ = stmtTypeOther
+	}
+
+	var finder tableNameFinder
+	stmt.StmtNode.Accept(&finder)
+	table = finder.name
+
+	return
+}
+
+// errorCode returns the mysql error code of the error.
+func errorCode(err error) string {
+	var se *errors.SQLError
+	if perrors.As(err, &se) {
+		return strconv.Itoa(se.Num)
+	}
+	return strconv.Itoa(mysql.ERUnknownError)
+}
+
+// observeStatement starts observing a frontend statement, the returned function should be called with
+// the count of returned rows and the final error when the statement is finished.
+func observeStatement(ctx *proto.Context) func(rows int64, err error) {
+	start := time.Now()
+	return func(rows int64, err error) {
+		typ, table := statementLabels(ctx.Stmt)
+		metrics.StatementDuration.WithLabelValues(ctx.Tenant, ctx.Schema, typ, table).Observe(time.Since(start).Seconds())
+		if rows > 0 {
+			metrics.RowsReturned.WithLabelValues(ctx.Tenant, ctx.Schema, typ, table).Add(float64(rows))
+		}
+		if err != nil {
+			metrics.StatementErrors.WithLabelValues(ctx.Tenant, ctx.Schema, typ, errorCode(err)).Inc()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/metrics"
+	"github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/proto"
+)
+
+func TestStatementLabels(t *testing.T) {
+	typ, table := statementLabels(nil)
+	assert.Equal(t, stmtTypeUnknown, typ)
+	assert.Empty(t, table)
+
+	for _, it := range []struct {
+		sql   string
+		typ   string
+		table string
+	}{
+		{"select * from student where id = 1", stmtTypeSelect, "student"},
+		{"select 1", stmtTypeSelect, ""},
+		{"select * from (select * from student) t union select * from teacher", stmtTypeSelect, "student"},
+		{"insert into student(id) values(1)", stmtTypeInsert, "student"},
+		{"replace into student(id) values(1)", stmtTypeReplace, "student"},
+		{"update student set name = 'foo'", stmtTypeUpdate, "student"},
+		{"delete from student where id = 1", stmtTypeDelete, "student"},
+		{"create index idx_name on student(name)", stmtTypeDDL, "student"},
+		{"show columns from student", stmtTypeShow, "student"},
+		{"set @@session.autocommit = 1", stmtTypeSet, ""},
+		{"begin", stmtTypeBegin, ""},
+		{"commit", stmtTypeCommit, ""},
+		{"rollback", stmtTypeRollback, ""},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmtNode, err := parser.New().ParseOneStmt(it.sql, "", "")
+			assert.NoError(t, err)
+			typ, table := statementLabels(&proto.Stmt{StmtNode: stmtNode})
+			assert.Equal(t, it.typ, typ)
+			assert.Equal(t, it.table, table)
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	err := errors.NewSQLError(mysql.ERSpecifiedAccessDenied, mysql.SSDBAccessDenied, "denied")
+	assert.Equal(t, "1227", errorCode(err))
+	assert.Equal(t, "1227", errorCode(perrors.WithStack(err)))
+	assert.Equal(t, "1105", errorCode(perrors.New("oops")))
+}
+
+func TestObserveStatement(t *testing.T) {
+	stmtNode, err := parser.New().ParseOneStmt("select * from metrics_student", "", "")
+	assert.NoError(t, err)
+
+	ctx := &proto.Context{
+		Tenant: "metrics_tenant",
+		Schema: "employees",
+		Stmt:   &proto.Stmt{StmtNode: stmtNode},
+	}
+
+	observeStatement(ctx)(3, nil)
+	observeStatement(ctx)(0, errors.NewSQLError(mysql.ERSpecifiedAccessDenied, mysql.SSDBAccessDenied, "denied"))
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.RowsReturned.WithLabelValues("metrics_tenant", "employees", stmtTypeSelect, "metrics_student")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.StatementErrors.WithLabelValues("metrics_tenant", "employees", stmtTypeSelect, "1227")))
+}
@@ -65,6 +65,9 @@ type RawResult struct {
 	closeOnce    sync.Once
 	closeFailure error
 
+	errorHook func(error) error
+	eofHook   func()
+
 	eof bool
 }
 
@@ -163,6 +166,7 @@ func (rr *RawResult) preflight() (err error) {
 
 	rr.preflightOnce.Do(func() {
 		rr.affectedRows, rr.lastInsertID, rr.colNumber, rr.more, rr.warnings, rr.preflightFailure = rr.c.readResultSetHeaderPacket()
+		rr.preflightFailure = rr.mapError(rr.preflightFailure)
 	})
 	err = rr.preflightFailure
 	return
@@ -180,6 +184,10 @@ func (rr *RawResult) flight() (err error) {
 	}()
 
 	rr.flightOnce.Do(func() {
+		defer func() {
+			rr.flightFailure = rr.mapError(rr.flightFailure)
+		}()
+
 		if rr.colNumber < 1 {
 			return
 		}
@@ -219,6 +227,10 @@ func (rr *RawResult) postFlight() (err error) {
 	}()
 
 	rr.postFlightOnce.Do(func() {
+		defer func() {
+			rr.postFlightFailure = rr.mapError(rr.postFlightFailure)
+		}()
+
 		if rr.colNumber < 1 {
 			return
 		}
@@ -272,6 +284,12 @@ func (rr *RawResult) RowsAffected() (uint64, error) {
 }
 
 func (rr *RawResult) nextRowData() (data []byte, err error) {
+	defer func() {
+		if err != io.EOF {
+			err = rr.mapError(err)
+		}
+	}()
+
 	if rr.eof {
 		err = io.EOF
 		return
@@ -305,6 +323,10 @@ func (rr *RawResult) nextRowData() (data []byte, err error) {
 		err = ParseErrorPacket(data)
 	}
 
+	if rr.eof && rr.eofHook != nil {
+		rr.eofHook()
+	}
+
 	// TODO: Check we're not over the limit before we add more.
 	//if len(result.Rows) == maxrows {
 	//	if err := conn.DrainResults(); err != nil {
@@ -336,6 +358,35 @@ func (rr *RawResult) SetCloser(closer func() error) {
 	rr.closeFunc = closer
 }
 
+// OnClose registers a hook which will be called after the result is closed.
+func (rr *RawResult) OnClose(hook func()) {
+	closer := rr.closeFunc
+	rr.closeFunc = func() error {
+		defer hook()
+		if closer == nil {
+			return nil
+		}
+		return closer()
+	}
+}
+
+// SetErrorHook sets the hook which maps the errors occurred while reading the result.
+func (rr *RawResult) SetErrorHook(hook func(error) error) {
+	rr.errorHook = hook
+}
+
+// SetEOFHook sets the hook which will be called once the last packet of the result has been read.
+func (rr *RawResult) SetEOFHook(hook func()) {
+	rr.eofHook = hook
+}
+
+func (rr *RawResult) mapError(err error) error {
+	if err == nil || rr.errorHook == nil {
+		return err
+	}
+	return rr.errorHook(err)
+}
+
 func newResult(c *BackendConnection) *RawResult {
 	return &RawResult{c: c}
 }
@@ -28,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 import (
@@ -41,7 +42,10 @@ import (
 import (
 	"github.com/arana-db/arana/pkg/config"
 	"github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/limiter"
+	"github.com/arana-db/arana/pkg/metrics"
 	"github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/process"
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/security"
 	"github.com/arana-db/arana/pkg/util/log"
@@ -49,6 +53,13 @@ import (
 
 const initClientConnStatus = mysql.ServerStatusAutocommit
 
+const (
+	// _drainCheckInterval is the interval of checking if all frontend connections are closed when draining.
+	_drainCheckInterval = 100 * time.Millisecond
+	// _drainRollbackTimeout is the max duration of waiting for the rollback of connections which are closed forcibly.
+	_drainRollbackTimeout = 5 * time.Second
+)
+
 type handshakeResult struct {
 	connectionID uint32
 	schema       string
@@ -57,10 +68,16 @@ type handshakeResult struct {
 	authMethod   string
 	authResponse []byte
 	salt         []byte
+	attributes   map[string]string
+	interactive  bool
 }
 
 type ServerConfig struct {
-	ServerVersion string `yaml:"server_version" json:"server_version"`
+	ServerVersion      string        `yaml:"server_version" json:"server_version"`
+	MaxConnections     int           `yaml:"max_connections" json:"max_connections"`
+	WaitTimeout        time.Duration `yaml:"wait_timeout" json:"wait_timeout"`
+	InteractiveTimeout time.Duration `yaml:"interactive_timeout" json:"interactive_timeout"`
+	DrainTimeout       time.Duration `yaml:"drain_timeout" json:"drain_timeout"`
 }
 
 type Listener struct {
@@ -106,11 +123,25 @@ type Listener struct {
 	// stmts is the map to use a prepared statement.
 	// key is uint32 value is *proto.Stmt
 	stmts sync.Map
+
+	// connections is the count of frontend connections.
+	connections atomic.Int64
+
+	// conns is the frontend connections, key is uint32 value is *Conn.
+	conns sync.Map
+
+	// draining is true if the listener is closing, the frontend connections will be closed
+	// after their transactions are finished.
+	draining atomic.Bool
 }
 
 func NewListener(conf *config.Listener) (proto.Listener, error) {
 	cfg := &ServerConfig{
-		ServerVersion: conf.ServerVersion,
+		ServerVersion:      conf.ServerVersion,
+		MaxConnections:     conf.MaxConnections,
+		WaitTimeout:        conf.GetWaitTimeout(),
+		InteractiveTimeout: conf.GetInteractiveTimeout(),
+		DrainTimeout:       conf.GetDrainTimeout(),
 	}
 
 	l, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.SocketAddress.Address, conf.SocketAddress.Port))
@@ -145,13 +176,61 @@ func (l *Listener) Listen() {
 	}
 }
 
+// Close stops accepting new connections, and waits for the transactions of frontend connections to finish.
+// The connections which are still in transactions after the drain timeout will be closed forcibly, and
+// their transactions will be rolled back.
 func (l *Listener) Close() {
+	if !l.draining.CAS(false, true) {
+		return
+	}
+	_ = l.listener.Close()
+
+	log.Infof("closing mysql Listener %s, waiting for %d connections to finish", l.listener.Addr(), l.connections.Load())
+
+	// wake up the idle connections which are waiting for the next command
+	l.conns.Range(func(_, value interface{}) bool {
+		if c := value.(*Conn); !l.inTransaction(c) {
+			_ = c.conn.SetReadDeadline(time.Now())
+		}
+		return true
+	})
+
+	if l.waitConnections(l.conf.DrainTimeout) {
+		return
+	}
+
+	l.conns.Range(func(_, value interface{}) bool {
+		c := value.(*Conn)
+		log.Warnf("close the connection#%d of remote client %s forcibly, its transaction will be rolled back", c.ConnectionID, c.RemoteAddr())
+		c.Close()
+		return true
+	})
+
+	// wait for the rollback of transactions
+	l.waitConnections(_drainRollbackTimeout)
+}
+
+// waitConnections waits until all frontend connections are closed, returns false if timeout.
+func (l *Listener) waitConnections(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for l.connections.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(_drainCheckInterval)
+	}
+	return true
 }
 
 func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 	c := newConn(conn)
 	c.ConnectionID = connectionID
 
+	count := l.connections.Inc()
+	defer l.connections.Dec()
+	l.conns.Store(c.ConnectionID, c)
+	defer l.conns.Delete(c.ConnectionID)
+
 	// Catch panics, and close the connection in any case.
 	defer func() {
 		if x := recover(); x != nil {
@@ -160,10 +239,19 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 		conn.Close()
 		l.executor.ConnectionClose(&proto.Context{
 			Context:      context.Background(),
+			Schema:       c.Schema,
+			Tenant:       c.Tenant,
 			ConnectionID: c.ConnectionID,
 		})
 	}()
 
+	if l.conf.MaxConnections > 0 && count > int64(l.conf.MaxConnections) {
+		if err := c.writeErrorPacket(mysql.ERConCount, mysql.SSConCount, "Too many connections"); err != nil {
+			log.Errorf("Cannot write error packet to %s: %v", c, err)
+		}
+		return
+	}
+
 	err := l.handshake(c)
 	if err != nil {
 		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
@@ -173,8 +261,25 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 		return
 	}
 
+	release, err := limiter.Default().Connect(c.Tenant, c.Username)
+	if err != nil {
+		if wErr := c.writeErrorPacketFromError(err); wErr != nil {
+			log.Errorf("Cannot write error packet to %s: %v", c, wErr)
+		}
+		return
+	}
+	defer release()
+
 	c.Capabilities = l.capabilities
 	c.CharacterSet = l.characterSet
+	c.Variables = make(map[string]string)
+
+	process.Register(c.ConnectionID, c.Tenant, c.Username, c.RemoteAddr().String(), c.Close).SetSchema(c.Schema)
+	defer process.Unregister(c.ConnectionID)
+
+	connections := metrics.FrontendConnections.WithLabelValues(c.Tenant)
+	connections.Inc()
+	defer connections.Dec()
 
 	// Negotiation worked, send OK packet.
 	if err = c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
@@ -184,14 +289,32 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 
 	for {
 		c.sequence = 0
+
+		// the connection will be closed if it's idle too long, or the listener is draining
+		idleTimeout := l.idleTimeout(c)
+		_ = c.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		if l.draining.Load() && !l.inTransaction(c) {
+			_ = c.writeErrorPacket(mysql.ERServerShutdown, mysql.SSServerShutdown, "Server shutdown in progress")
+			return
+		}
+
 		var data []byte
 		if data, err = c.readEphemeralPacket(); err != nil {
-			// Don't log EOF errors. They cause too much spam.
-			if err != io.EOF && !strings.Contains(err.Error(), "use of closed network connection") {
+			var netErr net.Error
+			switch {
+			case l.draining.Load() && !l.inTransaction(c):
+				_ = c.writeErrorPacket(mysql.ERServerShutdown, mysql.SSServerShutdown, "Server shutdown in progress")
+			case perrors.As(err, &netErr) && netErr.Timeout():
+				log.Infof("close the connection#%d of remote client %s which is idle for %s", c.ConnectionID, c.RemoteAddr(), idleTimeout)
+				_ = c.writeErrorPacket(mysql.ERClientInteractionTimeout, mysql.SSUnknownSQLState,
+					"The client was disconnected by the server because of inactivity. See wait_timeout and interactive_timeout for configuring this behavior.")
+			case err != io.EOF && !strings.Contains(err.Error(), "use of closed network connection"):
+				// Don't log EOF errors. They cause too much spam.
 				log.Errorf("Error reading packet from %s: %v", c, err)
 			}
 			return
 		}
+		_ = c.conn.SetReadDeadline(time.Time{})
 
 		content := make([]byte, len(data))
 		copy(content, data)
@@ -200,6 +323,10 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 			Schema:       c.Schema,
 			Tenant:       c.Tenant,
 			ConnectionID: c.ConnectionID,
+			Username:     c.Username,
+			RemoteAddr:   c.RemoteAddr().String(),
+			Attributes:   c.Attributes,
+			Variables:    c.Variables,
 			Data:         content,
 		}
 
@@ -214,6 +341,28 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32) {
 	}
 }
 
+// idleTimeout returns the idle timeout of frontend connection, the session variable takes precedence over the config.
+func (l *Listener) idleTimeout(c *Conn) time.Duration {
+	name, timeout := "wait_timeout", l.conf.WaitTimeout
+	if c.Interactive {
+		name, timeout = "interactive_timeout", l.conf.InteractiveTimeout
+	}
+	if value, ok := c.Variables[name]; ok {
+		if n, err := strconv.ParseInt(strings.Trim(value, "'\""), 10, 64); err == nil && n > 0 {
+			return time.Duration(n) * time.Second
+		}
+	}
+	return timeout
+}
+
+// inTransaction returns true if the frontend connection is in a transaction.
+func (l *Listener) inTransaction(c *Conn) bool {
+	return l.executor.InLocalTransaction(&proto.Context{
+		Context:      context.Background(),
+		ConnectionID: c.ConnectionID,
+	})
+}
+
 func (l *Listener) handshake(c *Conn) error {
 	salt, err := newSalt()
 	if err != nil {
@@ -257,6 +406,9 @@ func (l *Listener) handshake(c *Conn) error {
 
 	c.Schema = handshake.schema
 	c.Tenant = handshake.tenant
+	c.Username = handshake.username
+	c.Attributes = handshake.attributes
+	c.Interactive = handshake.interactive
 
 	return nil
 }
@@ -476,8 +628,10 @@ func (l *Listener) parseClientHandshakePacket(firstTime bool, data []byte) (*han
 	}
 
 	// Decode connection attributes send by the client
+	var attributes map[string]string
 	if clientFlags&mysql.CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		var err error
+		if attributes, _, err = parseConnAttrs(data, pos); err != nil {
 			log.Warnf("Decode connection attributes send by the client: %v", err)
 		}
 	}
@@ -487,6 +641,8 @@ func (l *Listener) parseClientHandshakePacket(firstTime bool, data []byte) (*han
 		username:     username,
 		authMethod:   authMethod,
 		authResponse: authResponse,
+		attributes:   attributes,
+		interactive:  clientFlags&mysql.CapabilityClientInteractive != 0,
 	}, nil
 }
 
@@ -580,6 +736,8 @@ func (l *Listener) ExecuteCommand(c *Conn, ctx *proto.Context) error {
 		return l.handleStmtReset(c, ctx)
 	case mysql.ComSetOption:
 		return l.handleSetOption(c, ctx)
+	case mysql.ComProcessKill:
+		return l.handleProcessKill(c, ctx)
 	}
 	return nil
 }
@@ -1093,22 +1251,24 @@ func (c *Conn) writeRow(row proto.Row) error {
 	return c.writeEphemeralPacket()
 }
 
-func (c *Conn) writeDataset(ds proto.Dataset) error {
+func (c *Conn) writeDataset(ds proto.Dataset) (int64, error) {
 	var (
-		row proto.Row
-		err error
+		row  proto.Row
+		err  error
+		rows int64
 	)
 	for {
 		row, err = ds.Next()
 		if perrors.Is(err, io.EOF) {
-			return nil
+			return rows, nil
 		}
 		if err != nil {
-			return err
+			return rows, err
 		}
 		if err = c.writeRow(row); err != nil {
-			return err
+			return rows, err
 		}
+		rows++
 	}
 }
 
@@ -1177,22 +1337,24 @@ func (c *Conn) writePrepare(prepare *proto.Stmt) error {
 	return nil
 }
 
-func (c *Conn) writeDatasetBinary(result proto.Dataset) error {
+func (c *Conn) writeDatasetBinary(result proto.Dataset) (int64, error) {
 	var (
-		row proto.Row
-		err error
+		row  proto.Row
+		err  error
+		rows int64
 	)
 
 	for {
 		row, err = result.Next()
 		if perrors.Is(err, io.EOF) {
-			return nil
+			return rows, nil
 		}
 		if err != nil {
-			return perrors.WithStack(err)
+			return rows, perrors.WithStack(err)
 		}
 		if err = c.writeRow(row); err != nil {
-			return perrors.WithStack(err)
+			return rows, perrors.WithStack(err)
 		}
+		rows++
 	}
 }
@@ -32,6 +32,66 @@ var (
 	Database = Thead{
 		Col{Name: "Database", FieldType: consts.FieldTypeVarString},
 	}
+	DDLJobs = Thead{
+		Col{Name: "job_id", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "db_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "table_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "state", FieldType: consts.FieldTypeVarString},
+		Col{Name: "progress", FieldType: consts.FieldTypeVarString},
+		Col{Name: "query", FieldType: consts.FieldTypeVarString},
+		Col{Name: "create_time", FieldType: consts.FieldTypeDateTime},
+		Col{Name: "update_time", FieldType: consts.FieldTypeDateTime},
+	}
+	CheckTable = Thead{
+		Col{Name: "Table", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Op", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Msg_type", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Msg_text", FieldType: consts.FieldTypeVarString},
+	}
+	DDLJobQueries = Thead{
+		Col{Name: "job_id", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "group_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "table_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "state", FieldType: consts.FieldTypeVarString},
+		Col{Name: "query", FieldType: consts.FieldTypeVarString},
+		Col{Name: "error", FieldType: consts.FieldTypeVarString},
+	}
+	Explain = Thead{
+		Col{Name: "id", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "operator", FieldType: consts.FieldTypeVarString},
+		Col{Name: "group_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "info", FieldType: consts.FieldTypeVarString},
+	}
+	DryRun = Thead{
+		Col{Name: "group_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "query", FieldType: consts.FieldTypeVarString},
+	}
+	ProcessList = Thead{
+		Col{Name: "Id", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "User", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Host", FieldType: consts.FieldTypeVarString},
+		Col{Name: "db", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Command", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Time", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "State", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Info", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Tenant", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Trx_id", FieldType: consts.FieldTypeLongLong},
+	}
+	PoolStatus = Thead{
+		Col{Name: "Group_name", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Node", FieldType: consts.FieldTypeVarString},
+		Col{Name: "Capacity", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Max_capacity", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Available", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Active", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "In_use", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Wait_count", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Wait_time_ms", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Idle_closed", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Expired", FieldType: consts.FieldTypeLongLong},
+		Col{Name: "Invalid", FieldType: consts.FieldTypeLongLong},
+	}
 )
 
 type Col struct {
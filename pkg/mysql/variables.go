@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql
+
+import (
+	"sort"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+// names of the special session variables
+const (
+	// VariableNames is the name of variable which is set by SET NAMES, the value is like "utf8mb4 COLLATE utf8mb4_bin".
+	VariableNames = "names"
+	// VariableCharset is the name of variable which is set by SET CHARACTER SET.
+	VariableCharset = "charset"
+)
+
+// SyncVariables applies the session variables on the backend connection.
+// The variables which were applied before but not included any more will be reset to default.
+func (conn *BackendConnection) SyncVariables(variables map[string]string) error {
+	var (
+		resets []string
+		sets   []string
+	)
+
+	for name := range conn.variables {
+		if _, ok := variables[name]; !ok {
+			resets = append(resets, name)
+		}
+	}
+	for name, value := range variables {
+		if prev, ok := conn.variables[name]; !ok || prev != value {
+			sets = append(sets, name)
+		}
+	}
+
+	if len(resets) == 0 && len(sets) == 0 {
+		return nil
+	}
+
+	var (
+		assignments = make([]string, 0, len(resets)+len(sets))
+		reset       bool // the charset is reset already
+	)
+
+	for _, name := range sortVariables(resets) {
+		switch name {
+		case VariableNames, VariableCharset:
+			if reset {
+				continue
+			}
+			reset = true
+			assignments = append(assignments, conn.defaultNames())
+		default:
+			assignments = append(assignments, formatVariable(name, "DEFAULT"))
+		}
+	}
+	for _, name := range sortVariables(sets) {
+		assignments = append(assignments, formatVariable(name, variables[name]))
+	}
+
+	// mark as unknown state before executing, the connection should be discarded if failed
+	conn.variables = nil
+
+	res, err := conn.ExecuteWithWarningCount("SET "+strings.Join(assignments, ", "), false)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = res.RowsAffected(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	applied := make(map[string]string, len(variables))
+	for k, v := range variables {
+		applied[k] = v
+	}
+	conn.variables = applied
+
+	return nil
+}
+
+// ResetVariables resets the session variables which are applied by SyncVariables.
+func (conn *BackendConnection) ResetVariables() error {
+	return conn.SyncVariables(nil)
+}
+
+func (conn *BackendConnection) defaultNames() string {
+	collation := conn.conf.Collation
+	charset := collation
+	if i := strings.IndexByte(collation, '_'); i != -1 {
+		charset = collation[:i]
+	}
+	return "NAMES " + charset + " COLLATE " + collation
+}
+
+// sortVariables sorts the names of variables, the charset variables will be placed at first,
+// so that the other character set variables won't be overwritten.
+func sortVariables(names []string) []string {
+	weight := func(name string) int {
+		switch name {
+		case VariableNames, VariableCharset:
+			return 0
+		default:
+			return 1
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if wi, wj := weight(names[i]), weight(names[j]); wi != wj {
+			return wi < wj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func formatVariable(name, value string) string {
+	switch name {
+	case VariableNames:
+		return "NAMES " + value
+	case VariableCharset:
+		return "CHARACTER SET " + value
+	default:
+		return "@@SESSION." + name + " = " + value
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncVariables(t *testing.T) {
+	dsn := "admin:123456@tcp(127.0.0.1:3306)/pass?collation=utf8mb4_general_ci"
+	cfg, _ := ParseDSN(dsn)
+	conn := &BackendConnection{conf: cfg}
+
+	okPacket := []byte{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}
+	mc := &mockConn{queuedReplies: [][]byte{okPacket, okPacket}}
+	conn.c = newConn(mc)
+
+	lastQuery := func() string {
+		defer func() {
+			mc.written = nil
+		}()
+		if len(mc.written) < 5 {
+			return ""
+		}
+		return string(mc.written[5:])
+	}
+
+	// nothing to do
+	assert.NoError(t, conn.ResetVariables())
+	assert.Empty(t, lastQuery())
+
+	assert.NoError(t, conn.SyncVariables(map[string]string{
+		"time_zone":   "'+08:00'",
+		VariableNames: "'utf8mb4'",
+	}))
+	assert.Equal(t, "SET NAMES 'utf8mb4', @@SESSION.time_zone = '+08:00'", lastQuery())
+
+	// no changes
+	assert.NoError(t, conn.SyncVariables(map[string]string{
+		"time_zone":   "'+08:00'",
+		VariableNames: "'utf8mb4'",
+	}))
+	assert.Empty(t, lastQuery())
+
+	assert.NoError(t, conn.ResetVariables())
+	assert.Equal(t, "SET NAMES utf8mb4 COLLATE utf8mb4_general_ci, @@SESSION.time_zone = DEFAULT", lastQuery())
+}
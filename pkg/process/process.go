@@ -0,0 +1,269 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+// commands of process
+const (
+	CommandSleep   = "Sleep"
+	CommandQuery   = "Query"
+	CommandExecute = "Execute"
+)
+
+var _processes sync.Map // map[uint32]*Process
+
+type keyProcess struct{}
+
+// Killer kills the query which is executing on a backend connection.
+type Killer func(ctx context.Context) error
+
+// Info represents the snapshot of a process.
+type Info struct {
+	ID      uint32
+	Tenant  string
+	User    string
+	Host    string
+	Schema  string
+	Command string
+	Time    time.Duration // elapsed time of current command
+	SQL     string
+	TxID    int64 // the id of opening transaction, zero if no transaction
+}
+
+// Process represents a frontend connection of proxy.
+type Process struct {
+	id     uint32
+	tenant string
+	user   string
+	host   string
+	closer func()
+
+	mu       sync.Mutex
+	schema   string
+	command  string
+	sql      string
+	startAt  time.Time
+	txID     int64
+	cancel   context.CancelFunc
+	seq      uint64
+	backends map[uint64]Killer
+}
+
+// Register registers a new process, the closer will be called when the process is killed.
+func Register(id uint32, tenant, user, host string, closer func()) *Process {
+	p := &Process{
+		id:       id,
+		tenant:   tenant,
+		user:     user,
+		host:     host,
+		closer:   closer,
+		command:  CommandSleep,
+		startAt:  time.Now(),
+		backends: make(map[uint64]Killer),
+	}
+	_processes.Store(id, p)
+	return p
+}
+
+// Unregister removes the process.
+func Unregister(id uint32) {
+	_processes.Delete(id)
+}
+
+// Load loads the process by id.
+func Load(id uint32) (*Process, bool) {
+	exist, ok := _processes.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return exist.(*Process), true
+}
+
+// List returns the processes which belong to the tenant, all processes will be returned if tenant is empty.
+func List(tenant string) []Info {
+	var ret []Info
+	_processes.Range(func(_, value interface{}) bool {
+		p := value.(*Process)
+		if len(tenant) < 1 || p.tenant == tenant {
+			ret = append(ret, p.Info())
+		}
+		return true
+	})
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+// Kill kills the process of tenant. If query is true, only the executing statement will be terminated,
+// otherwise the connection will be closed too. The processes of other users can be killed by admin only.
+func Kill(ctx context.Context, tenant, user string, admin bool, id uint32, query bool) error {
+	p, ok := Load(id)
+	if !ok || p.tenant != tenant {
+		return mysqlErrors.NewSQLError(mysql.ERNoSuchThread, mysql.SSUnknownSQLState, "Unknown thread id: %d", id)
+	}
+	if !admin && p.user != user {
+		return mysqlErrors.NewSQLError(mysql.ERKillDenied, mysql.SSUnknownSQLState, "You are not owner of thread %d", id)
+	}
+	if query {
+		return p.KillQuery(ctx)
+	}
+	return p.Kill(ctx)
+}
+
+// WithProcess binds the process.
+func WithProcess(ctx context.Context, p *Process) context.Context {
+	return context.WithValue(ctx, keyProcess{}, p)
+}
+
+// FromContext extracts the process.
+func FromContext(ctx context.Context) (*Process, bool) {
+	p, ok := ctx.Value(keyProcess{}).(*Process)
+	return p, ok
+}
+
+// ID returns the connection id of process.
+func (p *Process) ID() uint32 {
+	return p.id
+}
+
+// Tenant returns the tenant of process.
+func (p *Process) Tenant() string {
+	return p.tenant
+}
+
+// SetSchema sets the current schema.
+func (p *Process) SetSchema(schema string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.schema = schema
+}
+
+// SetTxID sets the id of opening transaction, use zero if the transaction is finished.
+func (p *Process) SetTxID(txID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.txID = txID
+}
+
+// Begin marks the beginning of a command, the returned context will be cancelled when the command is killed.
+// The returned function should be called when the command is finished.
+func (p *Process) Begin(ctx context.Context, command, sql string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(WithProcess(ctx, p))
+
+	p.mu.Lock()
+	p.command = command
+	p.sql = sql
+	p.startAt = time.Now()
+	p.cancel = cancel
+	p.mu.Unlock()
+
+	return ctx, func() {
+		cancel()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.command = CommandSleep
+		p.sql = ""
+		p.startAt = time.Now()
+		p.cancel = nil
+	}
+}
+
+// Attach attaches the killer of a backend connection which is used by process.
+// The returned function should be called when the backend connection is released.
+func (p *Process) Attach(killer Killer) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	seq := p.seq
+	p.backends[seq] = killer
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.backends, seq)
+	}
+}
+
+// KillQuery terminates the executing statement, and kills the queries on the backend connections it is using.
+func (p *Process) KillQuery(ctx context.Context) error {
+	p.mu.Lock()
+	cancel := p.cancel
+	killers := make([]Killer, 0, len(p.backends))
+	for _, it := range p.backends {
+		killers = append(killers, it)
+	}
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	var err error
+	for _, kill := range killers {
+		if e := kill(ctx); e != nil {
+			log.Errorf("failed to kill backend query of process#%d: %v", p.id, e)
+			if err == nil {
+				err = perrors.WithStack(e)
+			}
+		}
+	}
+	return err
+}
+
+// Kill terminates the executing statement, then closes the connection.
+func (p *Process) Kill(ctx context.Context) error {
+	err := p.KillQuery(ctx)
+	if p.closer != nil {
+		p.closer()
+	}
+	return err
+}
+
+// Info returns the snapshot of process.
+func (p *Process) Info() Info {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return Info{
+		ID:      p.id,
+		Tenant:  p.tenant,
+		User:    p.user,
+		Host:    p.host,
+		Schema:  p.schema,
+		Command: p.command,
+		Time:    time.Since(p.startAt),
+		SQL:     p.sql,
+		TxID:    p.txID,
+	}
+}
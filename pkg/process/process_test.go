@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+)
+
+func TestProcess(t *testing.T) {
+	var closed bool
+	p := Register(1, "fake-tenant", "fake-user", "127.0.0.1:3306", func() {
+		closed = true
+	})
+	defer Unregister(1)
+
+	p.SetSchema("employees")
+
+	list := List("fake-tenant")
+	assert.Len(t, list, 1)
+	assert.Equal(t, CommandSleep, list[0].Command)
+	assert.Equal(t, "employees", list[0].Schema)
+	assert.Empty(t, List("other-tenant"))
+
+	ctx, done := p.Begin(context.Background(), CommandQuery, "select sleep(10)")
+	p.SetTxID(100)
+
+	actual, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, p, actual)
+
+	info := p.Info()
+	assert.Equal(t, CommandQuery, info.Command)
+	assert.Equal(t, "select sleep(10)", info.SQL)
+	assert.Equal(t, int64(100), info.TxID)
+
+	var killed []string
+	detach1 := p.Attach(func(ctx context.Context) error {
+		killed = append(killed, "backend-1")
+		return nil
+	})
+	detach2 := p.Attach(func(ctx context.Context) error {
+		killed = append(killed, "backend-2")
+		return nil
+	})
+	detach2()
+
+	// the process of other tenant cannot be killed
+	err := Kill(context.Background(), "other-tenant", "fake-user", true, 1, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1094, err.(*mysqlErrors.SQLError).Num)
+
+	// the process of other user cannot be killed by non-admin
+	err = Kill(context.Background(), "fake-tenant", "other-user", false, 1, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1095, err.(*mysqlErrors.SQLError).Num)
+	assert.NoError(t, ctx.Err())
+
+	assert.NoError(t, Kill(context.Background(), "fake-tenant", "fake-user", false, 1, true))
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, []string{"backend-1"}, killed)
+	assert.False(t, closed)
+
+	detach1()
+	done()
+	assert.Equal(t, CommandSleep, p.Info().Command)
+	assert.Empty(t, p.Info().SQL)
+
+	assert.NoError(t, Kill(context.Background(), "fake-tenant", "admin", true, 1, false))
+	assert.True(t, closed)
+}
@@ -32,20 +32,26 @@ import (
 )
 
 const (
-	_            Type = iota
-	TypeMaster        // force route to master node
-	TypeSlave         // force route to slave node
-	TypeRoute         // custom route
-	TypeFullScan      // enable full-scan
-	TypeDirect        // direct route
+	_             Type = iota
+	TypeMaster         // force route to master node
+	TypeSlave          // force route to slave node
+	TypeRoute          // custom route
+	TypeFullScan       // enable full-scan
+	TypeDirect         // direct route
+	TypeConfirm        // confirm the destructive DDL
+	TypeDryRun         // show the physical statements without executing
+	TypeNodeLabel      // prefer the nodes with specified labels
 )
 
 var _hintTypes = [...]string{
-	TypeMaster:   "MASTER",
-	TypeSlave:    "SLAVE",
-	TypeRoute:    "ROUTE",
-	TypeFullScan: "FULLSCAN",
-	TypeDirect:   "DIRECT",
+	TypeMaster:    "MASTER",
+	TypeSlave:     "SLAVE",
+	TypeRoute:     "ROUTE",
+	TypeFullScan:  "FULLSCAN",
+	TypeDirect:    "DIRECT",
+	TypeConfirm:   "CONFIRM",
+	TypeDryRun:    "DRYRUN",
+	TypeNodeLabel: "NODE_LABEL",
 }
 
 // KeyValue represents a pair of key and value.
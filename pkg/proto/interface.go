@@ -38,6 +38,16 @@ type (
 
 		ConnectionID uint32
 
+		// Username is the user of frontend connection.
+		Username string
+		// RemoteAddr is the address of client.
+		RemoteAddr string
+		// Attributes is the connection attributes sent by client.
+		Attributes map[string]string
+
+		// Variables is the session variables of frontend connection, name -> value.
+		Variables map[string]string
+
 		// sql Data
 		Data []byte
 
@@ -57,13 +67,17 @@ type (
 	// PreFilter
 	PreFilter interface {
 		Filter
-		PreHandle(ctx *Context)
+		// PreHandle handles the statement before execution, the statement can be rewritten by replacing ctx.Stmt.
+		// A non-nil error rejects the statement, use *mysql.SQLError if a specified MySQL error is expected.
+		PreHandle(ctx *Context) error
 	}
 
 	// PostFilter
 	PostFilter interface {
 		Filter
-		PostHandle(ctx *Context, result Result)
+		// PostHandle handles the result of statement, err is the failure of execution if any.
+		// The returned result will be sent to client, it can be wrapped to be notified when it is released.
+		PostHandle(ctx *Context, result Result, err error) Result
 	}
 
 	FilterFactory interface {
@@ -79,6 +93,7 @@ type (
 		ProcessDistributedTransaction() bool
 		InLocalTransaction(ctx *Context) bool
 		InGlobalTransaction(ctx *Context) bool
+		// ExecuteUseDB checks the schema of COM_INIT_DB, and sets the resolved schema into ctx if succeed.
 		ExecuteUseDB(ctx *Context) error
 		ExecuteFieldList(ctx *Context) ([]Field, error)
 		ExecutorComQuery(ctx *Context) (Result, uint16, error)
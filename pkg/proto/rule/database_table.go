@@ -328,3 +328,50 @@ func (dt DatabaseTables) String() string {
 	sb.WriteByte(']')
 	return sb.String()
 }
+
+// RenameTable returns the physical table name after renaming the logical table, the physical name is rebuilt
+// from the new logical name and the suffix, for example: student_0001 -> pupil_0001, emp_db.emp_0001 -> emp_db.staff_0001.
+// The physical name will be returned as is if it doesn't belong to the logical table.
+func RenameTable(physical, table, newTable string) string {
+	prefix, name := "", physical
+	// the schema qualifier, the pattern such as student_${0000..0031} may contain dots
+	if i := strings.IndexByte(physical, '.'); i != -1 && !strings.ContainsRune(physical[:i], '$') {
+		prefix, name = physical[:i+1], physical[i+1:]
+	}
+
+	if !strings.HasPrefix(name, table) {
+		return physical
+	}
+
+	suffix := name[len(table):]
+	if !isShardSuffix(suffix) {
+		// another table which has the same prefix, eg: emp -> employee_0001, t -> t1_0000, t -> t_1_0000
+		return physical
+	}
+
+	return prefix + newTable + suffix
+}
+
+// isShardSuffix checks if the suffix following the logical table is the shard part of physical table,
+// which is empty, the shard number such as _0001, or the pattern such as _${0000..0031}.
+func isShardSuffix(suffix string) bool {
+	if len(suffix) == 0 {
+		return true
+	}
+	if len(suffix) < 2 || suffix[0] != '_' {
+		return false
+	}
+	if strings.HasPrefix(suffix[1:], "${") {
+		return true
+	}
+	for i := 1; i < len(suffix); i++ {
+		if !isDigit(suffix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -152,3 +152,25 @@ func TestDatabaseTables_Smallest(t *testing.T) {
 		})
 	}
 }
+
+func TestRenameTable(t *testing.T) {
+	for _, it := range []struct {
+		physical, table, newTable, expect string
+	}{
+		{"student_0001", "student", "pupil", "pupil_0001"},
+		{"student", "student", "pupil", "pupil"},
+		{"student_${0000..0031}", "student", "pupil", "pupil_${0000..0031}"},
+		{"emp_db.emp_0001", "emp", "staff", "emp_db.staff_0001"},
+		{"employee_0001", "emp", "staff", "employee_0001"},
+		{"teacher_0001", "student", "pupil", "teacher_0001"},
+		{"t1_0000", "t", "s", "t1_0000"},
+		{"t_1_0000", "t", "s", "t_1_0000"},
+		{"t$_0000", "t", "s", "t$_0000"},
+		{"t__0000", "t", "s", "t__0000"},
+		{"t_0000", "t", "s", "s_0000"},
+	} {
+		t.Run(it.physical, func(t *testing.T) {
+			assert.Equal(t, it.expect, RenameTable(it.physical, it.table, it.newTable))
+		})
+	}
+}
@@ -158,6 +158,7 @@ func (vt *VTable) SetName(name string) {
 type Rule struct {
 	mu    sync.RWMutex
 	vtabs map[string]*VTable // table name -> *VTable
+	views map[string]string  // view name -> definition
 }
 
 // HasColumn returns true if the table and columns exists.
@@ -233,3 +234,53 @@ func (ru *Rule) Range(f func(table string, vt *VTable) bool) {
 		}
 	}
 }
+
+// SetView sets the definition of a logical view, the definition should be a SELECT statement.
+func (ru *Rule) SetView(view, definition string) {
+	ru.mu.Lock()
+	if ru.views == nil {
+		ru.views = make(map[string]string)
+	}
+	ru.views[view] = definition
+	ru.mu.Unlock()
+}
+
+// RemoveView removes the logical view with given name.
+func (ru *Rule) RemoveView(view string) {
+	ru.mu.Lock()
+	delete(ru.views, view)
+	ru.mu.Unlock()
+}
+
+// View returns the definition of the logical view with given name.
+func (ru *Rule) View(view string) (string, bool) {
+	if ru == nil {
+		return "", false
+	}
+	ru.mu.RLock()
+	definition, ok := ru.views[view]
+	ru.mu.RUnlock()
+	return definition, ok
+}
+
+// HasViews returns true if any logical view exists.
+func (ru *Rule) HasViews() bool {
+	if ru == nil {
+		return false
+	}
+	ru.mu.RLock()
+	defer ru.mu.RUnlock()
+	return len(ru.views) > 0
+}
+
+// RangeViews ranges each logical view.
+func (ru *Rule) RangeViews(f func(view, definition string) bool) {
+	ru.mu.RLock()
+	defer ru.mu.RUnlock()
+
+	for k, v := range ru.views {
+		if !f(k, v) {
+			break
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination=../../testdata/mock_rule_manager.go -package=testdata . RuleManager
+package proto
+
+import (
+	"context"
+)
+
+var _defaultRuleManager RuleManager
+
+func RegisterRuleManager(m RuleManager) {
+	_defaultRuleManager = m
+}
+
+func LoadRuleManager() RuleManager {
+	cur := _defaultRuleManager
+	if cur == nil {
+		return noopRuleManager{}
+	}
+	return cur
+}
+
+// RuleManager represents the manager of logical rules, all changes will be persisted into the config center,
+// and the rule of the namespace will be refreshed at once.
+type RuleManager interface {
+	// RenameTable renames the logical table, the table pattern of topology will be renamed too.
+	RenameTable(ctx context.Context, schema, table, newTable string) error
+	// RenameColumn renames the shard column of logical table, do nothing if the column is not a shard key.
+	RenameColumn(ctx context.Context, schema, table, column, newColumn string) error
+	// CreateView creates a logical view, the existing view will be replaced if orReplace is true.
+	CreateView(ctx context.Context, schema, view, definition string, orReplace bool) error
+	// DropView drops the logical view, do nothing if the view doesn't exist and ifExists is true.
+	DropView(ctx context.Context, schema, view string, ifExists bool) error
+}
+
+type noopRuleManager struct{}
+
+func (n noopRuleManager) RenameTable(_ context.Context, _, _, _ string) error {
+	return nil
+}
+
+func (n noopRuleManager) RenameColumn(_ context.Context, _, _, _, _ string) error {
+	return nil
+}
+
+func (n noopRuleManager) CreateView(_ context.Context, _, _, _ string, _ bool) error {
+	return nil
+}
+
+func (n noopRuleManager) DropView(_ context.Context, _, _ string, _ bool) error {
+	return nil
+}
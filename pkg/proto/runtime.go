@@ -83,6 +83,8 @@ type (
 		Capacity() int
 		// Weight returns the weight.
 		Weight() Weight
+		// Labels returns the labels, eg: zone=shanghai.
+		Labels() map[string]string
 		// SetCapacity sets the capacity.
 		SetCapacity(capacity int) error
 		// SetMaxCapacity sets the max capacity.
@@ -93,6 +95,14 @@ type (
 		SetWeight(weight Weight) error
 	}
 
+	// DBStats represents the runtime statistics of DB, which is used by the adaptive load balance.
+	DBStats interface {
+		// ActiveRequests returns the count of in-flight requests.
+		ActiveRequests() int64
+		// Latency returns the EWMA of call durations.
+		Latency() time.Duration
+	}
+
 	// Executable represents an executor which can send sql request.
 	Executable interface {
 		// Execute executes the sql context.
@@ -15,11 +15,12 @@
  * limitations under the License.
  */
 
-//go:generate mockgen -destination=../../testdata/mock_schema.go -package=testdata . SchemaLoader
+//go:generate mockgen -destination=../../testdata/mock_schema.go -package=testdata . SchemaLoader,SchemaChecker
 package proto
 
 import (
 	"context"
+	"fmt"
 	"strings"
 )
 
@@ -69,6 +70,25 @@ type IndexMetadata struct {
 	Name string
 }
 
+const (
+	SchemaDriftTable  = "table"
+	SchemaDriftColumn = "column"
+	SchemaDriftIndex  = "index"
+)
+
+// SchemaDrift represents a difference between a physical table and the other physical tables of same logical table.
+type SchemaDrift struct {
+	Group  string
+	Table  string
+	Kind   string // table, column or index
+	Name   string
+	Detail string
+}
+
+func (d *SchemaDrift) String() string {
+	return fmt.Sprintf("%s.%s: %s `%s` %s", d.Group, d.Table, d.Kind, d.Name, d.Detail)
+}
+
 var _defaultSchemaLoader SchemaLoader
 
 func RegisterSchemaLoader(l SchemaLoader) {
@@ -89,6 +109,32 @@ type SchemaLoader interface {
 	Load(ctx context.Context, schema string, table []string) (map[string]*TableMetadata, error)
 }
 
+var _defaultSchemaChecker SchemaChecker
+
+func RegisterSchemaChecker(c SchemaChecker) {
+	_defaultSchemaChecker = c
+}
+
+func LoadSchemaChecker() SchemaChecker {
+	cur := _defaultSchemaChecker
+	if cur == nil {
+		return noopSchemaChecker{}
+	}
+	return cur
+}
+
+// SchemaChecker checks whether the physical tables of a logical table have the same columns and indexes.
+type SchemaChecker interface {
+	// CheckTable checks the physical tables of a logical table, returns the drifts.
+	CheckTable(ctx context.Context, schema, table string) ([]*SchemaDrift, error)
+}
+
+type noopSchemaChecker struct{}
+
+func (n noopSchemaChecker) CheckTable(_ context.Context, _, _ string) ([]*SchemaDrift, error) {
+	return nil, nil
+}
+
 type noopSchemaLoader struct{}
 
 func (n noopSchemaLoader) Load(_ context.Context, _ string, _ []string) (map[string]*TableMetadata, error) {
@@ -17,6 +17,10 @@
 
 package resultx
 
+import (
+	"sync"
+)
+
 import (
 	"github.com/arana-db/arana/pkg/proto"
 )
@@ -26,6 +30,7 @@ var (
 	_ proto.Result = (*slimResult)(nil)  // only contains rows-affected and last-insert-id, design for exec
 	_ proto.Result = (*dsResult)(nil)    // only contains dataset, design for query
 	_ proto.Result = (*fullResult)(nil)  // contains all
+	_ proto.Result = (*hookResult)(nil)  // calls the hook when it is released
 )
 
 type option struct {
@@ -155,3 +160,39 @@ func Drain(result proto.Result) {
 	}
 	return
 }
+
+// OnClose wraps the result, the hook will be called once after the dataset is closed,
+// or it will be called immediately when the result contains no dataset.
+func OnClose(result proto.Result, hook func()) proto.Result {
+	var once sync.Once
+	return hookResult{
+		Result: result,
+		hook: func() {
+			once.Do(hook)
+		},
+	}
+}
+
+type hookResult struct {
+	proto.Result
+	hook func()
+}
+
+func (h hookResult) Dataset() (proto.Dataset, error) {
+	ds, err := h.Result.Dataset()
+	if err != nil || ds == nil {
+		h.hook()
+		return ds, err
+	}
+	return hookDataset{Dataset: ds, hook: h.hook}, nil
+}
+
+type hookDataset struct {
+	proto.Dataset
+	hook func()
+}
+
+func (h hookDataset) Close() error {
+	defer h.hook()
+	return h.Dataset.Close()
+}
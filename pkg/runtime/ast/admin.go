@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	_ Statement = (*ShowDDLJobsStatement)(nil)
+	_ Statement = (*ShowDDLJobQueriesStatement)(nil)
+	_ Statement = (*CancelDDLJobsStatement)(nil)
+	_ Statement = (*CheckTableStatement)(nil)
+)
+
+// ShowDDLJobsStatement represents the statement which shows the sharding DDL jobs, eg: ADMIN SHOW DDL JOBS 10
+type ShowDDLJobsStatement struct {
+	Limit int64
+}
+
+func (s *ShowDDLJobsStatement) Restore(_ RestoreFlag, sb *strings.Builder, _ *[]int) error {
+	sb.WriteString("ADMIN SHOW DDL JOBS")
+	if s.Limit > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(strconv.FormatInt(s.Limit, 10))
+	}
+	return nil
+}
+
+func (s *ShowDDLJobsStatement) CntParams() int {
+	return 0
+}
+
+func (s *ShowDDLJobsStatement) Mode() SQLType {
+	return SQLTypeShowDDLJobs
+}
+
+// ShowDDLJobQueriesStatement represents the statement which shows the physical statements of sharding DDL jobs,
+// eg: ADMIN SHOW DDL JOB QUERIES 1, 2
+type ShowDDLJobQueriesStatement struct {
+	JobIDs []int64
+}
+
+func (s *ShowDDLJobQueriesStatement) Restore(_ RestoreFlag, sb *strings.Builder, _ *[]int) error {
+	sb.WriteString("ADMIN SHOW DDL JOB QUERIES ")
+	writeJobIDs(sb, s.JobIDs)
+	return nil
+}
+
+func (s *ShowDDLJobQueriesStatement) CntParams() int {
+	return 0
+}
+
+func (s *ShowDDLJobQueriesStatement) Mode() SQLType {
+	return SQLTypeShowDDLJobQueries
+}
+
+// CancelDDLJobsStatement represents the statement which rollbacks sharding DDL jobs, eg: ADMIN CANCEL DDL JOBS 1, 2
+type CancelDDLJobsStatement struct {
+	JobIDs []int64
+}
+
+func (s *CancelDDLJobsStatement) Restore(_ RestoreFlag, sb *strings.Builder, _ *[]int) error {
+	sb.WriteString("ADMIN CANCEL DDL JOBS ")
+	writeJobIDs(sb, s.JobIDs)
+	return nil
+}
+
+func (s *CancelDDLJobsStatement) CntParams() int {
+	return 0
+}
+
+func (s *CancelDDLJobsStatement) Mode() SQLType {
+	return SQLTypeCancelDDLJobs
+}
+
+// CheckTableStatement represents the statement which checks whether the physical tables of
+// logical tables are consistent, eg: ADMIN CHECK TABLE student
+type CheckTableStatement struct {
+	Tables []TableName
+}
+
+func (s *CheckTableStatement) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int) error {
+	sb.WriteString("ADMIN CHECK TABLE ")
+	for i, it := range s.Tables {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if err := it.Restore(flag, sb, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CheckTableStatement) CntParams() int {
+	return 0
+}
+
+func (s *CheckTableStatement) Mode() SQLType {
+	return SQLTypeCheckTable
+}
+
+func writeJobIDs(sb *strings.Builder, ids []int64) {
+	for i, id := range ids {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.FormatInt(id, 10))
+	}
+}
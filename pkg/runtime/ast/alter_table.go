@@ -21,6 +21,10 @@ import (
 	"strings"
 )
 
+import (
+	"github.com/pkg/errors"
+)
+
 var _ Statement = (*AlterTableStatement)(nil)
 
 type AlterTableType uint8
@@ -34,6 +38,8 @@ const (
 	AlterTableModifyColumn
 	AlterTableRenameTable
 	AlterTableRenameColumn
+	AlterTableDropIndex
+	AlterTableDropPrimaryKey
 )
 
 type AlterTableSpecStatement struct {
@@ -42,6 +48,7 @@ type AlterTableSpecStatement struct {
 	NewColumnName ColumnNameExpressionAtom
 	NewColumns    []*ColumnDefine
 	NewTable      TableName
+	IndexName     string
 	Position      *ColumnPosition
 	Constraint    *Constraint
 }
@@ -122,6 +129,11 @@ func (a *AlterTableSpecStatement) Restore(flag RestoreFlag, sb *strings.Builder,
 		if err := a.NewColumnName.Restore(flag, sb, args); err != nil {
 			return err
 		}
+	case AlterTableDropIndex:
+		sb.WriteString("DROP INDEX ")
+		WriteID(sb, a.IndexName)
+	case AlterTableDropPrimaryKey:
+		sb.WriteString("DROP PRIMARY KEY")
 	}
 
 	return nil
@@ -149,6 +161,115 @@ func (at *AlterTableStatement) ResetTable(table string) *AlterTableStatement {
 	return ret
 }
 
+// ResetNewTable resets the new table name of RENAME spec.
+func (at *AlterTableStatement) ResetNewTable(table string) *AlterTableStatement {
+	ret := new(AlterTableStatement)
+	*ret = *at
+
+	specs := make([]*AlterTableSpecStatement, 0, len(at.Specs))
+	for _, spec := range at.Specs {
+		if spec.Tp == AlterTableRenameTable {
+			renamed := new(AlterTableSpecStatement)
+			*renamed = *spec
+			renamed.NewTable = spec.NewTable.ResetSuffix(table)
+			spec = renamed
+		}
+		specs = append(specs, spec)
+	}
+
+	ret.Specs = specs
+	return ret
+}
+
+// RenamedTable returns the new table name if the table will be renamed.
+func (at *AlterTableStatement) RenamedTable() (TableName, bool) {
+	for i := len(at.Specs) - 1; i >= 0; i-- {
+		if at.Specs[i].Tp == AlterTableRenameTable {
+			return at.Specs[i].NewTable, true
+		}
+	}
+	return nil, false
+}
+
+// RenamedColumns returns the renamed columns, the key is the old column name and the value is the new column name.
+func (at *AlterTableStatement) RenamedColumns() map[string]string {
+	var ret map[string]string
+	for _, spec := range at.Specs {
+		var from, to string
+		switch spec.Tp {
+		case AlterTableRenameColumn:
+			from, to = spec.OldColumnName.Suffix(), spec.NewColumnName.Suffix()
+		case AlterTableChangeColumn:
+			from, to = spec.OldColumnName.Suffix(), spec.NewColumns[0].Column.Suffix()
+		default:
+			continue
+		}
+		if strings.EqualFold(from, to) {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]string)
+		}
+		ret[from] = to
+	}
+	return ret
+}
+
+// Reverse returns the statement which undoes current statement, returns an error if it's irreversible.
+// The reverse statement should be executed on the renamed table if the table is renamed.
+func (at *AlterTableStatement) Reverse() (*AlterTableStatement, error) {
+	ret := &AlterTableStatement{
+		Table: at.Table,
+	}
+	if newTable, ok := at.RenamedTable(); ok {
+		ret.Table = at.Table.ResetSuffix(newTable.Suffix())
+	}
+
+	for i := len(at.Specs) - 1; i >= 0; i-- {
+		spec := at.Specs[i]
+		switch spec.Tp {
+		case AlterTableAddColumns:
+			for j := len(spec.NewColumns) - 1; j >= 0; j-- {
+				ret.Specs = append(ret.Specs, &AlterTableSpecStatement{
+					Tp:            AlterTableDropColumn,
+					OldColumnName: spec.NewColumns[j].Column,
+				})
+			}
+		case AlterTableAddConstraint:
+			switch {
+			case spec.Constraint.Tp == ConstraintPrimaryKey:
+				ret.Specs = append(ret.Specs, &AlterTableSpecStatement{
+					Tp: AlterTableDropPrimaryKey,
+				})
+			case len(spec.Constraint.Name) > 0:
+				ret.Specs = append(ret.Specs, &AlterTableSpecStatement{
+					Tp:        AlterTableDropIndex,
+					IndexName: spec.Constraint.Name,
+				})
+			default:
+				return nil, errors.New("cannot reverse adding an anonymous index")
+			}
+		case AlterTableRenameColumn:
+			ret.Specs = append(ret.Specs, &AlterTableSpecStatement{
+				Tp:            AlterTableRenameColumn,
+				OldColumnName: spec.NewColumnName,
+				NewColumnName: spec.OldColumnName,
+			})
+		case AlterTableRenameTable:
+			ret.Specs = append(ret.Specs, &AlterTableSpecStatement{
+				Tp:       AlterTableRenameTable,
+				NewTable: spec.NewTable.ResetSuffix(at.Table.Suffix()),
+			})
+		default:
+			var sb strings.Builder
+			_ = spec.Restore(RestoreDefault, &sb, nil)
+			return nil, errors.Errorf("cannot reverse '%s'", sb.String())
+		}
+	}
+
+	return ret, nil
+}
+
 func (at *AlterTableStatement) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int) error {
 	sb.WriteString("ALTER TABLE ")
 	if err := at.Table.Restore(flag, sb, args); err != nil {
@@ -26,6 +26,7 @@ import (
 import (
 	"github.com/arana-db/parser"
 	"github.com/arana-db/parser/ast"
+	"github.com/arana-db/parser/format"
 	"github.com/arana-db/parser/mysql"
 	"github.com/arana-db/parser/opcode"
 	"github.com/arana-db/parser/test_driver"
@@ -99,11 +100,18 @@ func FromStmtNode(node ast.StmtNode) (Statement, error) {
 		case *ShowColumns:
 			return &DescribeStatement{Table: tgt.TableName, Column: tgt.Column}, nil
 		default:
-			return &ExplainStatement{Target: tgt}, nil
+			return &ExplainStatement{
+				Target:  tgt,
+				Analyze: stmt.Analyze,
+				Verbose: strings.EqualFold(stmt.Format, "verbose"),
+			}, nil
 		}
 	case *ast.TruncateTableStmt:
 		return cc.convTruncateTableStmt(stmt), nil
 	case *ast.DropTableStmt:
+		if stmt.IsView {
+			return cc.convDropViewStmt(stmt), nil
+		}
 		return cc.convDropTableStmt(stmt), nil
 	case *ast.AlterTableStmt:
 		return cc.convAlterTableStmt(stmt), nil
@@ -113,6 +121,14 @@ func FromStmtNode(node ast.StmtNode) (Statement, error) {
 		return cc.convDropTrigger(stmt), nil
 	case *ast.CreateIndexStmt:
 		return cc.convCreateIndexStmt(stmt), nil
+	case *ast.RenameTableStmt:
+		return cc.convRenameTableStmt(stmt), nil
+	case *ast.AdminStmt:
+		return cc.convAdminStmt(stmt)
+	case *ast.CreateViewStmt:
+		return cc.convCreateViewStmt(stmt)
+	case *ast.KillStmt:
+		return cc.convKillStmt(stmt), nil
 	default:
 		return nil, errors.Errorf("unimplement: stmt type %T!", stmt)
 	}
@@ -208,6 +224,15 @@ func (cc *convCtx) convAlterTableStmt(stmt *ast.AlterTableStmt) *AlterTableState
 				OldColumnName: cc.convColumn(spec.OldColumnName),
 				NewColumnName: cc.convColumn(spec.NewColumnName),
 			})
+		case ast.AlterTableDropIndex:
+			specs = append(specs, &AlterTableSpecStatement{
+				Tp:        AlterTableDropIndex,
+				IndexName: spec.Name,
+			})
+		case ast.AlterTableDropPrimaryKey:
+			specs = append(specs, &AlterTableSpecStatement{
+				Tp: AlterTableDropPrimaryKey,
+			})
 		}
 	}
 	return &AlterTableStatement{
@@ -333,6 +358,85 @@ func (cc *convCtx) convDropTableStmt(stmt *ast.DropTableStmt) *DropTableStatemen
 	}
 }
 
+func (cc *convCtx) convCreateViewStmt(stmt *ast.CreateViewStmt) (Statement, error) {
+	if len(stmt.Cols) > 0 {
+		return nil, errors.New("unimplement: CREATE VIEW with column list")
+	}
+
+	var sb strings.Builder
+	if err := stmt.Select.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return nil, errors.Wrap(err, "failed to restore view definition")
+	}
+
+	var view TableName
+	if db := stmt.ViewName.Schema.O; len(db) > 0 {
+		view = append(view, db)
+	}
+	view = append(view, stmt.ViewName.Name.O)
+
+	return &CreateViewStatement{
+		OrReplace:  stmt.OrReplace,
+		View:       view,
+		Definition: sb.String(),
+	}, nil
+}
+
+func (cc *convCtx) convDropViewStmt(stmt *ast.DropTableStmt) *DropViewStatement {
+	ret := &DropViewStatement{
+		IfExists: stmt.IfExists,
+	}
+	for _, it := range stmt.Tables {
+		var view TableName
+		if db := it.Schema.O; len(db) > 0 {
+			view = append(view, db)
+		}
+		ret.Views = append(ret.Views, append(view, it.Name.O))
+	}
+	return ret
+}
+
+func (cc *convCtx) convRenameTableStmt(stmt *ast.RenameTableStmt) *RenameTableStatement {
+	convTableName := func(table *ast.TableName) TableName {
+		var tableName TableName
+		if db := table.Schema.O; len(db) > 0 {
+			tableName = append(tableName, db)
+		}
+		return append(tableName, table.Name.O)
+	}
+
+	ret := NewRenameTableStatement()
+	for _, it := range stmt.TableToTables {
+		ret.TableToTables = append(ret.TableToTables, &TableToTable{
+			OldTable: convTableName(it.OldTable),
+			NewTable: convTableName(it.NewTable),
+		})
+	}
+	return ret
+}
+
+func (cc *convCtx) convAdminStmt(stmt *ast.AdminStmt) (Statement, error) {
+	switch stmt.Tp {
+	case ast.AdminShowDDLJobs:
+		return &ShowDDLJobsStatement{Limit: stmt.JobNumber}, nil
+	case ast.AdminShowDDLJobQueries:
+		return &ShowDDLJobQueriesStatement{JobIDs: stmt.JobIDs}, nil
+	case ast.AdminCancelDDLJobs:
+		return &CancelDDLJobsStatement{JobIDs: stmt.JobIDs}, nil
+	case ast.AdminCheckTable:
+		tables := make([]TableName, 0, len(stmt.Tables))
+		for _, it := range stmt.Tables {
+			var table TableName
+			if db := it.Schema.O; len(db) > 0 {
+				table = append(table, db)
+			}
+			tables = append(tables, append(table, it.Name.O))
+		}
+		return &CheckTableStatement{Tables: tables}, nil
+	default:
+		return nil, errors.Errorf("unimplement: admin statement type %d!", stmt.Tp)
+	}
+}
+
 func (cc *convCtx) convUpdateStmt(stmt *ast.UpdateStmt) *UpdateStatement {
 	var ret UpdateStatement
 	switch stmt.Priority {
@@ -576,6 +680,13 @@ func (cc *convCtx) convTruncateTableStmt(node *ast.TruncateTableStmt) Statement
 	}
 }
 
+func (cc *convCtx) convKillStmt(node *ast.KillStmt) Statement {
+	return &KillStatement{
+		Query:        node.Query,
+		ConnectionID: node.ConnectionID,
+	}
+}
+
 func (cc *convCtx) convShowStmt(node *ast.ShowStmt) Statement {
 	toIn := func(node *ast.ShowStmt) (string, bool) {
 		if node.DBName == "" {
@@ -679,6 +790,8 @@ func (cc *convCtx) convShowStmt(node *ast.ShowStmt) Statement {
 			global:   node.GlobalScope,
 		}
 		return ret
+	case ast.ShowProcessList:
+		return &ShowProcessList{Full: node.Full}
 	default:
 		panic(fmt.Sprintf("unimplement: show type %v!", node.Tp))
 	}
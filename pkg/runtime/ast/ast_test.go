@@ -257,6 +257,16 @@ func TestParse_ExplainStmt(t *testing.T) {
 	assert.IsType(t, (*ExplainStatement)(nil), stmt)
 	s := MustRestoreToString(RestoreDefault, stmt)
 	assert.Equal(t, "EXPLAIN SELECT * FROM `student` WHERE `uid` = 1", s)
+
+	_, stmt, err = Parse("explain format = verbose delete from student where uid = 1")
+	assert.NoError(t, err)
+	assert.True(t, stmt.(*ExplainStatement).Verbose)
+	assert.False(t, stmt.(*ExplainStatement).Analyze)
+	assert.Equal(t, "EXPLAIN FORMAT = VERBOSE DELETE FROM `student` WHERE `uid` = 1", MustRestoreToString(RestoreDefault, stmt))
+
+	_, stmt, err = Parse("explain analyze update student set name = 'foo' where uid = 1")
+	assert.NoError(t, err)
+	assert.True(t, stmt.(*ExplainStatement).Analyze)
 }
 
 func TestParseMore(t *testing.T) {
@@ -429,6 +439,10 @@ func TestParse_AlterTableStmt(t *testing.T) {
 			"alter table student rename column name to nickname, rename column nickname to name",
 			"ALTER TABLE `student` RENAME COLUMN `name` TO `nickname`, RENAME COLUMN `nickname` TO `name`",
 		},
+		{
+			"alter table student drop index idx_name, drop primary key",
+			"ALTER TABLE `student` DROP INDEX `idx_name`, DROP PRIMARY KEY",
+		},
 	} {
 		t.Run(it.input, func(t *testing.T) {
 			_, stmt, err := Parse(it.input)
@@ -442,6 +456,63 @@ func TestParse_AlterTableStmt(t *testing.T) {
 	}
 }
 
+func TestAlterTableStatement_Reverse(t *testing.T) {
+	type tt struct {
+		input  string
+		expect string
+	}
+
+	for _, it := range []tt{
+		{
+			"alter table student add dept_id int not null default 0, add index idx_dept (dept_id)",
+			"ALTER TABLE `student` DROP INDEX `idx_dept`, DROP COLUMN `dept_id`",
+		},
+		{
+			"alter table student rename column name to nickname, rename to students",
+			"ALTER TABLE `students` RENAME AS `student`, RENAME COLUMN `nickname` TO `name`",
+		},
+		{
+			"alter table student drop nickname",
+			"",
+		},
+	} {
+		t.Run(it.input, func(t *testing.T) {
+			_, stmt, err := Parse(it.input)
+			assert.NoError(t, err)
+
+			reverse, err := stmt.(*AlterTableStatement).Reverse()
+			if len(it.expect) < 1 {
+				assert.Error(t, err, "should be irreversible")
+				return
+			}
+			assert.NoError(t, err)
+
+			actual, err := RestoreToString(RestoreDefault, reverse)
+			assert.NoError(t, err, "should restore ok")
+			assert.Equal(t, it.expect, actual)
+		})
+	}
+}
+
+func TestParse_AdminStmt(t *testing.T) {
+	for _, it := range []string{
+		"ADMIN SHOW DDL JOBS",
+		"ADMIN SHOW DDL JOBS 10",
+		"ADMIN SHOW DDL JOB QUERIES 1, 2",
+		"ADMIN CANCEL DDL JOBS 3",
+		"ADMIN CHECK TABLE `student`, `employees`.`salaries`",
+	} {
+		t.Run(it, func(t *testing.T) {
+			_, stmt, err := Parse(it)
+			assert.NoError(t, err)
+
+			actual, err := RestoreToString(RestoreDefault, stmt.(Restorer))
+			assert.NoError(t, err)
+			assert.Equal(t, it, actual)
+		})
+	}
+}
+
 func TestParse_DescStmt(t *testing.T) {
 	_, stmt := MustParse("desc student id")
 	// In MySQL, the case of "desc student 'id'" will be parsed successfully,
@@ -452,3 +523,21 @@ func TestParse_DescStmt(t *testing.T) {
 	t.Logf(sb.String())
 	assert.Equal(t, "DESC `student` `id`", sb.String())
 }
+
+func TestParse_ProcessStmt(t *testing.T) {
+	for _, it := range []string{
+		"SHOW PROCESSLIST",
+		"SHOW FULL PROCESSLIST",
+		"KILL 42",
+		"KILL QUERY 42",
+	} {
+		t.Run(it, func(t *testing.T) {
+			_, stmt, err := Parse(it)
+			assert.NoError(t, err)
+
+			actual, err := RestoreToString(RestoreDefault, stmt.(Restorer))
+			assert.NoError(t, err)
+			assert.Equal(t, it, actual)
+		})
+	}
+}
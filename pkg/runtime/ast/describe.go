@@ -60,11 +60,19 @@ func (d *DescribeStatement) Mode() SQLType {
 
 // ExplainStatement represents mysql explain statement. see https://dev.mysql.com/doc/refman/8.0/en/explain.html
 type ExplainStatement struct {
-	Target Statement
+	Target  Statement
+	Analyze bool // EXPLAIN ANALYZE, which executes the statement in mysql, so it is not supported
+	Verbose bool // append the EXPLAIN outputs of backends if true, eg: EXPLAIN FORMAT = VERBOSE SELECT ...
 }
 
 func (e *ExplainStatement) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int) error {
 	sb.WriteString("EXPLAIN ")
+	switch {
+	case e.Analyze:
+		sb.WriteString("ANALYZE ")
+	case e.Verbose:
+		sb.WriteString("FORMAT = VERBOSE ")
+	}
 	if err := e.Target.Restore(flag, sb, args); err != nil {
 		return errors.WithStack(err)
 	}
@@ -76,5 +84,5 @@ func (e *ExplainStatement) CntParams() int {
 }
 
 func (e *ExplainStatement) Mode() SQLType {
-	return SQLTypeSelect
+	return SQLTypeExplain
 }
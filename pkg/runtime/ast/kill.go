@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"strconv"
+	"strings"
+)
+
+var _ Statement = (*KillStatement)(nil)
+
+// KillStatement represents the statement which kills a connection or the statement it is executing,
+// eg: KILL QUERY 42
+type KillStatement struct {
+	Query        bool
+	ConnectionID uint64
+}
+
+func (k *KillStatement) Restore(_ RestoreFlag, sb *strings.Builder, _ *[]int) error {
+	sb.WriteString("KILL ")
+	if k.Query {
+		sb.WriteString("QUERY ")
+	}
+	sb.WriteString(strconv.FormatUint(k.ConnectionID, 10))
+	return nil
+}
+
+func (k *KillStatement) CntParams() int {
+	return 0
+}
+
+func (k *KillStatement) Mode() SQLType {
+	return SQLTypeKill
+}
@@ -34,6 +34,7 @@ var (
 	_ Statement = (*ShowColumns)(nil)
 	_ Statement = (*ShowIndex)(nil)
 	_ Statement = (*ShowTopology)(nil)
+	_ Statement = (*ShowProcessList)(nil)
 )
 
 type FromTable string
@@ -411,3 +412,25 @@ func (s *ShowStatus) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int)
 func (s *ShowStatus) Mode() SQLType {
 	return SQLTypeShowStatus
 }
+
+// ShowProcessList represents the statement which shows the frontend connections, eg: SHOW FULL PROCESSLIST
+type ShowProcessList struct {
+	Full bool
+}
+
+func (s *ShowProcessList) Restore(_ RestoreFlag, sb *strings.Builder, _ *[]int) error {
+	sb.WriteString("SHOW ")
+	if s.Full {
+		sb.WriteString("FULL ")
+	}
+	sb.WriteString("PROCESSLIST")
+	return nil
+}
+
+func (s *ShowProcessList) CntParams() int {
+	return 0
+}
+
+func (s *ShowProcessList) Mode() SQLType {
+	return SQLTypeShowProcessList
+}
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+var (
+	_ Statement = (*CreateViewStatement)(nil)
+	_ Statement = (*DropViewStatement)(nil)
+)
+
+// CreateViewStatement represents the statement which creates a logical view, eg: CREATE VIEW v AS SELECT ...
+type CreateViewStatement struct {
+	OrReplace  bool
+	View       TableName
+	Definition string // the SELECT statement of view
+}
+
+func (c *CreateViewStatement) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int) error {
+	sb.WriteString("CREATE ")
+	if c.OrReplace {
+		sb.WriteString("OR REPLACE ")
+	}
+	sb.WriteString("VIEW ")
+	if err := c.View.Restore(flag, sb, args); err != nil {
+		return errors.WithStack(err)
+	}
+	sb.WriteString(" AS ")
+	sb.WriteString(c.Definition)
+	return nil
+}
+
+func (c *CreateViewStatement) CntParams() int {
+	return 0
+}
+
+func (c *CreateViewStatement) Mode() SQLType {
+	return SQLTypeCreateView
+}
+
+// DropViewStatement represents the statement which drops logical views, eg: DROP VIEW IF EXISTS v1, v2
+type DropViewStatement struct {
+	IfExists bool
+	Views    []TableName
+}
+
+func (d *DropViewStatement) Restore(flag RestoreFlag, sb *strings.Builder, args *[]int) error {
+	sb.WriteString("DROP VIEW ")
+	if d.IfExists {
+		sb.WriteString("IF EXISTS ")
+	}
+	for i, it := range d.Views {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if err := it.Restore(flag, sb, args); err != nil {
+			return errors.Wrapf(err, "failed to restore DropViewStatement.Views[%d]", i)
+		}
+	}
+	return nil
+}
+
+func (d *DropViewStatement) CntParams() int {
+	return 0
+}
+
+func (d *DropViewStatement) Mode() SQLType {
+	return SQLTypeDropView
+}
@@ -19,6 +19,9 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 )
 
 import (
@@ -26,10 +29,21 @@ import (
 )
 
 import (
+	"github.com/arana-db/arana/pkg/config"
 	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/util/log"
 	"github.com/arana-db/arana/third_party/pools"
 )
 
+const (
+	_warmupParallelism  = 4
+	_warmupTimeout      = 30 * time.Second
+	_validateTimeout    = 3 * time.Second
+	_maxValidateRetries = 3
+)
+
+var errConnExpired = errors.New("the max lifetime of backend connection is exceeded")
+
 type BackendResourcePool pools.ResourcePool
 
 // Get will return the next available resource and convert to mysql.BackendConnection.
@@ -57,3 +71,88 @@ func (bcp *BackendResourcePool) Get(ctx context.Context) (*mysql.BackendConnecti
 	}
 	return res.(*mysql.BackendConnection), nil
 }
+
+// poolOptions represents the options of backend connection pool.
+type poolOptions struct {
+	minIdle      int           // count of connections opened in advance
+	testOnBorrow bool          // validate the connection on every borrow
+	pingOnIdle   time.Duration // validate the connection on borrow if it has been idle longer than it
+	maxLifetime  time.Duration // reopen the connection if it has been opened longer than it
+}
+
+func newPoolOptions(connProps map[string]interface{}, capacity, maxCapacity int) poolOptions {
+	opts := poolOptions{
+		minIdle:      config.GetConnPropMinIdle(connProps, capacity),
+		testOnBorrow: config.GetConnPropTestOnBorrow(connProps, false),
+		pingOnIdle:   config.GetConnPropPingOnIdle(connProps, 0),
+		maxLifetime:  config.GetConnPropMaxLifetime(connProps, 0),
+	}
+	if opts.minIdle > maxCapacity {
+		opts.minIdle = maxCapacity
+	}
+	return opts
+}
+
+// warmup opens the connections of pool in advance, the capacity will be increased if it is less than the size.
+// The borrows of warmup bypass the accounting of pending requests, so they never skew the load balancing.
+func (db *AtomDB) warmup(ctx context.Context, size int) {
+	if size < 1 {
+		return
+	}
+
+	var (
+		bcp   = (*BackendResourcePool)(db.pool)
+		sem   = make(chan struct{}, _warmupParallelism)
+		conns = make(chan *mysql.BackendConnection, size)
+		wg    sync.WaitGroup
+	)
+
+	// hold all the connections until warmup is finished, so that each borrow opens a new one
+	for i := 0; i < size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			bc, err := bcp.Get(ctx)
+			if err != nil {
+				log.Warnf("failed to warm up connection pool of backend '%s': %v", db.id, err)
+				return
+			}
+			conns <- bc
+		}()
+	}
+	wg.Wait()
+	close(conns)
+
+	for bc := range conns {
+		db.pool.Put(bc)
+	}
+}
+
+// validate checks if the borrowed connection is still usable.
+func (db *AtomDB) validate(bc *mysql.BackendConnection) error {
+	opts := db.poolOptions
+	if opts.maxLifetime > 0 && time.Since(bc.CreatedAt()) >= opts.maxLifetime {
+		db.expired.Inc()
+		return errConnExpired
+	}
+
+	if !opts.testOnBorrow && (opts.pingOnIdle <= 0 || time.Since(bc.IdleSince()) < opts.pingOnIdle) {
+		return nil
+	}
+
+	c := bc.GetDatabaseConn().GetNetConn()
+	_ = c.SetDeadline(time.Now().Add(_validateTimeout))
+	defer func() {
+		_ = c.SetDeadline(time.Time{})
+	}()
+
+	if err := bc.Ping(); err != nil {
+		db.invalid.Inc()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+type keySession struct{}
+
+type binding struct {
+	c *config.Consistency
+	s *Session
+}
+
+// Session tracks the writes of a frontend connection, which is used to guarantee the reads after writes
+// on the same connection can see the writes.
+type Session struct {
+	mu        sync.Mutex
+	lastWrite time.Time
+	gtids     map[string]string // group -> gtid_executed of master captured after the last write
+}
+
+// NewSession creates a Session.
+func NewSession() *Session {
+	return &Session{}
+}
+
+// MarkWrite marks the session has written just now.
+func (s *Session) MarkWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastWrite = time.Now()
+	s.gtids = nil
+}
+
+// dirty returns true if the session has written within the window.
+func (s *Session) dirty(window time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.lastWrite.IsZero() && time.Since(s.lastWrite) < window
+}
+
+// WithSession binds the consistency config and session.
+func WithSession(ctx context.Context, c *config.Consistency, s *Session) context.Context {
+	return context.WithValue(ctx, keySession{}, binding{c: c, s: s})
+}
+
+// Ensure returns the DB for reading which is consistent with the previous writes of session, the given DB will
+// be returned if it is consistent, otherwise the master will be returned.
+func Ensure(ctx context.Context, ns *namespace.Namespace, group string, db proto.DB) proto.DB {
+	b, ok := ctx.Value(keySession{}).(binding)
+	if !ok || b.c == nil || b.s == nil || db == nil {
+		return db
+	}
+
+	if !b.s.dirty(b.c.GetWindow()) {
+		return db
+	}
+
+	master := ns.DBMaster(ctx, group)
+	if master == nil || master == db {
+		return db
+	}
+
+	switch b.c.Mode {
+	case config.ConsistencyMaster:
+		return master
+	case config.ConsistencyGTID:
+		if err := b.s.waitGTID(ctx, b.c.GetTimeout(), group, master, db); err != nil {
+			log.Debugf("[%s] route read of group %s to master %s: %v", ns.Name(), group, master.ID(), err)
+			return master
+		}
+	}
+
+	return db
+}
+
+// waitGTID waits the replica to execute all transactions of master which have been executed after the last write.
+func (s *Session) waitGTID(ctx context.Context, timeout time.Duration, group string, master, replica proto.DB) error {
+	gtid, err := s.gtidOf(ctx, group, master)
+	if err != nil {
+		return err
+	}
+	if len(gtid) < 1 {
+		return errors.Errorf("empty gtid_executed of %s, GTID may be disabled", master.ID())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*timeout)
+	defer cancel()
+
+	sql := fmt.Sprintf("SELECT WAIT_FOR_EXECUTED_GTID_SET('%s', %.3f)", gtid, timeout.Seconds())
+	ret, err := queryOne(ctx, replica, sql)
+	if err != nil {
+		return err
+	}
+	if ret != "0" {
+		return errors.Errorf("wait for gtid of %s timeout after %s", replica.ID(), timeout)
+	}
+	return nil
+}
+
+// gtidOf returns the gtid_executed of master, it will be cached until next write.
+func (s *Session) gtidOf(ctx context.Context, group string, master proto.DB) (string, error) {
+	s.mu.Lock()
+	gtid, ok := s.gtids[group]
+	s.mu.Unlock()
+	if ok {
+		return gtid, nil
+	}
+
+	ret, err := queryOne(ctx, master, "SELECT @@GLOBAL.gtid_executed")
+	if err != nil {
+		return "", err
+	}
+	// the gtid set is safe to be quoted after removing the line breaks
+	gtid = strings.Join(strings.Fields(ret), "")
+
+	s.mu.Lock()
+	if s.gtids == nil {
+		s.gtids = make(map[string]string)
+	}
+	s.gtids[group] = gtid
+	s.mu.Unlock()
+
+	return gtid, nil
+}
+
+// queryOne executes the sql, and returns the first column of first row as string.
+func queryOne(ctx context.Context, db proto.DB, sql string) (string, error) {
+	res, _, err := db.Call(ctx, sql)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	ds, err := res.Dataset()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if ds == nil {
+		return "", errors.Errorf("no result of '%s'", sql)
+	}
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	fields, err := ds.Fields()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	next, err := ds.Next()
+	if errors.Is(err, io.EOF) {
+		return "", errors.Errorf("no result of '%s'", sql)
+	}
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	dest := make([]proto.Value, len(fields))
+	if err = next.Scan(dest); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	// drain the rest rows
+	for {
+		if _, err = ds.Next(); err != nil {
+			break
+		}
+	}
+
+	switch val := dest[0].(type) {
+	case nil:
+		return "", nil
+	case []byte:
+		return string(val), nil
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consistency
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/config"
+	consts "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+)
+
+type fakeDB struct {
+	proto.DB
+
+	id     string
+	weight proto.Weight
+
+	mu      sync.Mutex
+	gtid    string // the gtid_executed
+	behind  bool   // true if WAIT_FOR_EXECUTED_GTID_SET timeout
+	queries []string
+}
+
+func (f *fakeDB) ID() string {
+	return f.id
+}
+
+func (f *fakeDB) Weight() proto.Weight {
+	return f.weight
+}
+
+func (f *fakeDB) Labels() map[string]string {
+	return nil
+}
+
+func (f *fakeDB) Close() error {
+	return nil
+}
+
+func (f *fakeDB) Call(_ context.Context, sql string, _ ...interface{}) (proto.Result, uint16, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queries = append(f.queries, sql)
+
+	var value proto.Value
+	switch {
+	case sql == "SELECT @@GLOBAL.gtid_executed":
+		value = f.gtid
+	case strings.HasPrefix(sql, "SELECT WAIT_FOR_EXECUTED_GTID_SET("):
+		value = int64(0)
+		if f.behind {
+			value = int64(1)
+		}
+	default:
+		return nil, 0, errors.Errorf("unexpected sql: %s", sql)
+	}
+
+	fields := []proto.Field{mysql.NewField("value", consts.FieldTypeVarString)}
+	return resultx.New(resultx.WithDataset(&dataset.VirtualDataset{
+		Columns: fields,
+		Rows:    []proto.Row{rows.NewTextVirtualRow(fields, []proto.Value{value})},
+	})), 0, nil
+}
+
+func (f *fakeDB) drain() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ret := f.queries
+	f.queries = nil
+	return ret
+}
+
+func TestEnsure(t *testing.T) {
+	const group = "consistency_0000"
+
+	var (
+		master  = &fakeDB{id: "master", weight: proto.Weight{R: 10, W: 10}, gtid: "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5,\n3E11FA47-71CA-11E1-9E33-C80AA9429563:1-3"}
+		replica = &fakeDB{id: "replica", weight: proto.Weight{R: 10, W: 0}}
+	)
+
+	ns, err := namespace.New("consistency", namespace.UpsertDB(group, master), namespace.UpsertDB(group, replica))
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.Equal(t, replica, Ensure(context.Background(), ns, group, replica))
+	})
+
+	t.Run("Master", func(t *testing.T) {
+		var (
+			s   = NewSession()
+			ctx = WithSession(context.Background(), &config.Consistency{Mode: config.ConsistencyMaster, Window: "50ms"}, s)
+		)
+
+		assert.Equal(t, replica, Ensure(ctx, ns, group, replica))
+		s.MarkWrite()
+		assert.Equal(t, master, Ensure(ctx, ns, group, replica))
+		assert.Equal(t, master, Ensure(ctx, ns, group, master))
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, replica, Ensure(ctx, ns, group, replica))
+	})
+
+	t.Run("GTID", func(t *testing.T) {
+		var (
+			s   = NewSession()
+			ctx = WithSession(context.Background(), &config.Consistency{Mode: config.ConsistencyGTID, Timeout: "1s"}, s)
+		)
+
+		assert.Equal(t, replica, Ensure(ctx, ns, group, replica))
+		assert.Empty(t, replica.drain())
+
+		s.MarkWrite()
+		assert.Equal(t, replica, Ensure(ctx, ns, group, replica))
+		assert.Equal(t, []string{"SELECT @@GLOBAL.gtid_executed"}, master.drain())
+		assert.Equal(t, []string{
+			"SELECT WAIT_FOR_EXECUTED_GTID_SET('3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5,3E11FA47-71CA-11E1-9E33-C80AA9429563:1-3', 1.000)",
+		}, replica.drain())
+
+		// the gtid of master is cached until next write
+		assert.Equal(t, replica, Ensure(ctx, ns, group, replica))
+		assert.Empty(t, master.drain())
+		assert.Len(t, replica.drain(), 1)
+
+		// fallback to master if the replica cannot catch up
+		replica.behind = true
+		s.MarkWrite()
+		assert.Equal(t, master, Ensure(ctx, ns, group, replica))
+		assert.Len(t, master.drain(), 1)
+		assert.Len(t, replica.drain(), 1)
+
+		// fallback to master if GTID is disabled
+		replica.behind = false
+		master.gtid = ""
+		s.MarkWrite()
+		assert.Equal(t, master, Ensure(ctx, ns, group, replica))
+		assert.Empty(t, replica.drain())
+	})
+}
@@ -19,6 +19,7 @@ package context
 
 import (
 	"context"
+	"time"
 )
 
 import (
@@ -30,6 +31,7 @@ const (
 	_flagDirect cFlag = 1 << iota
 	_flagRead
 	_flagWrite
+	_flagDenyFullScan
 )
 
 type (
@@ -40,7 +42,10 @@ type (
 	keySchema         struct{}
 	keyDefaultDBGroup struct{}
 	keyTenant         struct{}
+	keyUser           struct{}
 	keyHints          struct{}
+	keyVariables      struct{}
+	keyStartTime      struct{}
 )
 
 type cFlag uint8
@@ -56,6 +61,11 @@ func WithDirect(ctx context.Context) context.Context {
 	return context.WithValue(ctx, keyFlag{}, _flagDirect|getFlag(ctx))
 }
 
+// WithDenyFullScan denies full-scan on sharding tables, even if it is allowed by the table.
+func WithDenyFullScan(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keyFlag{}, _flagDenyFullScan|getFlag(ctx))
+}
+
 // WithSQL binds the original sql.
 func WithSQL(ctx context.Context, sql string) context.Context {
 	return context.WithValue(ctx, keySql{}, sql)
@@ -66,6 +76,11 @@ func WithTenant(ctx context.Context, tenant string) context.Context {
 	return context.WithValue(ctx, keyTenant{}, tenant)
 }
 
+// WithUser binds the username of frontend connection.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, keyUser{}, user)
+}
+
 func WithSchema(ctx context.Context, data string) context.Context {
 	return context.WithValue(ctx, keySchema{}, data)
 }
@@ -90,7 +105,17 @@ func WithHints(ctx context.Context, hints []*hint.Hint) context.Context {
 	return context.WithValue(ctx, keyHints{}, hints)
 }
 
+// WithVariables binds the session variables of frontend connection.
+func WithVariables(ctx context.Context, variables map[string]string) context.Context {
+	return context.WithValue(ctx, keyVariables{}, variables)
+}
+
 // Sequencer extracts the sequencer.
+// WithStartTime binds the time when the statement starts, before any filter is executed.
+func WithStartTime(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, keyStartTime{}, start)
+}
+
 func Sequencer(ctx context.Context) proto.SequenceManager {
 	s, ok := ctx.Value(keySequence{}).(proto.SequenceManager)
 	if !ok {
@@ -108,6 +133,15 @@ func Tenant(ctx context.Context) string {
 	return db
 }
 
+// User extracts the username of frontend connection.
+func User(ctx context.Context) string {
+	user, ok := ctx.Value(keyUser{}).(string)
+	if !ok {
+		return ""
+	}
+	return user
+}
+
 // IsRead returns true if this is a read operation
 func IsRead(ctx context.Context) bool {
 	return hasFlag(ctx, _flagRead)
@@ -123,6 +157,11 @@ func IsDirect(ctx context.Context) bool {
 	return hasFlag(ctx, _flagDirect)
 }
 
+// IsDenyFullScan returns true if full-scan on sharding tables is denied.
+func IsDenyFullScan(ctx context.Context) bool {
+	return hasFlag(ctx, _flagDenyFullScan)
+}
+
 // SQL returns the original sql string.
 func SQL(ctx context.Context) string {
 	if sql, ok := ctx.Value(keySql{}).(string); ok {
@@ -146,6 +185,21 @@ func NodeLabel(ctx context.Context) string {
 	return ""
 }
 
+// Variables extracts the session variables of frontend connection.
+func Variables(ctx context.Context) map[string]string {
+	variables, ok := ctx.Value(keyVariables{}).(map[string]string)
+	if !ok {
+		return nil
+	}
+	return variables
+}
+
+// StartTime extracts the time when the statement starts, returns zero time if not bound.
+func StartTime(ctx context.Context) time.Time {
+	start, _ := ctx.Value(keyStartTime{}).(time.Time)
+	return start
+}
+
 // Hints extracts the hints.
 func Hints(ctx context.Context) []*hint.Hint {
 	hints, ok := ctx.Value(keyHints{}).([]*hint.Hint)
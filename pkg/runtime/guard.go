@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/breaker"
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+// _killGracePeriod is the duration to wait for the backend responding to KILL QUERY,
+// the read of backend connection will be interrupted after it.
+const _killGracePeriod = 3 * time.Second
+
+// callGuard watches a call on the backend connection. It interrupts the call if the statement timeout is exceeded,
+// and reports the outcome to the circuit breaker of backend node.
+type callGuard struct {
+	db          *AtomDB
+	bc          *mysql.BackendConnection
+	timeout     time.Duration
+	timer       *time.Timer
+	fired       atomic.Bool
+	failed      atomic.Bool
+	stopOnce    sync.Once
+	interrupted bool
+	done        func(outcome breaker.Outcome)
+}
+
+// guard starts watching the call on the backend connection.
+func (db *AtomDB) guard(ctx context.Context, bc *mysql.BackendConnection, done func(outcome breaker.Outcome)) *callGuard {
+	g := &callGuard{
+		db:      db,
+		bc:      bc,
+		timeout: db.statementTimeout,
+		done:    done,
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if d := time.Until(deadline); g.timeout <= 0 || d < g.timeout {
+			g.timeout = d
+		}
+	}
+	if g.timeout > 0 {
+		g.timer = time.AfterFunc(g.timeout, g.interrupt)
+	}
+	return g
+}
+
+// interrupt kills the query executing on the backend connection, and unblocks the read if the backend doesn't respond in time.
+func (g *callGuard) interrupt() {
+	g.fired.Store(true)
+
+	c := g.bc.GetDatabaseConn()
+	connectionID := c.ConnectionID
+	log.Warnf("statement on backend '%s' exceeds the timeout %s, kill query %d", g.db.id, g.timeout, connectionID)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), _killGracePeriod)
+		defer cancel()
+		if err := g.db.killQuery(ctx, connectionID); err != nil {
+			log.Warnf("failed to kill query %d on backend '%s': %v", connectionID, g.db.id, err)
+		}
+	}()
+
+	if err := c.GetNetConn().SetReadDeadline(time.Now().Add(_killGracePeriod)); err != nil {
+		log.Warnf("failed to set read deadline of backend connection %d: %v", connectionID, err)
+	}
+}
+
+// mapError converts the error into a statement timeout error if the call is interrupted, and records the backend failure.
+func (g *callGuard) mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if g.fired.Load() {
+		return mysqlErrors.NewSQLError(mConstants.ERQueryTimeout, mConstants.SSUnknownSQLState,
+			"Query execution was interrupted, maximum statement execution time exceeded")
+	}
+	if isBackendFailure(err) {
+		g.failed.Store(true)
+	}
+	return err
+}
+
+// stop stops the timer of statement timeout once the result has been read completely,
+// returns true if the call has been interrupted.
+func (g *callGuard) stop() bool {
+	g.stopOnce.Do(func() {
+		g.interrupted = g.timer != nil && !g.timer.Stop()
+	})
+	return g.interrupted
+}
+
+// release stops watching the call, returns true if the call has been interrupted,
+// which means the backend connection is unusable and should be discarded.
+func (g *callGuard) release() (interrupted bool) {
+	interrupted = g.stop()
+	if interrupted || g.failed.Load() {
+		g.done(breaker.Failure)
+	} else {
+		g.done(breaker.Success)
+	}
+	return
+}
+
+// allow checks if the circuit breaker of backend node allows a call.
+func (db *AtomDB) allow() (func(outcome breaker.Outcome), error) {
+	done, err := db.breaker.Allow()
+	if err != nil {
+		return nil, mysqlErrors.NewSQLError(mConstants.CRConnHostError, mConstants.SSUnknownSQLState,
+			"circuit breaker of backend '%s' is open", db.id)
+	}
+	return done, nil
+}
+
+// outcomeOf returns the outcome of a call which fails before reaching the backend, such as borrowing connection.
+func outcomeOf(err error) breaker.Outcome {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return breaker.Ignored
+	case isBackendFailure(err):
+		return breaker.Failure
+	default:
+		return breaker.Success
+	}
+}
+
+// isBackendFailure returns true if the error is caused by the backend node rather than the statement,
+// the errors returned by mysql server are not considered as failures.
+func isBackendFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var sqlErr *mysqlErrors.SQLError
+	if errors.As(err, &sqlErr) {
+		// client errors range in [2000, 3000), such as lost connection
+		return sqlErr.Num >= mConstants.CRUnknownError && sqlErr.Num < 3000
+	}
+	return true
+}
+
+func logBreakerStateChange(id string) func(from, to breaker.State) {
+	return func(from, to breaker.State) {
+		log.Warnf("circuit breaker of backend '%s' changes from %s to %s", id, from, to)
+	}
+}
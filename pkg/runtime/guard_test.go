@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/breaker"
+	mConstants "github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+)
+
+func TestIsBackendFailure(t *testing.T) {
+	assert.True(t, isBackendFailure(io.ErrUnexpectedEOF))
+	assert.True(t, isBackendFailure(perrors.WithStack(mysqlErrors.NewSQLError(mConstants.CRServerLost, mConstants.SSUnknownSQLState, "lost"))))
+	assert.False(t, isBackendFailure(mysqlErrors.NewSQLError(mConstants.ERDupEntry, mConstants.SSDupKey, "duplicated")))
+	assert.False(t, isBackendFailure(perrors.WithStack(context.Canceled)))
+}
+
+func TestOutcomeOf(t *testing.T) {
+	assert.Equal(t, breaker.Ignored, outcomeOf(perrors.WithStack(context.Canceled)))
+	assert.Equal(t, breaker.Failure, outcomeOf(context.DeadlineExceeded))
+	assert.Equal(t, breaker.Success, outcomeOf(mysqlErrors.NewSQLError(mConstants.ERAccessDeniedError, mConstants.SSAccessDeniedError, "denied")))
+}
+
+func TestCallGuard_MapError(t *testing.T) {
+	var (
+		g       callGuard
+		outcome breaker.Outcome
+	)
+	g.done = func(o breaker.Outcome) {
+		outcome = o
+	}
+
+	assert.NoError(t, g.mapError(nil))
+
+	// server errors are returned as is
+	dup := mysqlErrors.NewSQLError(mConstants.ERDupEntry, mConstants.SSDupKey, "duplicated")
+	assert.Equal(t, dup, g.mapError(dup))
+	assert.False(t, g.release())
+	assert.Equal(t, breaker.Success, outcome)
+
+	// network errors are reported as failures
+	assert.Equal(t, io.ErrUnexpectedEOF, g.mapError(io.ErrUnexpectedEOF))
+	assert.False(t, g.release())
+	assert.Equal(t, breaker.Failure, outcome)
+
+	// all errors are converted into timeout after interrupted
+	g.fired.Store(true)
+	err := g.mapError(io.ErrUnexpectedEOF)
+	var sqlErr *mysqlErrors.SQLError
+	assert.ErrorAs(t, err, &sqlErr)
+	assert.Equal(t, mConstants.ERQueryTimeout, sqlErr.Num)
+}
+
+func TestCallGuard_Stop(t *testing.T) {
+	var outcome breaker.Outcome
+	done := func(o breaker.Outcome) {
+		outcome = o
+	}
+
+	// the timer is stopped once the last row has been read, the call is not interrupted any more
+	g := &callGuard{timer: time.AfterFunc(time.Hour, func() {}), done: done}
+	assert.False(t, g.stop())
+	assert.False(t, g.release())
+	assert.Equal(t, breaker.Success, outcome)
+
+	fired := make(chan struct{})
+	g = &callGuard{timer: time.AfterFunc(0, func() { close(fired) }), done: done}
+	<-fired
+	assert.True(t, g.stop())
+	assert.True(t, g.release())
+	assert.Equal(t, breaker.Failure, outcome)
+}
@@ -18,8 +18,18 @@
 package namespace
 
 import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/metrics"
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/selector"
 	"github.com/arana-db/arana/pkg/util/log"
 )
 
@@ -30,11 +40,11 @@ func UpdateWeight(group, id string, weight proto.Weight) Command {
 		defer ns.Unlock()
 
 		var (
-			dss   = ns.dss.Load().(map[string][]proto.DB)
+			dss   = ns.snapshot()
 			bingo proto.DB
 		)
 
-		if exist, ok := dss[group]; ok {
+		if exist, ok := dss.groups[group]; ok {
 			for _, it := range exist {
 				if it.ID() == id {
 					bingo = it
@@ -48,10 +58,8 @@ func UpdateWeight(group, id string, weight proto.Weight) Command {
 			return nil
 		}
 
-		if err := bingo.SetWeight(weight); err != nil {
-			log.Errorf("[%s] failed to update weight of datasource %s.%s: %v", ns.name, group, id, err)
-			return nil
-		}
+		ns.dss.Store(newDataSources(dss, dss.groups, map[proto.DB]proto.Weight{bingo: weight}))
+		syncWeight(ns, group, bingo, weight)
 
 		log.Infof("[%s] update weight of datasource %s.%s successfully", ns.name, group, id)
 
@@ -59,6 +67,50 @@ func UpdateWeight(group, id string, weight proto.Weight) Command {
 	}
 }
 
+// SetEjected returns a command to eject the DB from selection or restore it, the weight of DB is kept.
+func SetEjected(group, id string, ejected bool) Command {
+	return func(ns *Namespace) error {
+		ns.Lock()
+		defer ns.Unlock()
+
+		var (
+			dss     = ns.snapshot().groups
+			current = ns.ejected.Load().(map[proto.DB]struct{})
+			alive   = make(map[proto.DB]struct{})
+			bingo   proto.DB
+		)
+
+		for g, dbs := range dss {
+			for _, it := range dbs {
+				alive[it] = struct{}{}
+				if g == group && it.ID() == id {
+					bingo = it
+				}
+			}
+		}
+
+		if bingo == nil {
+			return errors.Errorf("no such datasource %s.%s", group, id)
+		}
+
+		// the removed or replaced DBs are dropped also
+		newborn := make(map[proto.DB]struct{}, len(current)+1)
+		for it := range current {
+			if _, ok := alive[it]; ok {
+				newborn[it] = struct{}{}
+			}
+		}
+		if ejected {
+			newborn[bingo] = struct{}{}
+		} else {
+			delete(newborn, bingo)
+		}
+		ns.ejected.Store(newborn)
+
+		return nil
+	}
+}
+
 // RemoveDB returns a command to remove an existing DB.
 func RemoveDB(group, id string) Command {
 	return func(ns *Namespace) error {
@@ -68,10 +120,10 @@ func RemoveDB(group, id string) Command {
 		var (
 			expired proto.DB
 			values  []proto.DB
-			dss     = ns.dss.Load().(map[string][]proto.DB)
+			dss     = ns.snapshot()
 		)
 
-		if exist, ok := dss[group]; ok {
+		if exist, ok := dss.groups[group]; ok {
 			values = make([]proto.DB, 0, len(exist))
 			for _, it := range exist {
 				if it.ID() == id {
@@ -87,14 +139,18 @@ func RemoveDB(group, id string) Command {
 		}
 
 		newborn := make(map[string][]proto.DB)
-		for k, v := range dss {
+		for k, v := range dss.groups {
 			newborn[k] = v
 		}
-		newborn[group] = values
+		if len(values) > 0 {
+			newborn[group] = values
+		} else {
+			delete(newborn, group)
+		}
 
-		// TODO: expire datasource, lazy-close?
+		ns.dss.Store(newDataSources(dss, newborn, nil))
+		expire(ns, group, expired)
 
-		ns.dss.Store(newborn)
 		log.Infof("[%s] remove datasource %s.%s successfully", ns.name, group, id)
 
 		return nil
@@ -108,13 +164,13 @@ func UpsertDB(group string, ds proto.DB) Command {
 		defer ns.Unlock()
 
 		var (
-			current = ns.dss.Load().(map[string][]proto.DB)
+			current = ns.snapshot()
 			values  []proto.DB
 			expired proto.DB
 			id      = ds.ID()
 		)
 
-		if exist, ok := current[group]; ok {
+		if exist, ok := current.groups[group]; ok {
 			for _, it := range exist {
 				if it.ID() == id {
 					expired = it
@@ -125,18 +181,17 @@ func UpsertDB(group string, ds proto.DB) Command {
 		}
 		values = append(values, ds)
 
-		if expired != nil {
-			// TODO: expire datasource, lazy-close?
-			log.Infof("todo: expire DB %s", expired.ID())
-		}
-
 		newborn := make(map[string][]proto.DB)
-		for k, v := range current {
+		for k, v := range current.groups {
 			newborn[k] = v
 		}
 		newborn[group] = values
 
-		ns.dss.Store(newborn)
+		ns.dss.Store(newDataSources(current, newborn, nil))
+
+		if expired != nil {
+			expire(ns, group, expired)
+		}
 
 		log.Infof("[%s] upsert db %s.%s successfully", ns.name, group, id)
 
@@ -154,3 +209,233 @@ func UpdateRule(rule *rule.Rule) Command {
 		return nil
 	}
 }
+
+// SwitchMaster returns a command to switch the master of group to the given DB, it takes no effect if the master
+// has been pinned manually.
+func SwitchMaster(group, id string) Command {
+	return func(ns *Namespace) error {
+		return setMaster(ns, group, id, false)
+	}
+}
+
+// PinMaster returns a command to specify the master of group manually, which will not be switched by
+// SwitchMaster any more. The master will be unpinned and elected by weight again if id is empty.
+// The pin only lives in the namespace of current process, it is not persisted and will be lost after restart.
+func PinMaster(group, id string) Command {
+	return func(ns *Namespace) error {
+		return setMaster(ns, group, id, true)
+	}
+}
+
+// UpdateLoadBalance returns a command to update the load balance strategy of selecting DB.
+func UpdateLoadBalance(strategy selector.Strategy) Command {
+	return func(ns *Namespace) error {
+		ns.loadBalance.Store(strategy)
+		log.Infof("[%s] update load balance strategy to %s successfully", ns.name, strategy)
+		return nil
+	}
+}
+
+// Changes represents the changes of namespace which are applied at once by Apply.
+type Changes struct {
+	Upserts     map[string][]proto.DB              // group -> the new DBs, the existing DBs with same id will be replaced
+	Removes     map[string][]string                // group -> the ids of removed DBs
+	Weights     map[string]map[string]proto.Weight // group -> id -> the new weight of existing DB
+	Rule        *rule.Rule                         // the new rule, nil if it is not changed
+	LoadBalance selector.Strategy                  // the new load balance strategy, empty if it is not changed
+}
+
+// IsEmpty returns true if nothing is changed.
+func (c *Changes) IsEmpty() bool {
+	return len(c.Upserts) == 0 && len(c.Removes) == 0 && len(c.Weights) == 0 && c.Rule == nil && len(c.LoadBalance) == 0
+}
+
+// Close closes the new DBs, it should be called if the changes are abandoned.
+func (c *Changes) Close() {
+	for group, dbs := range c.Upserts {
+		for _, db := range dbs {
+			if err := db.Close(); err != nil {
+				log.Errorf("close abandoned datasource %s.%s failed: %v", group, db.ID(), err)
+			}
+		}
+	}
+}
+
+// Apply returns a command which applies all the changes, or nothing if any change is invalid,
+// the new DBs will be closed if the changes are abandoned.
+// The new snapshot of datasources with the new weights is built before anything is replaced, then the snapshot,
+// the rule and the load balance strategy are stored together while holding the lock of namespace, the DBs which
+// are being served are never modified before the switch.
+func Apply(changes *Changes) Command {
+	return func(ns *Namespace) error {
+		ns.Lock()
+		defer ns.Unlock()
+
+		var (
+			current = ns.snapshot()
+			newborn = make(map[string][]proto.DB, len(current.groups))
+			expired = make(map[string][]proto.DB)
+		)
+
+		for group, dbs := range current.groups {
+			newborn[group] = dbs
+		}
+
+		for group, ids := range changes.Removes {
+			removed := make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				removed[id] = struct{}{}
+			}
+			var values []proto.DB
+			for _, it := range newborn[group] {
+				if _, ok := removed[it.ID()]; ok {
+					expired[group] = append(expired[group], it)
+					continue
+				}
+				values = append(values, it)
+			}
+			newborn[group] = values
+		}
+
+		for group, dbs := range changes.Upserts {
+			replaced := make(map[string]struct{}, len(dbs))
+			for _, it := range dbs {
+				replaced[it.ID()] = struct{}{}
+			}
+			var values []proto.DB
+			for _, it := range newborn[group] {
+				if _, ok := replaced[it.ID()]; ok {
+					expired[group] = append(expired[group], it)
+					continue
+				}
+				values = append(values, it)
+			}
+			newborn[group] = append(values, dbs...)
+		}
+
+		for group, dbs := range newborn {
+			if len(dbs) < 1 {
+				delete(newborn, group)
+			}
+		}
+
+		// the new weights are built into the new snapshot
+		type weightChange struct {
+			group string
+			db    proto.DB
+		}
+		var (
+			weights = make(map[proto.DB]proto.Weight)
+			synced  []weightChange
+		)
+		for group, values := range changes.Weights {
+			for id, weight := range values {
+				var bingo proto.DB
+				for _, it := range newborn[group] {
+					if it.ID() == id {
+						bingo = it
+						break
+					}
+				}
+				if bingo == nil {
+					changes.Close()
+					return errors.Errorf("failed to update weight: no such datasource %s.%s", group, id)
+				}
+				weights[bingo] = weight
+				synced = append(synced, weightChange{group: group, db: bingo})
+			}
+		}
+
+		ns.dss.Store(newDataSources(current, newborn, weights))
+		if changes.Rule != nil {
+			ns.rule.Store(changes.Rule)
+		}
+		if len(changes.LoadBalance) > 0 {
+			ns.loadBalance.Store(changes.LoadBalance)
+		}
+
+		for _, it := range synced {
+			syncWeight(ns, it.group, it.db, weights[it.db])
+		}
+
+		for group, dbs := range expired {
+			for _, it := range dbs {
+				expire(ns, group, it)
+			}
+		}
+
+		log.Infof("[%s] apply changes successfully", ns.name)
+
+		return nil
+	}
+}
+
+// syncWeight updates the weight of DB after the new snapshot is stored, the selection always uses the weights
+// in snapshot, so the weight of DB is only kept in sync for the other readers.
+func syncWeight(ns *Namespace, group string, db proto.DB, weight proto.Weight) {
+	if err := db.SetWeight(weight); err != nil {
+		log.Errorf("[%s] failed to sync weight of datasource %s.%s: %v", ns.name, group, db.ID(), err)
+	}
+}
+
+// expire closes the replaced DB in background, the in-flight requests will not be affected because
+// the DB will be closed after all pending requests are finished.
+func expire(ns *Namespace, group string, db proto.DB) {
+	go func() {
+		if err := db.Close(); err != nil {
+			log.Errorf("[%s] close expired datasource %s.%s failed: %v", ns.name, group, db.ID(), err)
+		}
+	}()
+}
+
+func setMaster(ns *Namespace, group, id string, pinned bool) error {
+	ns.Lock()
+	defer ns.Unlock()
+
+	current := ns.masters.Load().(map[string]master)
+	if !pinned && current[group].pinned {
+		log.Warnf("[%s] skip switching master of group %s to %s: the master is pinned", ns.name, group, id)
+		return nil
+	}
+
+	if len(id) > 0 {
+		var found bool
+		for _, it := range ns.snapshot().groups[group] {
+			if it.ID() == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("no such datasource %s.%s", group, id)
+		}
+	}
+
+	prev := masterOf(ns, group)
+
+	newborn := make(map[string]master, len(current)+1)
+	for k, v := range current {
+		newborn[k] = v
+	}
+	if len(id) > 0 {
+		newborn[group] = master{id: id, pinned: pinned}
+	} else {
+		delete(newborn, group)
+	}
+	ns.masters.Store(newborn)
+
+	if next := masterOf(ns, group); next != prev {
+		log.Warnf("[%s] switch master of group %s from '%s' to '%s'", ns.name, group, prev, next)
+		metrics.MasterSwitches.WithLabelValues(ns.name, group, next).Inc()
+		notifyMaster(ns.name, group, prev, next)
+	}
+
+	return nil
+}
+
+func masterOf(ns *Namespace, group string) string {
+	if db := ns.DBMaster(context.Background(), group); db != nil {
+		return db.ID()
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+)
+
+// parseLabel parses the node label into pairs, multiple pairs are separated by comma, eg: zone=shanghai,rack=r1.
+// The colon is also accepted as the separator of key and value, eg: zone:shanghai.
+func parseLabel(label string) map[string]string {
+	var ret map[string]string
+	for _, it := range strings.Split(label, ",") {
+		if it = strings.TrimSpace(it); len(it) < 1 {
+			continue
+		}
+		i := strings.IndexAny(it, "=:")
+		if i == -1 {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]string)
+		}
+		ret[strings.TrimSpace(it[:i])] = strings.TrimSpace(it[i+1:])
+	}
+	return ret
+}
+
+// matchLabel returns true if the DB has all the wanted labels.
+func matchLabel(db proto.DB, want map[string]string) bool {
+	labels := db.Labels()
+	for k, v := range want {
+		if exist, ok := labels[k]; !ok || exist != v {
+			return false
+		}
+	}
+	return true
+}
+
+// preferLabeled returns the readable DBs which match the node label in context, all DBs will be returned if no
+// label specified or nothing matched.
+func preferLabeled(ctx context.Context, dss *dataSources, dbs []proto.DB) []proto.DB {
+	want := parseLabel(rcontext.NodeLabel(ctx))
+	if len(want) < 1 {
+		return dbs
+	}
+	var matched []proto.DB
+	for _, db := range dbs {
+		if dss.weight(db).R > 0 && matchLabel(db, want) {
+			matched = append(matched, db)
+		}
+	}
+	if len(matched) < 1 {
+		return dbs
+	}
+	return matched
+}
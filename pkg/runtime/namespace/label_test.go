@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/testdata"
+)
+
+func TestParseLabel(t *testing.T) {
+	assert.Nil(t, parseLabel(""))
+	assert.Nil(t, parseLabel("shanghai"))
+	assert.Equal(t, map[string]string{"zone": "shanghai"}, parseLabel("zone=shanghai"))
+	assert.Equal(t, map[string]string{"zone": "shanghai"}, parseLabel("zone:shanghai"))
+	assert.Equal(t, map[string]string{"zone": "shanghai", "rack": "r1"}, parseLabel(" zone = shanghai , rack=r1,"))
+}
+
+func TestDBByLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getDB := func(i int, w, r int32, zone string) proto.DB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(fmt.Sprintf("the-mysql-instance-%d", i)).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: r, W: w}).AnyTimes()
+		db.EXPECT().Labels().Return(map[string]string{"zone": zone}).AnyTimes()
+		db.EXPECT().Close().Times(1)
+		return db
+	}
+
+	ns, err := New("label",
+		UpsertDB(getGroup(0), getDB(1, 10, 10, "beijing")),
+		UpsertDB(getGroup(0), getDB(2, 0, 10, "beijing")),
+		UpsertDB(getGroup(0), getDB(3, 0, 10, "shanghai")),
+		UpsertDB(getGroup(0), getDB(4, 10, 0, "hangzhou")),
+	)
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	read := rcontext.WithRead(context.Background())
+	for i := 0; i < 20; i++ {
+		ctx := rcontext.WithNodeLabel(read, "zone=shanghai")
+		assert.Equal(t, "the-mysql-instance-3", ns.DB(ctx, getGroup(0)).ID())
+		assert.Equal(t, "the-mysql-instance-3", ns.DBSlave(ctx, getGroup(0)).ID())
+
+		// the matched nodes are preferred
+		ctx = rcontext.WithNodeLabel(read, "zone=beijing")
+		assert.NotEqual(t, "the-mysql-instance-3", ns.DB(ctx, getGroup(0)).ID())
+		assert.Equal(t, "the-mysql-instance-2", ns.DBSlave(ctx, getGroup(0)).ID())
+
+		// fallback to all nodes if the matched ones are not readable
+		ctx = rcontext.WithNodeLabel(read, "zone=hangzhou")
+		assert.NotEqual(t, "the-mysql-instance-4", ns.DB(ctx, getGroup(0)).ID())
+		assert.NotEqual(t, "the-mysql-instance-4", ns.DBSlave(ctx, getGroup(0)).ID())
+	}
+
+	// labels never affect writes
+	ctx := rcontext.WithNodeLabel(context.Background(), "zone=shanghai")
+	assert.Equal(t, "the-mysql-instance-1", ns.DBMaster(ctx, getGroup(0)).ID())
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"sort"
 	"sync"
+	"time"
 )
 
 import (
@@ -67,6 +68,13 @@ func Unregister(namespace string) error {
 	return removed.(*Namespace).Close()
 }
 
+// Range calls f sequentially for each registered namespace, it stops the iteration if f returns false.
+func Range(f func(ns *Namespace) bool) {
+	_namespaces.Range(func(_, value interface{}) bool {
+		return f(value.(*Namespace))
+	})
+}
+
 type (
 	// Namespace represents a logical database with all resources.
 	Namespace struct {
@@ -78,8 +86,17 @@ type (
 
 		rule atomic.Value // *rule.Rule
 
-		// datasource map, eg: employee_0001 -> [mysql-a,mysql-b,mysql-c], ... employee_0007 -> [mysql-x,mysql-y,mysql-z]
-		dss atomic.Value // map[string][]proto.DB
+		// the snapshot of datasources, the DBs and their weights are replaced together
+		dss atomic.Value // *dataSources
+
+		// masters specified by failover detection or manually, eg: employee_0001 -> mysql-b
+		masters atomic.Value // map[string]master
+
+		// the DBs ejected by health checking, which will not be selected unless no other DB available
+		ejected atomic.Value // map[proto.DB]struct{}
+
+		loadBalance atomic.Value // selector.Strategy
+		counters    sync.Map     // the counters of round-robin, group -> *atomic.Uint64
 
 		cmds chan Command  // command queue
 		done chan struct{} // done notify
@@ -87,8 +104,44 @@ type (
 
 	// Command represents the command to control Namespace.
 	Command func(ns *Namespace) error
+
+	// MasterObserver observes the switch of master DB, prev or next is empty if no master available.
+	MasterObserver func(namespace, group, prev, next string)
+
+	// dataSources is the snapshot of datasources, which is never modified after stored.
+	dataSources struct {
+		// datasource map, eg: employee_0001 -> [mysql-a,mysql-b,mysql-c], ... employee_0007 -> [mysql-x,mysql-y,mysql-z]
+		groups map[string][]proto.DB
+		// the weights used by selection, so that the new weights take effect together with the new DBs
+		weights map[proto.DB]proto.Weight
+	}
+
+	master struct {
+		id     string
+		pinned bool // true if the master is specified manually
+	}
 )
 
+var _masterObservers struct {
+	sync.RWMutex
+	observers []MasterObserver
+}
+
+// SubscribeMaster registers an observer which will be notified when the master of any group is switched.
+func SubscribeMaster(observer MasterObserver) {
+	_masterObservers.Lock()
+	defer _masterObservers.Unlock()
+	_masterObservers.observers = append(_masterObservers.observers, observer)
+}
+
+func notifyMaster(namespace, group, prev, next string) {
+	_masterObservers.RLock()
+	defer _masterObservers.RUnlock()
+	for _, observer := range _masterObservers.observers {
+		observer(namespace, group, prev, next)
+	}
+}
+
 // New creates a Namespace.
 func New(name string, commands ...Command) (*Namespace, error) {
 	ns := &Namespace{
@@ -96,8 +149,11 @@ func New(name string, commands ...Command) (*Namespace, error) {
 		cmds: make(chan Command, 1),
 		done: make(chan struct{}),
 	}
-	ns.dss.Store(make(map[string][]proto.DB)) // init empty map
-	ns.rule.Store(&rule.Rule{})               // init empty rule
+	ns.dss.Store(&dataSources{})              // init empty snapshot
+	ns.masters.Store(make(map[string]master)) // init empty masters
+	ns.ejected.Store(make(map[proto.DB]struct{}))
+	ns.rule.Store(&rule.Rule{}) // init empty rule
+	ns.loadBalance.Store(selector.WeightRandom)
 
 	for _, cmd := range commands {
 		if err := cmd(ns); err != nil {
@@ -118,7 +174,7 @@ func (ns *Namespace) Name() string {
 // DBGroups returns the group names of DB.
 func (ns *Namespace) DBGroups() []string {
 	// FIXME: consider cache it
-	dss := ns.dss.Load().(map[string][]proto.DB)
+	dss := ns.snapshot().groups
 	groups := make([]string, 0, len(dss))
 	for k := range dss {
 		groups = append(groups, k)
@@ -127,6 +183,17 @@ func (ns *Namespace) DBGroups() []string {
 	return groups
 }
 
+// DBs returns all DB instances of the group.
+func (ns *Namespace) DBs(group string) []proto.DB {
+	exist, ok := ns.snapshot().groups[group]
+	if !ok {
+		return nil
+	}
+	ret := make([]proto.DB, len(exist))
+	copy(ret, exist)
+	return ret
+}
+
 func (ns *Namespace) DB0(ctx context.Context) proto.DB {
 	groups := ns.DBGroups()
 	if len(groups) < 1 {
@@ -138,8 +205,8 @@ func (ns *Namespace) DB0(ctx context.Context) proto.DB {
 // DB returns a DB, returns nil if nothing selected.
 func (ns *Namespace) DB(ctx context.Context, group string) proto.DB {
 	// use weight manager to select datasource
-	dss := ns.dss.Load().(map[string][]proto.DB)
-	exist, ok := dss[group]
+	dss := ns.snapshot()
+	exist, ok := dss.groups[group]
 	if !ok {
 		return nil
 	}
@@ -150,16 +217,18 @@ func (ns *Namespace) DB(ctx context.Context, group string) proto.DB {
 
 	// select by weight
 	if rcontext.IsRead(ctx) {
+		// skip the ejected DBs, and keep reads in the nodes with preferred label, eg: the local data center
+		exist = preferLabeled(ctx, dss, ns.healthy(exist))
 		for _, db := range exist {
-			wrList = append(wrList, int(db.Weight().R))
+			wrList = append(wrList, int(dss.weight(db).R))
 		}
 	} else if rcontext.IsWrite(ctx) {
 		for _, db := range exist {
-			wrList = append(wrList, int(db.Weight().W))
+			wrList = append(wrList, int(dss.weight(db).W))
 		}
 	}
 	if len(wrList) != 0 {
-		target = selector.NewWeightRandomSelector(wrList).GetDataSourceNo()
+		target = ns.pick(group, exist, wrList)
 	}
 
 	return exist[target]
@@ -168,51 +237,174 @@ func (ns *Namespace) DB(ctx context.Context, group string) proto.DB {
 // DBMaster returns a master DB, returns nil if nothing selected.
 func (ns *Namespace) DBMaster(_ context.Context, group string) proto.DB {
 	// use weight manager to select datasource
-	dss := ns.dss.Load().(map[string][]proto.DB)
-	exist, ok := dss[group]
+	dss := ns.snapshot()
+	exist, ok := dss.groups[group]
 	if !ok {
 		return nil
 	}
+	// the master switched by failover or manually takes precedence
+	if m, ok := ns.masters.Load().(map[string]master)[group]; ok {
+		for _, db := range exist {
+			if db.ID() == m.id {
+				return db
+			}
+		}
+	}
 	// master weight w>0 && r>0
+	var ejected proto.DB
 	for _, db := range exist {
-		if db.Weight().W > 0 && db.Weight().R > 0 {
-			return db
+		if w := dss.weight(db); w.W > 0 && w.R > 0 {
+			if !ns.IsEjected(db) {
+				return db
+			}
+			// the ejected master is still used if no other master available
+			if ejected == nil {
+				ejected = db
+			}
 		}
 	}
-	return nil
+	return ejected
 }
 
 // DBSlave returns a slave DB, returns nil if nothing selected.
-func (ns *Namespace) DBSlave(_ context.Context, group string) proto.DB {
+func (ns *Namespace) DBSlave(ctx context.Context, group string) proto.DB {
 	// use weight manager to select datasource
-	dss := ns.dss.Load().(map[string][]proto.DB)
-	exist, ok := dss[group]
+	dss := ns.snapshot()
+	exist, ok := dss.groups[group]
 	if !ok {
 		return nil
 	}
 	var (
-		target     = 0
 		wrList     = make([]int, 0, len(exist))
 		readDBList = make([]proto.DB, 0, len(exist))
 	)
 	// slave weight w==0 && r>=0
 	for _, db := range exist {
-		if db.Weight().W != 0 {
+		w := dss.weight(db)
+		if w.W != 0 || ns.IsEjected(db) {
 			continue
 		}
 		// r==0 has high priority
-		if db.Weight().R == 0 {
+		if w.R == 0 {
 			return db
 		}
-		if db.Weight().R > 0 {
-			wrList = append(wrList, int(db.Weight().R))
-			readDBList = append(readDBList, db)
+		readDBList = append(readDBList, db)
+	}
+	if len(readDBList) < 1 {
+		return nil
+	}
+	readDBList = preferLabeled(ctx, dss, readDBList)
+	for _, db := range readDBList {
+		wrList = append(wrList, int(dss.weight(db).R))
+	}
+	return readDBList[ns.pick(group, readDBList, wrList)]
+}
+
+// Weight returns the weight of DB which is used by selection.
+func (ns *Namespace) Weight(db proto.DB) proto.Weight {
+	return ns.snapshot().weight(db)
+}
+
+// snapshot returns the current snapshot of datasources.
+func (ns *Namespace) snapshot() *dataSources {
+	return ns.dss.Load().(*dataSources)
+}
+
+// newDataSources creates a snapshot of datasources, the weights are taken from the changed weights first,
+// then the previous snapshot, and the weights of new DBs are loaded from themselves.
+func newDataSources(prev *dataSources, groups map[string][]proto.DB, changed map[proto.DB]proto.Weight) *dataSources {
+	ret := &dataSources{
+		groups:  groups,
+		weights: make(map[proto.DB]proto.Weight),
+	}
+	for _, dbs := range groups {
+		for _, db := range dbs {
+			if w, ok := changed[db]; ok {
+				ret.weights[db] = w
+			} else if w, ok = prev.lookup(db); ok {
+				ret.weights[db] = w
+			} else {
+				ret.weights[db] = db.Weight()
+			}
+		}
+	}
+	return ret
+}
+
+func (ds *dataSources) lookup(db proto.DB) (proto.Weight, bool) {
+	if ds == nil {
+		return proto.Weight{}, false
+	}
+	w, ok := ds.weights[db]
+	return w, ok
+}
+
+func (ds *dataSources) weight(db proto.DB) proto.Weight {
+	if w, ok := ds.lookup(db); ok {
+		return w
+	}
+	return db.Weight()
+}
+
+// IsEjected returns true if the DB is ejected by health checking.
+func (ns *Namespace) IsEjected(db proto.DB) bool {
+	_, ok := ns.ejected.Load().(map[proto.DB]struct{})[db]
+	return ok
+}
+
+// healthy returns the DBs which are not ejected, or all DBs if all of them are ejected.
+func (ns *Namespace) healthy(dbs []proto.DB) []proto.DB {
+	ejected := ns.ejected.Load().(map[proto.DB]struct{})
+	if len(ejected) < 1 {
+		return dbs
+	}
+	ret := make([]proto.DB, 0, len(dbs))
+	for _, db := range dbs {
+		if _, ok := ejected[db]; !ok {
+			ret = append(ret, db)
 		}
 	}
-	if len(wrList) != 0 {
-		target = selector.NewWeightRandomSelector(wrList).GetDataSourceNo()
+	if len(ret) < 1 {
+		return dbs
+	}
+	return ret
+}
+
+// pick selects a DB by the load balance strategy, returns the index of DBs.
+func (ns *Namespace) pick(group string, dbs []proto.DB, weights []int) int {
+	switch ns.loadBalance.Load().(selector.Strategy) {
+	case selector.RoundRobin:
+		counter, _ := ns.counters.LoadOrStore(group, atomic.NewUint64(0))
+		return selector.NewRoundRobinSelector(counter.(*atomic.Uint64), weights).GetDataSourceNo()
+	case selector.LeastActive:
+		actives, _ := statsOf(dbs)
+		return selector.NewLeastActiveSelector(weights, actives).GetDataSourceNo()
+	case selector.LeastLatency:
+		actives, latencies := statsOf(dbs)
+		return selector.NewLeastLatencySelector(weights, latencies, actives).GetDataSourceNo()
+	default:
+		return selector.NewWeightRandomSelector(weights).GetDataSourceNo()
+	}
+}
+
+// statsOf returns the in-flight requests and latencies of DBs.
+func statsOf(dbs []proto.DB) ([]int64, []time.Duration) {
+	var (
+		actives   = make([]int64, len(dbs))
+		latencies = make([]time.Duration, len(dbs))
+	)
+	for i, db := range dbs {
+		if stats, ok := db.(proto.DBStats); ok {
+			actives[i] = stats.ActiveRequests()
+			latencies[i] = stats.Latency()
+		}
 	}
-	return readDBList[target]
+	return actives, latencies
+}
+
+// MasterPinned returns true if the master of group is specified manually.
+func (ns *Namespace) MasterPinned(group string) bool {
+	return ns.masters.Load().(map[string]master)[group].pinned
 }
 
 // Rule returns the sharding rule.
@@ -246,7 +438,7 @@ func (ns *Namespace) Close() error {
 	ns.Lock()
 	defer ns.Unlock()
 
-	for group, dbs := range ns.dss.Load().(map[string][]proto.DB) {
+	for group, dbs := range ns.snapshot().groups {
 		for _, db := range dbs {
 			if err := db.Close(); err != nil {
 				log.Errorf("[%s] close DB %s.%s failed: %v", ns.name, group, db.ID(), err)
@@ -262,6 +454,8 @@ func (ns *Namespace) Close() error {
 func (ns *Namespace) loopCmds() {
 	defer close(ns.done)
 	for cmd := range ns.cmds {
-		cmd(ns)
+		if err := cmd(ns); err != nil {
+			log.Errorf("[%s] failed to execute command: %v", ns.name, err)
+		}
 	}
 }
@@ -33,6 +33,7 @@ import (
 import (
 	"github.com/arana-db/arana/pkg/proto"
 	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/selector"
 	"github.com/arana-db/arana/testdata"
 )
 
@@ -120,3 +121,213 @@ func TestGetDBByWeight(t *testing.T) {
 	ctx = rcontext.WithWrite(context.Background())
 	assert.NotNil(t, ns.DB(ctx, getGroup(0)))
 }
+
+func TestSwitchMaster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getDB := func(i int, w, r int32) proto.DB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(fmt.Sprintf("the-mysql-instance-%d", i)).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: r, W: w}).AnyTimes()
+		// the removed DB is closed asynchronously
+		db.EXPECT().Close().AnyTimes()
+		return db
+	}
+
+	ns, err := New("switch_master",
+		UpsertDB(getGroup(0), getDB(1, 10, 10)),
+		UpsertDB(getGroup(0), getDB(2, 0, 10)),
+		UpsertDB(getGroup(0), getDB(3, 0, 10)),
+	)
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	ctx := context.Background()
+	assert.Equal(t, "the-mysql-instance-1", ns.DBMaster(ctx, getGroup(0)).ID())
+
+	assert.NoError(t, SwitchMaster(getGroup(0), "the-mysql-instance-2")(ns))
+	assert.Equal(t, "the-mysql-instance-2", ns.DBMaster(ctx, getGroup(0)).ID())
+	assert.False(t, ns.MasterPinned(getGroup(0)))
+
+	assert.Error(t, SwitchMaster(getGroup(0), "the-mysql-instance-4")(ns))
+	assert.Equal(t, "the-mysql-instance-2", ns.DBMaster(ctx, getGroup(0)).ID())
+
+	// the pinned master cannot be switched
+	assert.NoError(t, PinMaster(getGroup(0), "the-mysql-instance-3")(ns))
+	assert.True(t, ns.MasterPinned(getGroup(0)))
+	assert.NoError(t, SwitchMaster(getGroup(0), "the-mysql-instance-2")(ns))
+	assert.Equal(t, "the-mysql-instance-3", ns.DBMaster(ctx, getGroup(0)).ID())
+
+	// unpin, elect the master by weight again
+	assert.NoError(t, PinMaster(getGroup(0), "")(ns))
+	assert.False(t, ns.MasterPinned(getGroup(0)))
+	assert.Equal(t, "the-mysql-instance-1", ns.DBMaster(ctx, getGroup(0)).ID())
+
+	// fallback if the switched master is removed
+	assert.NoError(t, SwitchMaster(getGroup(0), "the-mysql-instance-2")(ns))
+	assert.NoError(t, RemoveDB(getGroup(0), "the-mysql-instance-2")(ns))
+	assert.Equal(t, "the-mysql-instance-1", ns.DBMaster(ctx, getGroup(0)).ID())
+}
+
+func TestUpdateLoadBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getDB := func(i int, w, r int32) proto.DB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(fmt.Sprintf("the-mysql-instance-%d", i)).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: r, W: w}).AnyTimes()
+		db.EXPECT().Close().Times(1)
+		return db
+	}
+
+	ns, err := New("load_balance",
+		UpsertDB(getGroup(0), getDB(1, 10, 10)),
+		UpsertDB(getGroup(0), getDB(2, 0, 10)),
+		UpsertDB(getGroup(0), getDB(3, 0, 10)),
+		UpdateLoadBalance(selector.RoundRobin),
+	)
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	ctx := rcontext.WithRead(context.Background())
+
+	var selected []string
+	for i := 0; i < 6; i++ {
+		selected = append(selected, ns.DB(ctx, getGroup(0)).ID())
+	}
+	assert.Equal(t, []string{
+		"the-mysql-instance-1", "the-mysql-instance-2", "the-mysql-instance-3",
+		"the-mysql-instance-1", "the-mysql-instance-2", "the-mysql-instance-3",
+	}, selected)
+
+	// the slaves share the counter of group
+	assert.Equal(t, "the-mysql-instance-2", ns.DBSlave(ctx, getGroup(0)).ID())
+	assert.Equal(t, "the-mysql-instance-3", ns.DBSlave(ctx, getGroup(0)).ID())
+}
+
+func TestApply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getDB := func(i int) *testdata.MockDB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(fmt.Sprintf("the-mysql-instance-%d", i)).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: 10, W: 10}).AnyTimes()
+		return db
+	}
+
+	var ns *Namespace
+
+	closed := make(chan struct{})
+	db1, db2 := getDB(1), getDB(2)
+	// the weight of served DB is synced only after the new snapshot is stored
+	db1.EXPECT().SetWeight(proto.Weight{R: 5, W: 5}).DoAndReturn(func(proto.Weight) error {
+		assert.Len(t, ns.DBs(getGroup(0)), 1)
+		assert.Equal(t, proto.Weight{R: 5, W: 5}, ns.Weight(db1))
+		return nil
+	}).Times(1)
+	db1.EXPECT().Close().Times(1)
+	// the removed DB is closed in background
+	db2.EXPECT().Close().DoAndReturn(func() error {
+		close(closed)
+		return nil
+	}).Times(1)
+	db3, db4 := getDB(3), getDB(4)
+	db3.EXPECT().Close().Times(1)
+	db4.EXPECT().Close().Times(1)
+
+	ns, err := New("apply", UpsertDB(getGroup(0), db1), UpsertDB(getGroup(0), db2))
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	// nothing is changed if any change is invalid, and the new DBs are closed
+	err = Apply(&Changes{
+		Upserts: map[string][]proto.DB{getGroup(1): {db3}},
+		Removes: map[string][]string{getGroup(0): {"the-mysql-instance-2"}},
+		Weights: map[string]map[string]proto.Weight{getGroup(0): {
+			"the-mysql-instance-1": {R: 5, W: 5},
+			"the-mysql-instance-9": {R: 5, W: 5},
+		}},
+		LoadBalance: selector.RoundRobin,
+	})(ns)
+	assert.Error(t, err)
+	assert.Len(t, ns.DBs(getGroup(0)), 2)
+	assert.Empty(t, ns.DBs(getGroup(1)))
+	assert.Equal(t, proto.Weight{R: 10, W: 10}, ns.Weight(db1))
+	assert.Equal(t, selector.WeightRandom, ns.loadBalance.Load())
+
+	err = Apply(&Changes{
+		Upserts:     map[string][]proto.DB{getGroup(1): {db4}},
+		Removes:     map[string][]string{getGroup(0): {"the-mysql-instance-2"}},
+		Weights:     map[string]map[string]proto.Weight{getGroup(0): {"the-mysql-instance-1": {R: 5, W: 5}}},
+		LoadBalance: selector.RoundRobin,
+	})(ns)
+	assert.NoError(t, err)
+	assert.Len(t, ns.DBs(getGroup(0)), 1)
+	assert.Len(t, ns.DBs(getGroup(1)), 1)
+	assert.Equal(t, selector.RoundRobin, ns.loadBalance.Load())
+	assert.Equal(t, proto.Weight{R: 5, W: 5}, ns.Weight(db1))
+
+	select {
+	case <-closed:
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "the removed DB is not closed")
+	}
+}
+
+func TestDBSlave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	getDB := func(i int, w, r int32) proto.DB {
+		db := testdata.NewMockDB(ctrl)
+		db.EXPECT().ID().Return(fmt.Sprintf("the-mysql-instance-%d", i)).AnyTimes()
+		db.EXPECT().Weight().Return(proto.Weight{R: r, W: w}).AnyTimes()
+		db.EXPECT().Close().Times(1)
+		return db
+	}
+
+	ns, err := New("slave",
+		UpsertDB(getGroup(0), getDB(1, 10, 10)),
+		UpsertDB(getGroup(0), getDB(2, 0, 10)),
+		UpsertDB(getGroup(0), getDB(3, 0, 0)),
+		UpsertDB(getGroup(1), getDB(4, 10, 10)),
+		UpsertDB(getGroup(1), getDB(5, 0, 0)),
+		UpsertDB(getGroup(1), getDB(6, 0, 0)),
+	)
+	assert.NoError(t, err)
+	defer func() {
+		_ = ns.Close()
+	}()
+
+	ctx := context.Background()
+
+	// r==0 has high priority, and no random pick if all weights are zero
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "the-mysql-instance-3", ns.DBSlave(ctx, getGroup(0)).ID())
+		assert.Equal(t, "the-mysql-instance-5", ns.DBSlave(ctx, getGroup(1)).ID())
+	}
+
+	// the ejected slaves are skipped
+	assert.NoError(t, SetEjected(getGroup(0), "the-mysql-instance-3", true)(ns))
+	assert.Equal(t, "the-mysql-instance-2", ns.DBSlave(ctx, getGroup(0)).ID())
+	assert.NoError(t, SetEjected(getGroup(0), "the-mysql-instance-2", true)(ns))
+	assert.Nil(t, ns.DBSlave(ctx, getGroup(0)))
+
+	// the weight is kept, and the ejected master is still available
+	assert.NoError(t, SetEjected(getGroup(0), "the-mysql-instance-1", true)(ns))
+	assert.Equal(t, "the-mysql-instance-1", ns.DBMaster(ctx, getGroup(0)).ID())
+	assert.Equal(t, proto.Weight{R: 10, W: 10}, ns.DBMaster(ctx, getGroup(0)).Weight())
+
+	assert.NoError(t, SetEjected(getGroup(0), "the-mysql-instance-3", false)(ns))
+	assert.Equal(t, "the-mysql-instance-3", ns.DBSlave(ctx, getGroup(0)).ID())
+	assert.Error(t, SetEjected(getGroup(0), "the-mysql-instance-9", true)(ns))
+}
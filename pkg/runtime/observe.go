@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/metrics"
+	"github.com/arana-db/arana/pkg/runtime/namespace"
+	"github.com/arana-db/arana/pkg/slowlog"
+	"github.com/arana-db/arana/pkg/tracing"
+)
+
+func init() {
+	metrics.SetPoolStatsProvider(collectPoolStats)
+}
+
+// collectPoolStats collects the stats of backend connection pools of all namespaces.
+func collectPoolStats() []metrics.PoolStats {
+	var ret []metrics.PoolStats
+	namespace.Range(func(ns *namespace.Namespace) bool {
+		ret = append(ret, PoolStats(ns)...)
+		return true
+	})
+	return ret
+}
+
+// PoolStats returns the stats of backend connection pools in the namespace.
+func PoolStats(ns *namespace.Namespace) []metrics.PoolStats {
+	var ret []metrics.PoolStats
+	for _, group := range ns.DBGroups() {
+		for _, db := range ns.DBs(group) {
+			atom, ok := db.(*AtomDB)
+			if !ok || atom.pool == nil {
+				continue
+			}
+			ret = append(ret, metrics.PoolStats{
+				Schema:      ns.Name(),
+				Group:       group,
+				Node:        atom.id,
+				Capacity:    atom.pool.Capacity(),
+				MaxCapacity: atom.pool.MaxCap(),
+				Available:   atom.pool.Available(),
+				Active:      atom.pool.Active(),
+				InUse:       atom.pool.InUse(),
+				WaitCount:   atom.pool.WaitCount(),
+				WaitTime:    atom.pool.WaitTime(),
+				IdleClosed:  atom.pool.IdleClosed(),
+				Expired:     atom.expired.Load(),
+				Invalid:     atom.invalid.Load(),
+			})
+		}
+	}
+	return ret
+}
+
+// startPhysical starts observing a physical statement with the slow log, metrics and tracing. It returns a context
+// which contains the span of physical statement, and the SQL with the injected trace context. The returned function
+// should be called when the statement is finished.
+func startPhysical(ctx context.Context, schema, group, node, query string) (context.Context, string, func(err error)) {
+	var (
+		start = time.Now()
+		done  = slowlog.FromContext(ctx).Record(group, node, query)
+		span  trace.Span
+	)
+
+	ctx, span = Tracer.Start(ctx, "Physical", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemMySQL,
+		semconv.DBNameKey.String(group),
+		semconv.DBStatementKey.String(query),
+		attribute.String("arana.node", node),
+	))
+
+	return ctx, tracing.Inject(ctx, query), func(err error) {
+		done(err)
+		metrics.BackendDuration.WithLabelValues(schema, group, node).Observe(time.Since(start).Seconds())
+		if err != nil {
+			metrics.BackendErrors.WithLabelValues(schema, group, node).Inc()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -48,13 +48,26 @@ func optimizeAlterTable(_ context.Context, o *optimize.Optimizer) (proto.Plan, e
 		return ret, nil
 	}
 
-	// TODO alter table table or column to new name , should update sharding info
-
 	// exit if full-scan is disabled
 	if !vt.AllowFullScan() {
 		return nil, optimize.ErrDenyFullScan
 	}
 
+	// rename the table, the sharding info should be updated
+	if newTable, renamed := stmt.RenamedTable(); renamed {
+		if err := validateRenameTable(o, table, newTable); err != nil {
+			return nil, err
+		}
+		ret.SetRenamedTable(newTable.Suffix())
+	}
+
+	// rename the shard columns, the sharding info should be updated
+	for column, newColumn := range stmt.RenamedColumns() {
+		if o.Rule.HasColumn(table.Suffix(), column) {
+			ret.AddRenamedColumn(column, newColumn)
+		}
+	}
+
 	// sharding
 	ret.Shards = vt.Topology().Enumerate()
 	return ret, nil
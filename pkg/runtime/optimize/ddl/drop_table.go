@@ -35,14 +35,17 @@ func init() {
 	optimize.Register(ast.SQLTypeDropTable, optimizeDropTable)
 }
 
-func optimizeDropTable(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+func optimizeDropTable(ctx context.Context, o *optimize.Optimizer) (proto.Plan, error) {
 	stmt := o.Stmt.(*ast.DropTableStatement)
 	// table shard
-	var shards []rule.DatabaseTables
+	var (
+		shards        []rule.DatabaseTables
+		shardedTables []string
+	)
 	// tables not shard
 	noShardStmt := ast.NewDropTableStatement()
 	for _, table := range stmt.Tables {
-		shard, err := o.ComputeShards(*table, nil, o.Args)
+		shard, err := o.ComputeShards(ctx, *table, nil, o.Args)
 		if err != nil {
 			return nil, err
 		}
@@ -51,12 +54,33 @@ func optimizeDropTable(_ context.Context, o *optimize.Optimizer) (proto.Plan, er
 			continue
 		}
 		shards = append(shards, shard)
+		shardedTables = append(shardedTables, table.Suffix())
 	}
 
 	shardPlan := ddl.NewDropTablePlan(stmt)
 	shardPlan.BindArgs(o.Args)
 	shardPlan.SetShards(shards)
 
+	if isDryRun(o) {
+		var statements []*ddl.PhysicalStatement
+		if len(noShardStmt.Tables) > 0 {
+			query, err := ast.RestoreToString(ast.RestoreDefault, noShardStmt)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, &ddl.PhysicalStatement{Query: query})
+		}
+		physicals, err := shardPlan.PhysicalStatements()
+		if err != nil {
+			return nil, err
+		}
+		return ddl.NewDryRunPlan(append(statements, physicals...)), nil
+	}
+
+	if err := guardDestructive(ctx, o, "drop", shardedTables); err != nil {
+		return nil, err
+	}
+
 	if len(noShardStmt.Tables) == 0 {
 		return shardPlan, nil
 	}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto/hint"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/optimize"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// guardDestructive checks if the destructive DDL on sharding tables can be executed,
+// it requires a CONFIRM hint or the permission of current tenant.
+func guardDestructive(ctx context.Context, o *optimize.Optimizer, op string, tables []string) error {
+	if len(tables) == 0 || hint.Contains(hint.TypeConfirm, o.Hints) {
+		return nil
+	}
+	if security.DefaultTenantManager().AllowDestructiveDDL(rcontext.Tenant(ctx)) {
+		return nil
+	}
+	return errors.Wrapf(optimize.ErrDenyDestructive,
+		"cannot %s sharding table '%s', please retry with hint /*A! confirm */ or use /*A! dryrun */ to preview",
+		op, strings.Join(tables, "','"))
+}
+
+// isDryRun returns true if the DRYRUN hint exists.
+func isDryRun(o *optimize.Optimizer) bool {
+	return hint.Contains(hint.TypeDryRun, o.Hints)
+}
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	"github.com/arana-db/arana/pkg/runtime/optimize"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+	"github.com/arana-db/arana/pkg/runtime/plan/ddl"
+	"github.com/arana-db/arana/pkg/runtime/plan/dml"
+)
+
+func init() {
+	optimize.Register(ast.SQLTypeRenameTable, optimizeRenameTable)
+}
+
+func optimizeRenameTable(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+	var (
+		stmt        = o.Stmt.(*ast.RenameTableStatement)
+		shardPlan   = ddl.NewRenameTablePlan()
+		noShardStmt = ast.NewRenameTableStatement()
+	)
+	shardPlan.BindArgs(o.Args)
+
+	for _, it := range stmt.TableToTables {
+		vt, ok := o.Rule.VTable(it.OldTable.Suffix())
+		if !ok {
+			noShardStmt.TableToTables = append(noShardStmt.TableToTables, it)
+			continue
+		}
+
+		if err := validateRenameTable(o, it.OldTable, it.NewTable); err != nil {
+			return nil, err
+		}
+
+		// exit if full-scan is disabled
+		if !vt.AllowFullScan() {
+			return nil, optimize.ErrDenyFullScan
+		}
+
+		shardPlan.AddTable(it.OldTable.Suffix(), it.NewTable.Suffix(), vt.Topology().Enumerate())
+	}
+
+	if len(noShardStmt.TableToTables) == 0 {
+		return shardPlan, nil
+	}
+
+	noShardPlan := plan.Transparent(noShardStmt, o.Args)
+	if len(noShardStmt.TableToTables) == len(stmt.TableToTables) {
+		return noShardPlan, nil
+	}
+
+	return &dml.CompositePlan{
+		Plans: []proto.Plan{
+			noShardPlan, shardPlan,
+		},
+	}, nil
+}
+
+// validateRenameTable checks if the sharding table can be renamed to the new table.
+func validateRenameTable(o *optimize.Optimizer, table, newTable ast.TableName) error {
+	if len(newTable.Prefix()) > 0 && newTable.Prefix() != table.Prefix() {
+		return errors.Errorf("optimize: cannot rename sharding table '%s' to another database", table.Suffix())
+	}
+	if o.Rule.Has(newTable.Suffix()) {
+		return errors.Errorf("optimize: cannot rename sharding table '%s': table '%s' exists already", table.Suffix(), newTable.Suffix())
+	}
+	return nil
+}
@@ -37,14 +37,21 @@ func init() {
 	optimize.Register(ast.SQLTypeTruncate, optimizeTruncate)
 }
 
-func optimizeTruncate(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+func optimizeTruncate(ctx context.Context, o *optimize.Optimizer) (proto.Plan, error) {
 	stmt := o.Stmt.(*ast.TruncateStatement)
-	shards, err := o.ComputeShards(stmt.Table, nil, o.Args)
+	shards, err := o.ComputeShards(ctx, stmt.Table, nil, o.Args)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to optimize TRUNCATE statement")
 	}
 
 	if shards == nil {
+		if isDryRun(o) {
+			query, err := ast.RestoreToString(ast.RestoreDefault, stmt)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return ddl.NewDryRunPlan([]*ddl.PhysicalStatement{{Query: query}}), nil
+		}
 		return plan.Transparent(stmt, o.Args), nil
 	}
 
@@ -52,5 +59,17 @@ func optimizeTruncate(_ context.Context, o *optimize.Optimizer) (proto.Plan, err
 	ret.BindArgs(o.Args)
 	ret.SetShards(shards)
 
+	if isDryRun(o) {
+		statements, err := ret.PhysicalStatements()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to optimize TRUNCATE statement")
+		}
+		return ddl.NewDryRunPlan(statements), nil
+	}
+
+	if err = guardDestructive(ctx, o, "truncate", []string{stmt.Table.Suffix()}); err != nil {
+		return nil, err
+	}
+
 	return ret, nil
 }
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	"github.com/arana-db/arana/pkg/runtime/optimize"
+	"github.com/arana-db/arana/pkg/runtime/plan/ddl"
+)
+
+func init() {
+	optimize.Register(ast.SQLTypeCreateView, optimizeCreateView)
+	optimize.Register(ast.SQLTypeDropView, optimizeDropView)
+}
+
+func optimizeCreateView(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+	stmt := o.Stmt.(*ast.CreateViewStatement)
+
+	view := stmt.View.Suffix()
+	if o.Rule.Has(view) {
+		return nil, errors.Errorf("optimize: cannot create view '%s': table '%s' exists already", view, view)
+	}
+	if err := optimize.ValidateView(o.Rule, view, stmt.Definition); err != nil {
+		return nil, err
+	}
+
+	ret := ddl.NewCreateViewPlan(stmt)
+	ret.BindArgs(o.Args)
+	return ret, nil
+}
+
+func optimizeDropView(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+	stmt := o.Stmt.(*ast.DropViewStatement)
+
+	if !stmt.IfExists {
+		for _, it := range stmt.Views {
+			if _, ok := o.Rule.View(it.Suffix()); !ok {
+				return nil, errors.Errorf("optimize: unknown view '%s'", it.Suffix())
+			}
+		}
+	}
+
+	ret := ddl.NewDropViewPlan(stmt)
+	ret.BindArgs(o.Args)
+	return ret, nil
+}
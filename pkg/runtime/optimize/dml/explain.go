@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dml
+
+import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	"github.com/arana-db/arana/pkg/runtime/optimize"
+	"github.com/arana-db/arana/pkg/runtime/plan/dml"
+)
+
+func init() {
+	optimize.Register(ast.SQLTypeExplain, optimizeExplain)
+}
+
+func optimizeExplain(ctx context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+	stmt := o.Stmt.(*ast.ExplainStatement)
+
+	// EXPLAIN ANALYZE executes the statement and measures it in mysql, use EXPLAIN FORMAT = VERBOSE for backend plans.
+	if stmt.Analyze {
+		return nil, errors.New("EXPLAIN ANALYZE is not supported, use EXPLAIN FORMAT = VERBOSE to show the plans of backends")
+	}
+
+	// only the plans without side effects can be explained, the physical statements are collected by executing
+	// the plans with a recorder, eg: the sequence values will be consumed when optimizing INSERT.
+	switch mode := stmt.Target.Mode(); mode {
+	case ast.SQLTypeSelect, ast.SQLTypeUnion, ast.SQLTypeUpdate, ast.SQLTypeDelete:
+	default:
+		return nil, errors.Errorf("EXPLAIN %s is not supported", mode)
+	}
+
+	target := *o
+	target.Stmt = stmt.Target
+
+	p, err := target.Optimize(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to optimize EXPLAIN statement")
+	}
+
+	ret := &dml.ExplainPlan{
+		Plan:    p,
+		Verbose: stmt.Verbose,
+	}
+	ret.BindArgs(o.Args)
+
+	return ret, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dml
+
+import (
+	"sort"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	"github.com/arana-db/arana/pkg/runtime/optimize"
+	"github.com/arana-db/arana/pkg/runtime/plan/dml"
+)
+
+// optimizeInformationSchema returns a virtual plan if the statement queries information_schema.tables/columns/statistics,
+// so that clients see logical tables instead of physical shards.
+func optimizeInformationSchema(o *optimize.Optimizer, stmt *ast.SelectStatement) (proto.Plan, bool, error) {
+	var (
+		table  string
+		tables int
+	)
+	for _, it := range stmt.From {
+		if name, ok := virtualInformationSchema(it); ok {
+			table = name
+		}
+		tables += countTableSources(it)
+	}
+
+	if len(table) < 1 {
+		return nil, false, nil
+	}
+	if tables != 1 {
+		return nil, false, errors.Errorf("multiple tables or joins with information_schema.%s are not supported", table)
+	}
+
+	shards := make(map[string]rule.DatabaseTables)
+	for name, vt := range o.Rule.VTables() {
+		shards[name] = vt.Topology().Enumerate()
+	}
+
+	var views []string
+	o.Rule.RangeViews(func(view, _ string) bool {
+		views = append(views, view)
+		return true
+	})
+	sort.Strings(views)
+
+	ret := dml.NewInformationSchemaPlan(stmt, table)
+	ret.BindArgs(o.Args)
+	ret.SetShards(shards)
+	ret.SetViews(views)
+	return ret, true, nil
+}
+
+// virtualInformationSchema returns the information_schema table served by proxy if the table source
+// or any side of its joins references it.
+func virtualInformationSchema(source *ast.TableSourceNode) (string, bool) {
+	if source == nil {
+		return "", false
+	}
+	if jn, ok := source.Join(); ok {
+		if table, ok := virtualInformationSchema(jn.Left); ok {
+			return table, true
+		}
+		return virtualInformationSchema(jn.Right)
+	}
+
+	tn := source.TableName()
+	if len(tn) != 2 || !strings.EqualFold(tn.Prefix(), "information_schema") {
+		return "", false
+	}
+
+	switch table := strings.ToLower(tn.Suffix()); table {
+	case dml.InformationSchemaTables, dml.InformationSchemaColumns, dml.InformationSchemaStatistics:
+		return table, true
+	}
+	return "", false
+}
+
+func countTableSources(source *ast.TableSourceNode) int {
+	if source == nil {
+		return 0
+	}
+	if jn, ok := source.Join(); ok {
+		return countTableSources(jn.Left) + countTableSources(jn.Right)
+	}
+	return 1
+}
@@ -53,11 +53,17 @@ func init() {
 func optimizeSelect(ctx context.Context, o *optimize.Optimizer) (proto.Plan, error) {
 	stmt := o.Stmt.(*ast.SelectStatement)
 
+	if ret, ok, err := optimizeInformationSchema(o, stmt); err != nil {
+		return nil, errors.WithStack(err)
+	} else if ok {
+		return ret, nil
+	}
+
 	// overwrite stmt limit x offset y. eg `select * from student offset 100 limit 5` will be
 	// `select * from student offset 0 limit 100+5`
 	originOffset, newLimit := overwriteLimit(stmt, &o.Args)
 	if stmt.HasJoin() {
-		return optimizeJoin(o, stmt)
+		return optimizeJoin(ctx, o, stmt)
 	}
 	flag := getSelectFlag(o.Rule, stmt)
 	if flag&_supported == 0 {
@@ -99,7 +105,7 @@ func optimizeSelect(ctx context.Context, o *optimize.Optimizer) (proto.Plan, err
 
 	log.Debugf("compute shards: result=%s, isFullScan=%v", shards, fullScan)
 	// return error if full-scan is disabled
-	if fullScan && (!vt.AllowFullScan() && !hint.Contains(hint.TypeFullScan, o.Hints)) {
+	if fullScan && ((!vt.AllowFullScan() || rcontext.IsDenyFullScan(ctx)) && !hint.Contains(hint.TypeFullScan, o.Hints)) {
 		return nil, errors.WithStack(optimize.ErrDenyFullScan)
 	}
 
@@ -318,7 +324,7 @@ func handleGroupBy(parentPlan proto.Plan, stmt *ast.SelectStatement) (proto.Plan
 }
 
 // optimizeJoin ony support  a join b in one db
-func optimizeJoin(o *optimize.Optimizer, stmt *ast.SelectStatement) (proto.Plan, error) {
+func optimizeJoin(ctx context.Context, o *optimize.Optimizer, stmt *ast.SelectStatement) (proto.Plan, error) {
 	join := stmt.From[0].Source().(*ast.JoinNode)
 
 	compute := func(tableSource *ast.TableSourceNode) (database, alias string, shardList []string, err error) {
@@ -330,7 +336,7 @@ func optimizeJoin(o *optimize.Optimizer, stmt *ast.SelectStatement) (proto.Plan,
 		alias = tableSource.Alias
 		database = table.Prefix()
 
-		shards, err := o.ComputeShards(table, nil, o.Args)
+		shards, err := o.ComputeShards(ctx, table, nil, o.Args)
 		if err != nil {
 			return
 		}
@@ -29,6 +29,7 @@ import (
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/rule"
 	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
 	"github.com/arana-db/arana/pkg/runtime/optimize"
 	"github.com/arana-db/arana/pkg/runtime/plan"
 	"github.com/arana-db/arana/pkg/runtime/plan/dml"
@@ -38,7 +39,7 @@ func init() {
 	optimize.Register(ast.SQLTypeUpdate, optimizeUpdate)
 }
 
-func optimizeUpdate(_ context.Context, o *optimize.Optimizer) (proto.Plan, error) {
+func optimizeUpdate(ctx context.Context, o *optimize.Optimizer) (proto.Plan, error) {
 	var (
 		stmt  = o.Stmt.(*ast.UpdateStatement)
 		table = stmt.Table
@@ -82,7 +83,7 @@ func optimizeUpdate(_ context.Context, o *optimize.Optimizer) (proto.Plan, error
 	}
 
 	// exit if full-scan is disabled
-	if fullScan && !vt.AllowFullScan() {
+	if fullScan && (!vt.AllowFullScan() || rcontext.IsDenyFullScan(ctx)) {
 		return nil, optimize.ErrDenyFullScan
 	}
 
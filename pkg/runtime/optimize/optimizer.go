@@ -48,6 +48,7 @@ var (
 	ErrNoRuleFound     = errors.New("optimize: no rule found")
 	ErrDenyFullScan    = errors.New("optimize: the full-scan query is not allowed")
 	ErrNoShardKeyFound = errors.New("optimize: no shard key found")
+	ErrDenyDestructive = errors.New("optimize: the destructive DDL on sharding tables is not confirmed")
 )
 
 // IsNoShardKeyFoundErr returns true if target error is caused by NO-SHARD-KEY-FOUND
@@ -65,6 +66,11 @@ func IsDenyFullScanErr(err error) bool {
 	return perrors.Is(err, ErrDenyFullScan)
 }
 
+// IsDenyDestructiveErr returns true if target error is caused by DENY-DESTRUCTIVE.
+func IsDenyDestructiveErr(err error) bool {
+	return perrors.Is(err, ErrDenyDestructive)
+}
+
 var _handlers = make(map[rast.SQLType]Processor)
 
 func Register(t rast.SQLType, h Processor) {
@@ -85,6 +91,9 @@ func NewOptimizer(rule *rule.Rule, hints []*hint.Hint, stmt ast.StmtNode, args [
 		rstmt rast.Statement
 		err   error
 	)
+	if err = expandViews(rule, stmt); err != nil {
+		return nil, err
+	}
 	if rstmt, err = rast.FromStmtNode(stmt); err != nil {
 		return nil, perrors.Wrap(err, "optimize failed")
 	}
@@ -115,7 +124,20 @@ func (o *Optimizer) Optimize(ctx context.Context) (plan proto.Plan, err error) {
 	return h(ctx, o)
 }
 
-func (o *Optimizer) ComputeShards(table rast.TableName, where rast.ExpressionNode, args []interface{}) (rule.DatabaseTables, error) {
+// isDenyFullScan returns true if full-scan is denied by the firewall, which only guards the queries and DMLs,
+// the DDLs on all shards are guarded by the CONFIRM hint instead.
+func (o *Optimizer) isDenyFullScan(ctx context.Context) bool {
+	if !rcontext.IsDenyFullScan(ctx) {
+		return false
+	}
+	switch o.Stmt.Mode() {
+	case rast.SQLTypeSelect, rast.SQLTypeUpdate, rast.SQLTypeDelete:
+		return true
+	}
+	return false
+}
+
+func (o *Optimizer) ComputeShards(ctx context.Context, table rast.TableName, where rast.ExpressionNode, args []interface{}) (rule.DatabaseTables, error) {
 	ru := o.Rule
 	vt, ok := ru.VTable(table.Suffix())
 	if !ok {
@@ -142,7 +164,7 @@ func (o *Optimizer) ComputeShards(table rast.TableName, where rast.ExpressionNod
 	// log.Debugf("compute shards: result=%s, isFullScan=%v", shards, fullScan)
 
 	// return error if full-scan is disabled
-	if fullScan && !vt.AllowFullScan() {
+	if fullScan && (!vt.AllowFullScan() || o.isDenyFullScan(ctx)) {
 		return nil, perrors.WithStack(ErrDenyFullScan)
 	}
 
@@ -19,7 +19,9 @@ package optimize_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 )
@@ -33,14 +35,22 @@ import (
 )
 
 import (
+	consts "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/mysql/rows"
 	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/hint"
 	"github.com/arana-db/arana/pkg/proto/rule"
 	"github.com/arana-db/arana/pkg/resultx"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
 	. "github.com/arana-db/arana/pkg/runtime/optimize"
 	_ "github.com/arana-db/arana/pkg/runtime/optimize/dal"
 	_ "github.com/arana-db/arana/pkg/runtime/optimize/ddl"
 	_ "github.com/arana-db/arana/pkg/runtime/optimize/dml"
 	_ "github.com/arana-db/arana/pkg/runtime/optimize/utility"
+	"github.com/arana-db/arana/pkg/runtime/plan/ddl"
+	"github.com/arana-db/arana/pkg/security"
 	"github.com/arana-db/arana/testdata"
 )
 
@@ -251,6 +261,550 @@ func TestOptimizer_OptimizeAlterTable(t *testing.T) {
 	})
 }
 
+func TestOptimizer_OptimizeRenameTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var executed []string
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			t.Logf("fake exec: db='%s', sql=\"%s\", args=%v\n", db, sql, args)
+			executed = append(executed, sql)
+			return resultx.New(), nil
+		}).AnyTimes()
+
+	rm := testdata.NewMockRuleManager(ctrl)
+	defer proto.RegisterRuleManager(proto.LoadRuleManager())
+	proto.RegisterRuleManager(rm)
+
+	var (
+		ctx      = context.Background()
+		ru       rule.Rule
+		tab      rule.VTable
+		topology rule.Topology
+	)
+
+	topology.SetRender(func(_ int) string {
+		return "fake_db"
+	}, func(i int) string {
+		return fmt.Sprintf("student_%04d", i)
+	})
+	tables := make([]int, 0, 8)
+	for i := 0; i < 8; i++ {
+		tables = append(tables, i)
+	}
+	topology.SetTopology(0, tables...)
+	tab.SetTopology(&topology)
+	tab.SetAllowFullScan(true)
+	tab.SetShardMetadata("uid", nil, &rule.ShardMetadata{Steps: 8})
+	ru.SetVTable("student", &tab)
+
+	t.Run("sharding", func(t *testing.T) {
+		executed = nil
+		rm.EXPECT().RenameTable(gomock.Any(), gomock.Any(), "student", "student_new").Return(nil).Times(1)
+
+		p := parser.New()
+		stmt, _ := p.ParseOneStmt("rename table student to student_new", "", "")
+
+		opt, err := NewOptimizer(&ru, nil, stmt, nil)
+		assert.NoError(t, err)
+
+		plan, err := opt.Optimize(ctx)
+		assert.NoError(t, err)
+
+		_, err = plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+		assert.Len(t, executed, 1)
+		assert.Contains(t, executed[0], "`student_0007` TO `student_new_0007`")
+	})
+
+	t.Run("alter table rename", func(t *testing.T) {
+		executed = nil
+		rm.EXPECT().RenameColumn(gomock.Any(), gomock.Any(), "student", "uid", "user_id").Return(nil).Times(1)
+		rm.EXPECT().RenameTable(gomock.Any(), gomock.Any(), "student", "student_new").Return(nil).Times(1)
+
+		p := parser.New()
+		stmt, _ := p.ParseOneStmt("alter table student rename column uid to user_id, rename to student_new", "", "")
+
+		opt, err := NewOptimizer(&ru, nil, stmt, nil)
+		assert.NoError(t, err)
+
+		plan, err := opt.Optimize(ctx)
+		assert.NoError(t, err)
+
+		_, err = plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+		assert.Len(t, executed, 8)
+		for _, it := range executed {
+			assert.Contains(t, it, "RENAME AS `student_new_")
+		}
+	})
+
+	t.Run("rename to existing table", func(t *testing.T) {
+		ru.SetVTable("student_new", ru.MustVTable("student"))
+		defer ru.RemoveVTable("student_new")
+
+		p := parser.New()
+		stmt, _ := p.ParseOneStmt("rename table student to student_new", "", "")
+
+		opt, err := NewOptimizer(&ru, nil, stmt, nil)
+		assert.NoError(t, err)
+
+		_, err = opt.Optimize(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestOptimizer_DDLJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		broken   = true
+		executed []string
+	)
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			t.Logf("fake exec: db='%s', sql=\"%s\", args=%v\n", db, sql, args)
+			if broken && strings.Contains(sql, "student_0003") {
+				return nil, errors.New("fake error")
+			}
+			executed = append(executed, sql)
+			return resultx.New(), nil
+		}).AnyTimes()
+
+	var (
+		ctx      = rcontext.WithSchema(context.Background(), "fake_ddl_jobs")
+		ru       rule.Rule
+		tab      rule.VTable
+		topology rule.Topology
+	)
+
+	topology.SetRender(func(_ int) string {
+		return "fake_db"
+	}, func(i int) string {
+		return fmt.Sprintf("student_%04d", i)
+	})
+	topology.SetTopology(0, 0, 1, 2, 3, 4, 5, 6, 7)
+	tab.SetTopology(&topology)
+	tab.SetAllowFullScan(true)
+	ru.SetVTable("student", &tab)
+
+	execute := func(sql string) (proto.Result, error) {
+		p := parser.New()
+		stmt, err := p.ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+
+		opt, err := NewOptimizer(&ru, nil, stmt, nil)
+		assert.NoError(t, err)
+
+		plan, err := opt.Optimize(ctx)
+		assert.NoError(t, err)
+
+		return plan.ExecIn(ctx, conn)
+	}
+
+	countRows := func(res proto.Result) int {
+		ds, err := res.Dataset()
+		assert.NoError(t, err)
+		var cnt int
+		for {
+			if _, err = ds.Next(); err != nil {
+				assert.Equal(t, io.EOF, err)
+				return cnt
+			}
+			cnt++
+		}
+	}
+
+	const alter = "alter table student add dept_id int not null default 0"
+
+	// fail on the 4th physical table
+	_, err := execute(alter)
+	assert.Error(t, err)
+	assert.Len(t, executed, 3)
+
+	res, err := execute("admin show ddl jobs")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, countRows(res))
+
+	jobs := ddl.DefaultJobManager().List("fake_ddl_jobs")
+	assert.Len(t, jobs, 1)
+	id := jobs[0].ID
+
+	res, err = execute(fmt.Sprintf("admin show ddl job queries %d", id))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, countRows(res))
+
+	// resume the failed job, only the rest physical tables will be altered
+	broken = false
+	executed = nil
+	_, err = execute(alter)
+	assert.NoError(t, err)
+	assert.Len(t, executed, 5)
+
+	// rollback with reverse statements
+	executed = nil
+	res, err = execute(fmt.Sprintf("admin cancel ddl jobs %d", id))
+	assert.NoError(t, err)
+	affected, _ := res.RowsAffected()
+	assert.Equal(t, uint64(8), affected)
+	assert.Len(t, executed, 8)
+	for _, it := range executed {
+		assert.Contains(t, it, "DROP COLUMN `dept_id`")
+	}
+
+	_, err = execute(fmt.Sprintf("admin cancel ddl jobs %d", id))
+	assert.Error(t, err, "cannot cancel a cancelled job")
+}
+
+func TestOptimizer_CheckTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checker := testdata.NewMockSchemaChecker(ctrl)
+	defer proto.RegisterSchemaChecker(proto.LoadSchemaChecker())
+	proto.RegisterSchemaChecker(checker)
+
+	checker.EXPECT().CheckTable(gomock.Any(), "employees", "student").
+		Return([]*proto.SchemaDrift{
+			{Group: "employees_0000", Table: "student_0001", Kind: proto.SchemaDriftColumn, Name: "uid", Detail: "is missing"},
+			{Group: "employees_0001", Table: "student_0005", Kind: proto.SchemaDriftIndex, Name: "idx_uid", Detail: "is missing"},
+		}, nil).
+		Times(1)
+
+	var (
+		ctx = rcontext.WithSchema(context.Background(), "employees")
+		ru  rule.Rule
+	)
+	ru.SetVTable("student", new(rule.VTable))
+
+	p := parser.New()
+	stmt, _ := p.ParseOneStmt("admin check table student, salaries", "", "")
+
+	opt, err := NewOptimizer(&ru, nil, stmt, nil)
+	assert.NoError(t, err)
+
+	plan, err := opt.Optimize(ctx)
+	assert.NoError(t, err)
+
+	res, err := plan.ExecIn(ctx, testdata.NewMockVConn(ctrl))
+	assert.NoError(t, err)
+
+	ds, err := res.Dataset()
+	assert.NoError(t, err)
+
+	var messages []string
+	for {
+		row, err := ds.Next()
+		if err != nil {
+			break
+		}
+		values := make([]proto.Value, 4)
+		_ = row.Scan(values)
+		messages = append(messages, fmt.Sprintf("%v %v", values[2], values[3]))
+	}
+
+	assert.Equal(t, []string{
+		"error employees_0000.student_0001: column `uid` is missing",
+		"error employees_0001.student_0005: index `idx_uid` is missing",
+		"note The table is not a sharding table",
+	}, messages)
+}
+
+func TestOptimizer_View(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var queried []string
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			t.Logf("fake query: db=%s, sql=%s, args=%v\n", db, sql, args)
+			queried = append(queried, sql)
+
+			ds := testdata.NewMockDataset(ctrl)
+			ds.EXPECT().Fields().Return([]proto.Field{}, nil).AnyTimes()
+
+			return resultx.New(resultx.WithDataset(ds)), nil
+		}).
+		AnyTimes()
+
+	rm := testdata.NewMockRuleManager(ctrl)
+	defer proto.RegisterRuleManager(proto.LoadRuleManager())
+	proto.RegisterRuleManager(rm)
+
+	var (
+		ctx = rcontext.WithSchema(context.Background(), "employees")
+		ru  = makeFakeRule(ctrl, 8)
+	)
+
+	optimize := func(sql string) (proto.Plan, error) {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		opt, err := NewOptimizer(ru, nil, stmt, nil)
+		if err != nil {
+			return nil, err
+		}
+		return opt.Optimize(ctx)
+	}
+
+	t.Run("create view", func(t *testing.T) {
+		rm.EXPECT().
+			CreateView(gomock.Any(), "employees", "v_student", "SELECT `id`,`uid`,`name` AS `student_name` FROM `student` WHERE `gender`=1", false).
+			Return(nil).
+			Times(1)
+
+		plan, err := optimize("create view v_student as select id, uid, name as student_name from student where gender = 1")
+		assert.NoError(t, err)
+		_, err = plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+
+		_, err = optimize("create view v_cnt as select uid, count(1) as cnt from student group by uid")
+		assert.Error(t, err)
+
+		_, err = optimize("create view student as select id from student")
+		assert.Error(t, err)
+	})
+
+	t.Run("select view", func(t *testing.T) {
+		ru.SetView("v_student", "select id, uid, name as student_name from student where gender = 1")
+		defer ru.RemoveView("v_student")
+
+		plan, err := optimize("select student_name from v_student where uid = 3")
+		assert.NoError(t, err)
+		_, _ = plan.ExecIn(ctx, conn)
+
+		assert.Len(t, queried, 1)
+		assert.Contains(t, queried[0], "`student_0003`")
+		assert.Contains(t, queried[0], "`gender` = 1 AND `uid` = 3")
+	})
+
+	t.Run("drop view", func(t *testing.T) {
+		ru.SetView("v_student", "select id, uid, name as student_name from student where gender = 1")
+		defer ru.RemoveView("v_student")
+
+		rm.EXPECT().DropView(gomock.Any(), "employees", "v_student", false).Return(nil).Times(1)
+
+		plan, err := optimize("drop view v_student")
+		assert.NoError(t, err)
+		_, err = plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+
+		_, err = optimize("drop view v_unknown")
+		assert.Error(t, err)
+	})
+}
+
+func TestOptimizer_DestructiveDDL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var executed []string
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			t.Logf("fake exec: db='%s', sql=\"%s\", args=%v\n", db, sql, args)
+			executed = append(executed, sql)
+			return resultx.New(), nil
+		}).AnyTimes()
+
+	var (
+		ctx      = rcontext.WithTenant(context.Background(), "fake_tenant")
+		ru       rule.Rule
+		tab      rule.VTable
+		topology rule.Topology
+	)
+
+	topology.SetRender(func(i int) string {
+		return fmt.Sprintf("employees_%04d", i%2)
+	}, func(i int) string {
+		return fmt.Sprintf("student_%04d", i)
+	})
+	topology.SetTopology(0, 0, 2)
+	topology.SetTopology(1, 1, 3)
+	tab.SetTopology(&topology)
+	tab.SetAllowFullScan(true)
+	ru.SetVTable("student", &tab)
+
+	optimize := func(sql string, hints ...*hint.Hint) (proto.Plan, error) {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		opt, err := NewOptimizer(&ru, hints, stmt, nil)
+		assert.NoError(t, err)
+		return opt.Optimize(ctx)
+	}
+
+	dryRun := func(sql string) []string {
+		plan, err := optimize(sql, &hint.Hint{Type: hint.TypeDryRun})
+		assert.NoError(t, err)
+		res, err := plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+		ds, err := res.Dataset()
+		assert.NoError(t, err)
+
+		var statements []string
+		for {
+			row, err := ds.Next()
+			if err != nil {
+				break
+			}
+			values := make([]proto.Value, 2)
+			_ = row.Scan(values)
+			statements = append(statements, fmt.Sprintf("%v: %v", values[0], values[1]))
+		}
+		return statements
+	}
+
+	for _, sql := range []string{"truncate table student", "drop table student, salaries"} {
+		_, err := optimize(sql)
+		assert.True(t, IsDenyDestructiveErr(err))
+	}
+
+	assert.Equal(t, []string{
+		"employees_0000: TRUNCATE TABLE `student_0000`",
+		"employees_0000: TRUNCATE TABLE `student_0002`",
+		"employees_0001: TRUNCATE TABLE `student_0001`",
+		"employees_0001: TRUNCATE TABLE `student_0003`",
+	}, dryRun("truncate table student"))
+	assert.Equal(t, []string{
+		": DROP TABLE `salaries`",
+		"employees_0000: DROP TABLE `student_0000`, `student_0002`",
+		"employees_0001: DROP TABLE `student_0001`, `student_0003`",
+	}, dryRun("drop table student, salaries"))
+	assert.Empty(t, executed)
+
+	plan, err := optimize("truncate table student", &hint.Hint{Type: hint.TypeConfirm})
+	assert.NoError(t, err)
+	_, err = plan.ExecIn(ctx, conn)
+	assert.NoError(t, err)
+	assert.Len(t, executed, 4)
+
+	// the full-scan denied by firewall doesn't block the confirmed DDL
+	denied := rcontext.WithDenyFullScan(ctx)
+	stmt, err := parser.New().ParseOneStmt("truncate table student", "", "")
+	assert.NoError(t, err)
+	opt, err := NewOptimizer(&ru, []*hint.Hint{{Type: hint.TypeConfirm}}, stmt, nil)
+	assert.NoError(t, err)
+	plan, err = opt.Optimize(denied)
+	assert.NoError(t, err)
+	_, err = plan.ExecIn(denied, conn)
+	assert.NoError(t, err)
+	assert.Len(t, executed, 8)
+
+	stmt, err = parser.New().ParseOneStmt("delete from student", "", "")
+	assert.NoError(t, err)
+	opt, err = NewOptimizer(&ru, nil, stmt, nil)
+	assert.NoError(t, err)
+	_, err = opt.Optimize(denied)
+	assert.True(t, IsDenyFullScanErr(err))
+
+	security.DefaultTenantManager().SetAllowDestructiveDDL("fake_tenant", true)
+	defer security.DefaultTenantManager().SetAllowDestructiveDDL("fake_tenant", false)
+
+	_, err = optimize("drop table student")
+	assert.NoError(t, err)
+}
+
+func TestOptimizer_Explain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fields := []proto.Field{
+		mysql.NewField("id", consts.FieldTypeLongLong),
+		mysql.NewField("type", consts.FieldTypeVarString),
+	}
+
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			assert.True(t, strings.HasPrefix(sql, "EXPLAIN "))
+			ds := &dataset.VirtualDataset{
+				Columns: fields,
+				Rows: []proto.Row{
+					rows.NewTextVirtualRow(fields, []proto.Value{int64(1), "range"}),
+				},
+			}
+			return resultx.New(resultx.WithDataset(ds)), nil
+		}).
+		AnyTimes()
+
+	var (
+		ctx = context.Background()
+		ru  = makeFakeRule(ctrl, 8)
+	)
+
+	explain := func(sql string) []string {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		opt, err := NewOptimizer(ru, nil, stmt, []interface{}{1, 2})
+		assert.NoError(t, err)
+		plan, err := opt.Optimize(ctx)
+		assert.NoError(t, err)
+		res, err := plan.ExecIn(ctx, conn)
+		assert.NoError(t, err)
+		ds, err := res.Dataset()
+		assert.NoError(t, err)
+
+		var ret []string
+		for {
+			row, err := ds.Next()
+			if err != nil {
+				break
+			}
+			values := make([]proto.Value, 4)
+			_ = row.Scan(values)
+			ret = append(ret, fmt.Sprintf("%v|%v|%v", values[1], values[2], values[3]))
+		}
+		return ret
+	}
+
+	actual := explain("explain select id, uid from student where uid in (?, ?) order by id")
+	assert.Equal(t, []string{
+		"Aggregate||",
+		"└─Order||order by id",
+		"  └─Composite||",
+		"    └─SimpleQuery||",
+		"      └─Query|fake_db|SELECT * FROM ((SELECT `id`,`uid` FROM `student_0001` WHERE `uid` IN (?,?) ORDER BY `id`) " +
+			"UNION ALL (SELECT `id`,`uid` FROM `student_0002` WHERE `uid` IN (?,?) ORDER BY `id`))  T  ORDER BY `id`, args=[1 2 1 2]",
+	}, actual)
+
+	actual = explain("explain format = verbose select id, uid from student where uid = 1")
+	assert.Equal(t, []string{
+		"SimpleQuery||",
+		"└─Query|fake_db|SELECT `id`,`uid` FROM `student_0001` WHERE `uid` = 1",
+		"  └─Backend|fake_db|id=1, type=range",
+	}, actual)
+
+	// the UPDATE and DELETE are never executed, only the EXPLAIN of them are sent to backends,
+	// which is guaranteed by the conn mock without any Exec expectation.
+	actual = explain("explain format = verbose delete from student where uid = 1")
+	assert.Equal(t, []string{
+		"SimpleDelete||",
+		"└─Exec|fake_db|DELETE FROM `student_0001` WHERE `uid` = 1",
+		"  └─Backend|fake_db|id=1, type=range",
+	}, actual)
+
+	failed := func(sql string) {
+		stmt, err := parser.New().ParseOneStmt(sql, "", "")
+		assert.NoError(t, err)
+		opt, err := NewOptimizer(ru, nil, stmt, nil)
+		assert.NoError(t, err)
+		_, err = opt.Optimize(ctx)
+		assert.Error(t, err, sql)
+	}
+
+	// INSERT is not side-effect free, eg: the sequence values will be consumed
+	failed("explain insert into student(uid, name) values(1, 'foo')")
+
+	// EXPLAIN ANALYZE executes the statement in mysql, which is not supported
+	failed("explain analyze select id, uid from student where uid = 1")
+	failed("explain analyze update student set name = 'foo' where uid = 1")
+	failed("explain analyze delete from student where uid = 1")
+}
+
 func TestOptimizer_OptimizeInsertSelect(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -298,3 +852,124 @@ func TestOptimizer_OptimizeInsertSelect(t *testing.T) {
 		assert.Equal(t, fakeId, lastInsertId)
 	})
 }
+
+func TestOptimizer_InformationSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fields := []proto.Field{
+		mysql.NewField("TABLE_SCHEMA", consts.FieldTypeVarString),
+		mysql.NewField("TABLE_NAME", consts.FieldTypeVarString),
+		mysql.NewField("TABLE_TYPE", consts.FieldTypeVarString),
+		mysql.NewField("TABLE_ROWS", consts.FieldTypeLongLong),
+		mysql.NewField("AVG_ROW_LENGTH", consts.FieldTypeLongLong),
+		mysql.NewField("DATA_LENGTH", consts.FieldTypeLongLong),
+	}
+
+	newRow := func(table string, n int64) proto.Row {
+		return rows.NewTextVirtualRow(fields, []proto.Value{"employees_0000", table, "BASE TABLE", n, int64(100), n * 100})
+	}
+
+	var queried []string
+	conn := testdata.NewMockVConn(ctrl)
+	conn.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, db string, sql string, args ...interface{}) (proto.Result, error) {
+			t.Logf("fake query: db=%s, sql=%s, args=%v\n", db, sql, args)
+
+			ds := &dataset.VirtualDataset{Columns: fields}
+			switch {
+			case !strings.Contains(sql, "DATABASE()"):
+				queried = append(queried, sql)
+			case db == "":
+				ds.Rows = append(ds.Rows, newRow("student_0000", 1), newRow("teacher", 5))
+			default:
+				for i := 0; i < 4; i++ {
+					ds.Rows = append(ds.Rows, newRow(fmt.Sprintf("student_%04d", i), int64(i+1)))
+				}
+			}
+			return resultx.New(resultx.WithDataset(ds)), nil
+		}).
+		AnyTimes()
+
+	var (
+		ctx  = rcontext.WithSchema(context.Background(), "employees")
+		ru   rule.Rule
+		tab  rule.VTable
+		topo rule.Topology
+	)
+
+	topo.SetRender(func(_ int) string {
+		return "employees_0000"
+	}, func(i int) string {
+		return fmt.Sprintf("student_%04d", i)
+	})
+	topo.SetTopology(0, 0, 1, 2, 3)
+	tab.SetTopology(&topo)
+	tab.SetName("student")
+	ru.SetVTable("student", &tab)
+	ru.SetView("v_student", "select id from student")
+
+	stmt, err := parser.New().ParseOneStmt("select table_name, table_rows from information_schema.TABLES t where t.table_schema = 'employees' order by table_name limit 1, 10", "", "")
+	assert.NoError(t, err)
+	opt, err := NewOptimizer(&ru, nil, stmt, nil)
+	assert.NoError(t, err)
+	plan, err := opt.Optimize(ctx)
+	assert.NoError(t, err)
+	res, err := plan.ExecIn(ctx, conn)
+	assert.NoError(t, err)
+
+	assert.Empty(t, queried)
+
+	ds, err := res.Dataset()
+	assert.NoError(t, err)
+	defer ds.Close()
+
+	columns, err := ds.Fields()
+	assert.NoError(t, err)
+	assert.Len(t, columns, 2)
+	assert.Equal(t, "TABLE_NAME", columns[0].Name())
+	assert.Equal(t, "TABLE_ROWS", columns[1].Name())
+
+	var actual [][]proto.Value
+	for {
+		next, err := ds.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		dest := make([]proto.Value, len(columns))
+		assert.NoError(t, next.Scan(dest))
+		actual = append(actual, dest)
+	}
+	assert.Equal(t, [][]proto.Value{{"teacher", int64(5)}, {"v_student", nil}}, actual)
+
+	// the aggregated rows are filtered and projected in proxy
+	stmt, err = parser.New().ParseOneStmt("select table_name as name, table_rows from information_schema.tables where table_name like 'stu%' and table_rows between ? and 100", "", "")
+	assert.NoError(t, err)
+	opt, err = NewOptimizer(&ru, nil, stmt, []interface{}{int64(10)})
+	assert.NoError(t, err)
+	plan, err = opt.Optimize(ctx)
+	assert.NoError(t, err)
+	res, err = plan.ExecIn(ctx, conn)
+	assert.NoError(t, err)
+	ds, err = res.Dataset()
+	assert.NoError(t, err)
+	columns, err = ds.Fields()
+	assert.NoError(t, err)
+	assert.Equal(t, "name", columns[0].Name())
+	next, err := ds.Next()
+	assert.NoError(t, err)
+	dest := make([]proto.Value, len(columns))
+	assert.NoError(t, next.Scan(dest))
+	assert.Equal(t, []proto.Value{"student", uint64(10)}, dest)
+	_, err = ds.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// joins with information_schema are not supported
+	stmt, err = parser.New().ParseOneStmt("select a.table_name from information_schema.tables a join information_schema.columns b on a.table_name = b.table_name", "", "")
+	assert.NoError(t, err)
+	opt, err = NewOptimizer(&ru, nil, stmt, nil)
+	assert.NoError(t, err)
+	_, err = opt.Optimize(ctx)
+	assert.Error(t, err)
+}
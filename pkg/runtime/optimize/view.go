@@ -0,0 +1,434 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package optimize
+
+import (
+	"github.com/arana-db/parser"
+	"github.com/arana-db/parser/ast"
+	"github.com/arana-db/parser/model"
+	"github.com/arana-db/parser/opcode"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto/rule"
+)
+
+// _maxViewDepth is the max depth of nested views.
+const _maxViewDepth = 8
+
+// logicalView is a parsed view which can be merged into the outer query.
+type logicalView struct {
+	name     string
+	table    *ast.TableName
+	alias    model.CIStr
+	fields   []*ast.SelectField
+	columns  map[string]ast.ExprNode // lowercase column name -> expression
+	wildcard bool
+	where    ast.ExprNode
+}
+
+// ValidateView checks if the definition of view can be expanded when querying.
+// Only the view which selects from a single table without DISTINCT, GROUP BY, ORDER BY, LIMIT
+// or aggregate functions is supported.
+func ValidateView(ru *rule.Rule, view, definition string) error {
+	_, err := parseView(ru, view, definition, 0)
+	return err
+}
+
+// expandViews rewrites the statement in place, the references of views will be replaced with their definitions,
+// including the views in subqueries and derived tables. The views cannot be modified by INSERT, UPDATE or DELETE.
+func expandViews(ru *rule.Rule, stmt ast.StmtNode) error {
+	if !ru.HasViews() {
+		return nil
+	}
+
+	if explain, ok := stmt.(*ast.ExplainStmt); ok {
+		return expandViews(ru, explain.Stmt)
+	}
+
+	var targets []ast.Node
+	switch t := stmt.(type) {
+	case *ast.InsertStmt:
+		targets = append(targets, t.Table)
+	case *ast.UpdateStmt:
+		targets = append(targets, t.TableRefs)
+	case *ast.DeleteStmt:
+		targets = append(targets, t.TableRefs)
+		if t.Tables != nil {
+			targets = append(targets, t.Tables)
+		}
+	}
+	for _, it := range targets {
+		if it == nil {
+			continue
+		}
+		detector := viewDetector{ru: ru}
+		it.Accept(&detector)
+		if len(detector.view) > 0 {
+			return perrors.Errorf("optimize: view '%s' is not updatable", detector.view)
+		}
+	}
+
+	expander := viewExpander{ru: ru}
+	stmt.Accept(&expander)
+	return expander.err
+}
+
+// viewExpander expands the views of all SELECT statements, the inner statements will be expanded first.
+type viewExpander struct {
+	ru  *rule.Rule
+	err error
+}
+
+func (ve *viewExpander) Enter(n ast.Node) (ast.Node, bool) {
+	return n, ve.err != nil
+}
+
+func (ve *viewExpander) Leave(n ast.Node) (ast.Node, bool) {
+	if sel, ok := n.(*ast.SelectStmt); ok && ve.err == nil {
+		ve.err = expandSelectViews(ve.ru, sel, 0)
+	}
+	return n, ve.err == nil
+}
+
+func expandSelectViews(ru *rule.Rule, sel *ast.SelectStmt, depth int) error {
+	if sel.From == nil {
+		return nil
+	}
+
+	ts := singleTableSource(sel.From)
+	if ts == nil {
+		// views are not allowed in JOIN
+		var detector viewDetector
+		detector.ru = ru
+		sel.From.Accept(&detector)
+		if len(detector.view) > 0 {
+			return perrors.Errorf("optimize: view '%s' cannot be joined", detector.view)
+		}
+		return nil
+	}
+
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return nil
+	}
+
+	definition, ok := ru.View(tn.Name.O)
+	if !ok {
+		return nil
+	}
+
+	if depth >= _maxViewDepth {
+		return perrors.Errorf("optimize: too many nested views when expanding view '%s'", tn.Name.O)
+	}
+
+	view, err := parseView(ru, tn.Name.O, definition, depth+1)
+	if err != nil {
+		return err
+	}
+
+	rewriter := &viewColumnRewriter{
+		view:      view,
+		qualifier: tn.Name.L,
+	}
+	if len(ts.AsName.L) > 0 {
+		rewriter.qualifier = ts.AsName.L
+	}
+
+	// 1. rewrite select fields
+	var fields []*ast.SelectField
+	for _, it := range sel.Fields.Fields {
+		if it.WildCard != nil {
+			if tbl := it.WildCard.Table.L; len(tbl) > 0 && tbl != rewriter.qualifier {
+				return perrors.Errorf("optimize: unknown table '%s'", it.WildCard.Table.O)
+			}
+			fields = append(fields, view.fields...)
+			continue
+		}
+
+		column, isColumn := it.Expr.(*ast.ColumnNameExpr)
+		if it.Expr, err = rewriter.rewrite(it.Expr); err != nil {
+			return err
+		}
+		// keep the column name which is visible to client
+		if replaced, ok := it.Expr.(*ast.ColumnNameExpr); isColumn && len(it.AsName.L) < 1 &&
+			(!ok || replaced.Name.Name.L != column.Name.Name.L) {
+			it.AsName = column.Name.Name
+		}
+		if len(it.AsName.L) > 0 {
+			if rewriter.aliases == nil {
+				rewriter.aliases = make(map[string]struct{})
+			}
+			rewriter.aliases[it.AsName.L] = struct{}{}
+		}
+		fields = append(fields, it)
+	}
+	sel.Fields.Fields = fields
+
+	// 2. rewrite where, group by, having and order by
+	if sel.Where != nil {
+		if sel.Where, err = rewriter.rewrite(sel.Where); err != nil {
+			return err
+		}
+	}
+	for _, it := range []ast.Node{sel.GroupBy, sel.Having, sel.OrderBy} {
+		if isNilNode(it) {
+			continue
+		}
+		if _, err = rewriter.rewrite(it); err != nil {
+			return err
+		}
+	}
+
+	// 3. replace view with the table, then merge the filter of view
+	ts.Source = view.table
+	ts.AsName = view.alias
+	sel.Where = andExpr(view.where, sel.Where)
+
+	return nil
+}
+
+func parseView(ru *rule.Rule, name, definition string, depth int) (*logicalView, error) {
+	stmt, err := parser.New().ParseOneStmt(definition, "", "")
+	if err != nil {
+		return nil, perrors.Wrapf(err, "optimize: failed to parse view '%s'", name)
+	}
+
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok {
+		return nil, perrors.Errorf("optimize: view '%s' must be defined by a SELECT statement", name)
+	}
+
+	// expand the nested views first
+	if err = expandSelectViews(ru, sel, depth); err != nil {
+		return nil, err
+	}
+
+	notMergeable := func(reason string) error {
+		return perrors.Errorf("optimize: view '%s' is not mergeable: %s", name, reason)
+	}
+
+	switch {
+	case sel.Distinct:
+		return nil, notMergeable("DISTINCT is not supported")
+	case sel.GroupBy != nil, sel.Having != nil:
+		return nil, notMergeable("GROUP BY is not supported")
+	case sel.OrderBy != nil:
+		return nil, notMergeable("ORDER BY is not supported")
+	case sel.Limit != nil:
+		return nil, notMergeable("LIMIT is not supported")
+	case len(sel.WindowSpecs) > 0:
+		return nil, notMergeable("WINDOW is not supported")
+	case sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone:
+		return nil, notMergeable("locking read is not supported")
+	}
+
+	ts := singleTableSource(sel.From)
+	if ts == nil {
+		return nil, notMergeable("only single table is supported")
+	}
+	table, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return nil, notMergeable("only single table is supported")
+	}
+
+	var checker viewChecker
+	sel.Fields.Accept(&checker)
+	if sel.Where != nil {
+		sel.Where.Accept(&checker)
+	}
+	if len(checker.reason) > 0 {
+		return nil, notMergeable(checker.reason)
+	}
+
+	view := &logicalView{
+		name:    name,
+		table:   table,
+		alias:   ts.AsName,
+		fields:  sel.Fields.Fields,
+		columns: make(map[string]ast.ExprNode, len(sel.Fields.Fields)),
+		where:   sel.Where,
+	}
+
+	for _, it := range sel.Fields.Fields {
+		if it.WildCard != nil {
+			view.wildcard = true
+			continue
+		}
+		var column string
+		if len(it.AsName.L) > 0 {
+			column = it.AsName.L
+		} else if c, ok := it.Expr.(*ast.ColumnNameExpr); ok {
+			column = c.Name.Name.L
+		} else {
+			return nil, perrors.Errorf("optimize: the expression of view '%s' must have an alias", name)
+		}
+		if _, ok := view.columns[column]; ok {
+			return nil, perrors.Errorf("optimize: duplicate column name '%s' in view '%s'", column, name)
+		}
+		view.columns[column] = it.Expr
+	}
+
+	return view, nil
+}
+
+// singleTableSource returns the table source if only one table exists in FROM clause.
+func singleTableSource(from *ast.TableRefsClause) *ast.TableSource {
+	if from == nil || from.TableRefs == nil || from.TableRefs.Right != nil {
+		return nil
+	}
+	ts, _ := from.TableRefs.Left.(*ast.TableSource)
+	return ts
+}
+
+func andExpr(left, right ast.ExprNode) ast.ExprNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	wrap := func(expr ast.ExprNode) ast.ExprNode {
+		if b, ok := expr.(*ast.BinaryOperationExpr); ok && (b.Op == opcode.LogicOr || b.Op == opcode.LogicXor) {
+			return &ast.ParenthesesExpr{Expr: expr}
+		}
+		return expr
+	}
+
+	return &ast.BinaryOperationExpr{
+		Op: opcode.LogicAnd,
+		L:  wrap(left),
+		R:  wrap(right),
+	}
+}
+
+func isNilNode(n ast.Node) bool {
+	switch t := n.(type) {
+	case *ast.GroupByClause:
+		return t == nil
+	case *ast.HavingClause:
+		return t == nil
+	case *ast.OrderByClause:
+		return t == nil
+	default:
+		return n == nil
+	}
+}
+
+// viewColumnRewriter replaces the columns of view with the expressions of view definition.
+type viewColumnRewriter struct {
+	view      *logicalView
+	qualifier string              // the name or alias of view in outer query
+	aliases   map[string]struct{} // the alias names of outer select fields
+	err       error
+}
+
+func (vr *viewColumnRewriter) rewrite(node ast.Node) (ast.ExprNode, error) {
+	n, _ := node.Accept(vr)
+	if vr.err != nil {
+		return nil, vr.err
+	}
+	expr, _ := n.(ast.ExprNode)
+	return expr, nil
+}
+
+func (vr *viewColumnRewriter) Enter(n ast.Node) (ast.Node, bool) {
+	// skip subqueries, the columns inside belong to other tables
+	if _, ok := n.(*ast.SubqueryExpr); ok {
+		return n, true
+	}
+	return n, false
+}
+
+func (vr *viewColumnRewriter) Leave(n ast.Node) (ast.Node, bool) {
+	c, ok := n.(*ast.ColumnNameExpr)
+	if !ok {
+		return n, true
+	}
+
+	name := c.Name
+	if tbl := name.Table.L; len(tbl) > 0 && tbl != vr.qualifier {
+		vr.err = perrors.Errorf("optimize: unknown column '%s.%s'", name.Table.O, name.Name.O)
+		return n, false
+	}
+
+	if expr, ok := vr.view.columns[name.Name.L]; ok {
+		return expr, true
+	}
+
+	if _, ok := vr.aliases[name.Name.L]; ok && len(name.Table.L) < 1 {
+		return n, true
+	}
+
+	if vr.view.wildcard {
+		return &ast.ColumnNameExpr{
+			Name: &ast.ColumnName{
+				Table: vr.view.alias,
+				Name:  name.Name,
+			},
+		}, true
+	}
+
+	vr.err = perrors.Errorf("optimize: unknown column '%s' in view '%s'", name.Name.O, vr.view.name)
+	return n, false
+}
+
+// viewDetector finds the first view in the nodes.
+type viewDetector struct {
+	ru   *rule.Rule
+	view string
+}
+
+func (vd *viewDetector) Enter(n ast.Node) (ast.Node, bool) {
+	if tn, ok := n.(*ast.TableName); ok {
+		if _, ok = vd.ru.View(tn.Name.O); ok {
+			vd.view = tn.Name.O
+		}
+	}
+	return n, len(vd.view) > 0
+}
+
+func (vd *viewDetector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, len(vd.view) < 1
+}
+
+// viewChecker checks if the expressions of view can be merged into the outer query.
+type viewChecker struct {
+	reason string
+}
+
+func (vc *viewChecker) Enter(n ast.Node) (ast.Node, bool) {
+	switch n.(type) {
+	case *ast.AggregateFuncExpr:
+		vc.reason = "aggregate function is not supported"
+	case *ast.WindowFuncExpr:
+		vc.reason = "window function is not supported"
+	case *ast.SubqueryExpr:
+		vc.reason = "subquery is not supported"
+	case ast.ParamMarkerExpr:
+		vc.reason = "parameter is not supported"
+	}
+	return n, len(vc.reason) > 0
+}
+
+func (vc *viewChecker) Leave(n ast.Node) (ast.Node, bool) {
+	return n, len(vc.reason) < 1
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package optimize
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/arana-db/parser"
+	"github.com/arana-db/parser/format"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto/rule"
+)
+
+func TestExpandViews(t *testing.T) {
+	var ru rule.Rule
+	ru.SetView("v_student", "select id, uid, upper(name) as student_name from student where gender = 1 or age > 18")
+	ru.SetView("v_all", "select * from student s where s.deleted = 0")
+	ru.SetView("v_nested", "select uid, student_name as sname from v_student where uid > 100")
+
+	type tt struct {
+		sql    string
+		expect string
+	}
+
+	for _, it := range []tt{
+		{
+			"select * from v_student where uid = 1",
+			"SELECT `id`,`uid`,UPPER(`name`) AS `student_name` FROM `student` WHERE (`gender`=1 OR `age`>18) AND `uid`=1",
+		},
+		{
+			"select v.student_name, count(1) from v_student v group by v.student_name order by v.student_name",
+			"SELECT UPPER(`name`) AS `student_name`,COUNT(1) FROM `student` WHERE `gender`=1 OR `age`>18 GROUP BY UPPER(`name`) ORDER BY UPPER(`name`)",
+		},
+		{
+			"select id, name from v_all where uid in (1, 2)",
+			"SELECT `s`.`id`,`s`.`name` FROM `student` AS `s` WHERE `s`.`deleted`=0 AND `s`.`uid` IN (1,2)",
+		},
+		{
+			"select sname from v_nested where uid = 200",
+			"SELECT UPPER(`name`) AS `sname` FROM `student` WHERE (`gender`=1 OR `age`>18) AND `uid`>100 AND `uid`=200",
+		},
+		{
+			"select * from student where uid = 1",
+			"SELECT * FROM `student` WHERE `uid`=1",
+		},
+		{
+			"select * from student where id in (select id from v_student where uid = 1)",
+			"SELECT * FROM `student` WHERE `id` IN (SELECT `id` FROM `student` WHERE (`gender`=1 OR `age`>18) AND `uid`=1)",
+		},
+		{
+			"select t.id from (select * from v_student) t where t.uid = 1",
+			"SELECT `t`.`id` FROM (SELECT `id`,`uid`,UPPER(`name`) AS `student_name` FROM `student` WHERE `gender`=1 OR `age`>18) AS `t` WHERE `t`.`uid`=1",
+		},
+		{
+			"delete from student where id in (select id from v_student)",
+			"DELETE FROM `student` WHERE `id` IN (SELECT `id` FROM `student` WHERE `gender`=1 OR `age`>18)",
+		},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(it.sql, "", "")
+			assert.NoError(t, err)
+			assert.NoError(t, expandViews(&ru, stmt))
+
+			var sb strings.Builder
+			assert.NoError(t, stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)))
+			assert.Equal(t, it.expect, sb.String())
+		})
+	}
+
+	for _, it := range []string{
+		"select * from v_student join student on v_student.id = student.id",
+		"select gender from v_student",
+		"insert into v_student(uid) values(1)",
+		"update v_student set uid = 2 where uid = 1",
+		"delete from v_student where uid = 1",
+	} {
+		t.Run(it, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(it, "", "")
+			assert.NoError(t, err)
+			assert.Error(t, expandViews(&ru, stmt))
+		})
+	}
+}
+
+func TestValidateView(t *testing.T) {
+	var ru rule.Rule
+	assert.NoError(t, ValidateView(&ru, "v1", "select id, uid from student where uid > 1"))
+	assert.Error(t, ValidateView(&ru, "v1", "select uid, count(1) as cnt from student group by uid"))
+	assert.Error(t, ValidateView(&ru, "v1", "select count(1) as cnt from student"))
+	assert.Error(t, ValidateView(&ru, "v1", "select id from student limit 10"))
+	assert.Error(t, ValidateView(&ru, "v1", "select a.id from student a join teacher b on a.tid = b.id"))
+	assert.Error(t, ValidateView(&ru, "v1", "select upper(name) from student"))
+	assert.Error(t, ValidateView(&ru, "v1", "delete from student"))
+}
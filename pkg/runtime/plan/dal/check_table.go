@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dal
+
+import (
+	"context"
+	"fmt"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var _ proto.Plan = (*CheckTablePlan)(nil)
+
+// CheckTablePlan checks whether the physical tables of logical tables have same columns and indexes.
+type CheckTablePlan struct {
+	plan.BasePlan
+	Stmt *ast.CheckTableStatement
+	rule *rule.Rule
+}
+
+// NewCheckTablePlan creates a CheckTablePlan.
+func NewCheckTablePlan(stmt *ast.CheckTableStatement) *CheckTablePlan {
+	return &CheckTablePlan{Stmt: stmt}
+}
+
+func (c *CheckTablePlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (c *CheckTablePlan) SetRule(rule *rule.Rule) {
+	c.rule = rule
+}
+
+func (c *CheckTablePlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "CheckTablePlan.ExecIn")
+	defer span.End()
+
+	var (
+		schema = rcontext.Schema(ctx)
+		fields = thead.CheckTable.ToFields()
+		ds     = &dataset.VirtualDataset{Columns: fields}
+	)
+
+	for _, it := range c.Stmt.Tables {
+		var (
+			table = it.Suffix()
+			name  = fmt.Sprintf("%s.%s", schema, table)
+		)
+
+		if len(it.Prefix()) > 0 && it.Prefix() != schema {
+			return nil, errors.Errorf("cannot check table %s of other database", it.String())
+		}
+
+		if !c.rule.Has(table) {
+			ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+				name, "check", "note", "The table is not a sharding table",
+			}))
+			continue
+		}
+
+		drifts, err := proto.LoadSchemaChecker().CheckTable(ctx, schema, table)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if len(drifts) == 0 {
+			ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+				name, "check", "status", "OK",
+			}))
+			continue
+		}
+
+		for _, drift := range drifts {
+			ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+				name, "check", "error", drift.String(),
+			}))
+		}
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
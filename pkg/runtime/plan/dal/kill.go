@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dal
+
+import (
+	"context"
+	"math"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/constants/mysql"
+	mysqlErrors "github.com/arana-db/arana/pkg/mysql/errors"
+	"github.com/arana-db/arana/pkg/process"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+var _ proto.Plan = (*KillPlan)(nil)
+
+// KillPlan kills the frontend connection or the statement it is executing.
+type KillPlan struct {
+	plan.BasePlan
+	stmt *ast.KillStatement
+}
+
+func NewKillPlan(stmt *ast.KillStatement) *KillPlan {
+	return &KillPlan{stmt: stmt}
+}
+
+func (k *KillPlan) Type() proto.PlanType {
+	return proto.PlanTypeExec
+}
+
+func (k *KillPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "KillPlan.ExecIn")
+	defer span.End()
+
+	if k.stmt.ConnectionID > math.MaxUint32 {
+		return nil, mysqlErrors.NewSQLError(mysql.ERNoSuchThread, mysql.SSUnknownSQLState, "Unknown thread id: %d", k.stmt.ConnectionID)
+	}
+
+	var (
+		tenant = rcontext.Tenant(ctx)
+		user   = rcontext.User(ctx)
+	)
+	if err := process.Kill(ctx, tenant, user, security.IsAdmin(tenant, user), uint32(k.stmt.ConnectionID), k.stmt.Query); err != nil {
+		return nil, err
+	}
+
+	return resultx.New(), nil
+}
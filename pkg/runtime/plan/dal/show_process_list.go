@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dal
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/process"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+	"github.com/arana-db/arana/pkg/security"
+)
+
+// _maxProcessInfoLength is the max length of sql displayed if FULL is not specified.
+const _maxProcessInfoLength = 100
+
+var _ proto.Plan = (*ShowProcessListPlan)(nil)
+
+type ShowProcessListPlan struct {
+	plan.BasePlan
+	stmt *ast.ShowProcessList
+}
+
+func NewShowProcessListPlan(stmt *ast.ShowProcessList) *ShowProcessListPlan {
+	return &ShowProcessListPlan{stmt: stmt}
+}
+
+func (s *ShowProcessListPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (s *ShowProcessListPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "ShowProcessListPlan.ExecIn")
+	defer span.End()
+
+	fields := thead.ProcessList.ToFields()
+	ds := &dataset.VirtualDataset{
+		Columns: fields,
+	}
+
+	var (
+		tenant = rcontext.Tenant(ctx)
+		user   = rcontext.User(ctx)
+		admin  = security.IsAdmin(tenant, user)
+	)
+
+	// only the processes of current tenant are visible, and the processes of other users are visible to admin only
+	for _, it := range process.List(tenant) {
+		if !admin && it.User != user {
+			continue
+		}
+		var db, state, info, txID proto.Value
+		if len(it.Schema) > 0 {
+			db = it.Schema
+		}
+		if len(it.SQL) > 0 {
+			state = "executing"
+			if s.stmt.Full {
+				info = it.SQL
+			} else {
+				info = truncateInfo(it.SQL)
+			}
+		}
+		if it.TxID != 0 {
+			txID = it.TxID
+		}
+
+		ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+			int64(it.ID), it.User, it.Host, db, it.Command, int64(it.Time.Seconds()), state, info, it.Tenant, txID,
+		}))
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
+
+// truncateInfo truncates the sql to the max length at the boundary of rune, so that no character will be split.
+func truncateInfo(sql string) string {
+	if len(sql) <= _maxProcessInfoLength {
+		return sql
+	}
+	n := _maxProcessInfoLength
+	for n > 0 && !utf8.RuneStart(sql[n]) {
+		n--
+	}
+	return sql[:n]
+}
@@ -24,39 +24,51 @@ import (
 
 import (
 	"github.com/pkg/errors"
-
-	uatomic "go.uber.org/atomic"
-
-	"golang.org/x/sync/errgroup"
 )
 
 import (
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/rule"
-	"github.com/arana-db/arana/pkg/resultx"
 	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
 	"github.com/arana-db/arana/pkg/runtime/plan"
-	"github.com/arana-db/arana/pkg/util/log"
 )
 
 var _ proto.Plan = (*AlterTablePlan)(nil)
 
 type AlterTablePlan struct {
 	plan.BasePlan
-	stmt   *ast.AlterTableStatement
-	Shards rule.DatabaseTables
+	stmt           *ast.AlterTableStatement
+	Shards         rule.DatabaseTables
+	renamedTable   string
+	renamedColumns map[string]string
 }
 
 func NewAlterTablePlan(stmt *ast.AlterTableStatement) *AlterTablePlan {
 	return &AlterTablePlan{stmt: stmt}
 }
 
+// SetRenamedTable sets the new name of sharding table, all physical tables will be renamed.
+func (at *AlterTablePlan) SetRenamedTable(table string) {
+	at.renamedTable = table
+}
+
+// AddRenamedColumn adds a renamed shard column.
+func (at *AlterTablePlan) AddRenamedColumn(column, newColumn string) {
+	if at.renamedColumns == nil {
+		at.renamedColumns = make(map[string]string)
+	}
+	at.renamedColumns[column] = newColumn
+}
+
 func (at *AlterTablePlan) Type() proto.PlanType {
 	return proto.PlanTypeExec
 }
 
 func (at *AlterTablePlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
-	// TODO: ADD trace in all plan ExecIn
+	ctx, span := plan.Tracer.Start(ctx, "AlterTablePlan.ExecIn")
+	defer span.End()
+
 	if at.Shards == nil {
 		// non-sharding alter table
 		var sb strings.Builder
@@ -65,60 +77,112 @@ func (at *AlterTablePlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.R
 		}
 		return conn.Exec(ctx, "", sb.String(), at.Args...)
 	}
+
+	// sharding alter table, the status of each physical table will be tracked by a ddl job
+	query, err := ast.RestoreToString(ast.RestoreDefault, at.stmt)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	job, err := DefaultJobManager().Submit(rcontext.Schema(ctx), query, at)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return execJob(ctx, conn, job)
+}
+
+// physicalStatement returns the statement which alters the given physical table.
+func (at *AlterTablePlan) physicalStatement(table string) *ast.AlterTableStatement {
+	stmt := at.stmt.ResetTable(table)
+	if len(at.renamedTable) > 0 {
+		stmt = stmt.ResetNewTable(rule.RenameTable(table, at.stmt.Table.Suffix(), at.renamedTable))
+	}
+	return stmt
+}
+
+func (at *AlterTablePlan) execPhysical(ctx context.Context, conn proto.VConn, db string, stmt *ast.AlterTableStatement) (string, uint64, error) {
+	var (
+		sb   strings.Builder
+		args []int
+	)
+	if err := stmt.Restore(ast.RestoreDefault, &sb, &args); err != nil {
+		return "", 0, errors.WithStack(err)
+	}
+
+	query := sb.String()
+	res, err := conn.Exec(ctx, db, query, at.ToArgs(args)...)
+	if err != nil {
+		return query, 0, errors.WithStack(err)
+	}
+
+	n, _ := res.RowsAffected()
+	return query, n, nil
+}
+
+// appliedRule tracks the rule changes which have been applied, so that a partial update can be resumed or restored.
+type appliedRule struct {
+	columns map[string]struct{} // the renamed shard columns, key is the old column name
+	table   bool                // true if the table is renamed
+}
+
+func (ar *appliedRule) isEmpty() bool {
+	return !ar.table && len(ar.columns) == 0
+}
+
+// updateRule updates the rule of sharding table if the table or shard columns are renamed,
+// the steps which have been applied already will be skipped.
+func (at *AlterTablePlan) updateRule(ctx context.Context, applied *appliedRule) error {
 	var (
-		affects = uatomic.NewUint64(0)
-		cnt     = uatomic.NewUint32(0)
+		schema = rcontext.Schema(ctx)
+		table  = at.stmt.Table.Suffix()
+		rm     = proto.LoadRuleManager()
 	)
 
-	var g errgroup.Group
-
-	// sharding alter table
-	for k, v := range at.Shards {
-		// do copy for goroutine-safe
-		var (
-			db     = k
-			tables = v
-		)
-		// execute concurrent for each phy database
-		g.Go(func() error {
-			var (
-				sb   strings.Builder
-				args []int
-				res  proto.Result
-				err  error
-			)
-
-			sb.Grow(256)
-
-			for _, table := range tables {
-				if err = at.stmt.ResetTable(table).Restore(ast.RestoreDefault, &sb, &args); err != nil {
-					return errors.WithStack(err)
-				}
-
-				if res, err = conn.Exec(ctx, db, sb.String(), at.ToArgs(args)...); err != nil {
-					return errors.WithStack(err)
-				}
-
-				n, _ := res.RowsAffected()
-				affects.Add(n)
-				cnt.Inc()
-
-				// cleanup
-				if len(args) > 0 {
-					args = args[:0]
-				}
-				sb.Reset()
-			}
-
-			return nil
-		})
+	for column, newColumn := range at.renamedColumns {
+		if _, ok := applied.columns[column]; ok {
+			continue
+		}
+		if err := rm.RenameColumn(ctx, schema, table, column, newColumn); err != nil {
+			return errors.Wrapf(err, "failed to rename shard column %s of table %s", column, table)
+		}
+		if applied.columns == nil {
+			applied.columns = make(map[string]struct{})
+		}
+		applied.columns[column] = struct{}{}
+	}
+
+	if len(at.renamedTable) > 0 && !applied.table {
+		if err := rm.RenameTable(ctx, schema, table, at.renamedTable); err != nil {
+			return errors.Wrapf(err, "failed to rename table %s", table)
+		}
+		applied.table = true
 	}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	return nil
+}
+
+// restoreRule restores the rule changes which are applied by updateRule.
+func (at *AlterTablePlan) restoreRule(ctx context.Context, schema string, applied *appliedRule) error {
+	var (
+		table = at.stmt.Table.Suffix()
+		rm    = proto.LoadRuleManager()
+	)
+
+	if applied.table {
+		if err := rm.RenameTable(ctx, schema, at.renamedTable, table); err != nil {
+			return errors.Wrapf(err, "failed to rename table %s", at.renamedTable)
+		}
+		applied.table = false
 	}
 
-	log.Debugf("sharding alter table success: batch=%d, affects=%d", cnt.Load(), affects.Load())
+	for column := range applied.columns {
+		newColumn := at.renamedColumns[column]
+		if err := rm.RenameColumn(ctx, schema, table, newColumn, column); err != nil {
+			return errors.Wrapf(err, "failed to rename shard column %s of table %s", newColumn, table)
+		}
+		delete(applied.columns, column)
+	}
 
-	return resultx.New(resultx.WithRowsAffected(affects.Load())), nil
+	return nil
 }
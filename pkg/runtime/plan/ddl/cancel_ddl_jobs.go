@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var _ proto.Plan = (*CancelDDLJobsPlan)(nil)
+
+// CancelDDLJobsPlan rollbacks the sharding DDL jobs with reverse statements.
+type CancelDDLJobsPlan struct {
+	plan.BasePlan
+	Stmt *ast.CancelDDLJobsStatement
+}
+
+// NewCancelDDLJobsPlan creates a CancelDDLJobsPlan.
+func NewCancelDDLJobsPlan(stmt *ast.CancelDDLJobsStatement) *CancelDDLJobsPlan {
+	return &CancelDDLJobsPlan{Stmt: stmt}
+}
+
+func (c *CancelDDLJobsPlan) Type() proto.PlanType {
+	return proto.PlanTypeExec
+}
+
+func (c *CancelDDLJobsPlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "CancelDDLJobsPlan.ExecIn")
+	defer span.End()
+
+	var affects uint64
+	for _, id := range c.Stmt.JobIDs {
+		job, ok := DefaultJobManager().Get(id)
+		if !ok || job.Schema != rcontext.Schema(ctx) {
+			return nil, errors.Errorf("no such ddl job %d", id)
+		}
+		n, err := job.Cancel(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		affects += n
+	}
+
+	return resultx.New(resultx.WithRowsAffected(affects)), nil
+}
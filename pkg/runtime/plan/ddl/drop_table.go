@@ -19,7 +19,7 @@ package ddl
 
 import (
 	"context"
-	"strings"
+	"sort"
 )
 
 import (
@@ -53,41 +53,54 @@ func (d *DropTablePlan) Type() proto.PlanType {
 func (d *DropTablePlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
 	ctx, span := plan.Tracer.Start(ctx, "DropTablePlan.ExecIn")
 	defer span.End()
-	var (
-		sb   strings.Builder
-		args []int
-	)
 
+	statements, err := d.PhysicalStatements()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, it := range statements {
+		if err = d.execOne(ctx, conn, it.Group, it.Query); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return resultx.New(), nil
+}
+
+// PhysicalStatements returns the DROP TABLE statements, one statement for each physical database.
+func (d *DropTablePlan) PhysicalStatements() ([]*PhysicalStatement, error) {
+	var ret []*PhysicalStatement
 	for _, shards := range d.shardsMap {
-		stmt := new(ast.DropTableStatement)
-		for db, tables := range shards {
-			for _, table := range tables {
+		dbs := make([]string, 0, len(shards))
+		for db := range shards {
+			dbs = append(dbs, db)
+		}
+		sort.Strings(dbs)
+
+		for _, db := range dbs {
+			stmt := new(ast.DropTableStatement)
+			for _, table := range shards[db] {
 				stmt.Tables = append(stmt.Tables, &ast.TableName{
 					table,
 				})
 			}
-			err := stmt.Restore(ast.RestoreDefault, &sb, &args)
+			query, err := ast.RestoreToString(ast.RestoreDefault, stmt)
 			if err != nil {
 				return nil, err
 			}
-
-			if err = d.execOne(ctx, conn, db, sb.String(), d.ToArgs(args)); err != nil {
-				return nil, errors.WithStack(err)
-			}
-
-			sb.Reset()
+			ret = append(ret, &PhysicalStatement{Group: db, Query: query})
 		}
 	}
-
-	return resultx.New(), nil
+	return ret, nil
 }
 
 func (d *DropTablePlan) SetShards(shardsMap []rule.DatabaseTables) {
 	d.shardsMap = shardsMap
 }
 
-func (d *DropTablePlan) execOne(ctx context.Context, conn proto.VConn, db, query string, args []interface{}) error {
-	res, err := conn.Exec(ctx, db, query, args...)
+func (d *DropTablePlan) execOne(ctx context.Context, conn proto.VConn, db, query string) error {
+	res, err := conn.Exec(ctx, db, query)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var _ proto.Plan = (*DryRunPlan)(nil)
+
+// PhysicalStatement represents a statement which will be executed in a physical database.
+type PhysicalStatement struct {
+	Group string // the group of physical database, empty means the default group
+	Query string
+}
+
+// DryRunPlan returns the physical statements which would be executed, nothing will be executed.
+type DryRunPlan struct {
+	plan.BasePlan
+	Statements []*PhysicalStatement
+}
+
+// NewDryRunPlan creates a DryRunPlan.
+func NewDryRunPlan(statements []*PhysicalStatement) *DryRunPlan {
+	return &DryRunPlan{Statements: statements}
+}
+
+func (d *DryRunPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (d *DryRunPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	_, span := plan.Tracer.Start(ctx, "DryRunPlan.ExecIn")
+	defer span.End()
+
+	var (
+		fields = thead.DryRun.ToFields()
+		ds     = &dataset.VirtualDataset{Columns: fields}
+	)
+
+	for _, it := range d.Statements {
+		ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+			it.Group,
+			it.Query,
+		}))
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
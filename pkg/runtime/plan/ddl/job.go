@@ -0,0 +1,473 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+const (
+	// the max number of finished jobs which will be kept
+	_maxFinishedJobs = 256
+	// the max number of failed jobs which will be kept, the oldest ones will be evicted, and they cannot be resumed or cancelled any more
+	_maxFailedJobs = 64
+)
+
+var _defaultJobManager JobManager
+
+// DefaultJobManager returns the default DDL job manager.
+func DefaultJobManager() *JobManager {
+	return &_defaultJobManager
+}
+
+// JobState represents the state of a sharding DDL job.
+type JobState uint8
+
+const (
+	_             JobState = iota
+	JobRunning             // the physical tables are being altered
+	JobDone                // all physical tables are altered
+	JobFailed              // some physical tables are failed, it can be resumed by executing the same statement again
+	JobCancelling          // the altered physical tables are being rolled back
+	JobCancelled           // all altered physical tables are rolled back
+)
+
+var _jobStateNames = [...]string{
+	JobRunning:    "running",
+	JobDone:       "done",
+	JobFailed:     "failed",
+	JobCancelling: "cancelling",
+	JobCancelled:  "cancelled",
+}
+
+func (s JobState) String() string {
+	return _jobStateNames[s]
+}
+
+// ShardState represents the state of DDL on a physical table.
+type ShardState uint8
+
+const (
+	_ ShardState = iota
+	ShardPending
+	ShardDone
+	ShardFailed
+	ShardCancelled
+)
+
+var _shardStateNames = [...]string{
+	ShardPending:   "pending",
+	ShardDone:      "done",
+	ShardFailed:    "failed",
+	ShardCancelled: "cancelled",
+}
+
+func (s ShardState) String() string {
+	return _shardStateNames[s]
+}
+
+// JobShard represents the status of DDL on a physical table.
+type JobShard struct {
+	DB    string
+	Table string
+	State ShardState
+	Query string // the last executed physical statement
+	Error string
+}
+
+// Job represents a sharding DDL job, which tracks the status of each physical table.
+type Job struct {
+	ID        int64
+	Schema    string
+	Table     string
+	Query     string
+	CreatedAt time.Time
+
+	mu        sync.RWMutex
+	state     JobState
+	updatedAt time.Time
+	shards    []*JobShard
+	plan      *AlterTablePlan
+	rule      appliedRule // only accessed by the running or cancelling job
+}
+
+// State returns the state of job.
+func (j *Job) State() JobState {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.state
+}
+
+// UpdatedAt returns the last time when the job changed.
+func (j *Job) UpdatedAt() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.updatedAt
+}
+
+// Shards returns a snapshot of the physical tables.
+func (j *Job) Shards() []JobShard {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	ret := make([]JobShard, 0, len(j.shards))
+	for _, it := range j.shards {
+		ret = append(ret, *it)
+	}
+	return ret
+}
+
+// Progress returns the count of altered physical tables and the total count.
+func (j *Job) Progress() (done, total int) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	for _, it := range j.shards {
+		if it.State == ShardDone {
+			done++
+		}
+	}
+	return done, len(j.shards)
+}
+
+// Cancel rollbacks the job, the altered physical tables will be compensated with reverse statements,
+// the rule will be restored too if it has been updated.
+func (j *Job) Cancel(ctx context.Context, conn proto.VConn) (uint64, error) {
+	j.mu.Lock()
+	if j.state != JobDone && j.state != JobFailed {
+		j.mu.Unlock()
+		return 0, errors.Errorf("cannot cancel ddl job %d: job is %s", j.ID, j.state)
+	}
+	if _, err := j.plan.stmt.Reverse(); err != nil {
+		j.mu.Unlock()
+		return 0, errors.Wrapf(err, "cannot cancel ddl job %d", j.ID)
+	}
+	done := make(rule.DatabaseTables)
+	for _, it := range j.shards {
+		if it.State == ShardDone {
+			done[it.DB] = append(done[it.DB], it.Table)
+		}
+	}
+	j.state = JobCancelling
+	j.updatedAt = time.Now()
+	j.mu.Unlock()
+
+	var g errgroup.Group
+	for k, v := range done {
+		var (
+			db     = k
+			tables = v
+		)
+		g.Go(func() error {
+			for _, table := range tables {
+				stmt, _ := j.plan.physicalStatement(table).Reverse()
+				query, _, err := j.plan.execPhysical(ctx, conn, db, stmt)
+				j.setShard(db, table, ShardCancelled, query, err)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if err == nil {
+		err = j.plan.restoreRule(ctx, j.Schema, &j.rule)
+	}
+
+	if err != nil {
+		j.setState(JobFailed)
+		return 0, errors.Wrapf(err, "failed to cancel ddl job %d", j.ID)
+	}
+
+	j.setState(JobCancelled)
+
+	var n uint64
+	for _, it := range done {
+		n += uint64(len(it))
+	}
+
+	log.Infof("cancel ddl job %d successfully: tables=%d", j.ID, n)
+
+	return n, nil
+}
+
+// pending returns the physical tables which are not altered.
+func (j *Job) pending() rule.DatabaseTables {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	ret := make(rule.DatabaseTables)
+	for _, it := range j.shards {
+		if it.State != ShardDone {
+			ret[it.DB] = append(ret[it.DB], it.Table)
+		}
+	}
+	return ret
+}
+
+func (j *Job) setShard(db, table string, state ShardState, query string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, it := range j.shards {
+		if it.DB != db || it.Table != table {
+			continue
+		}
+		it.Query = query
+		if err != nil {
+			it.Error = err.Error()
+			if state == ShardDone {
+				it.State = ShardFailed
+			}
+		} else {
+			it.Error = ""
+			it.State = state
+		}
+		break
+	}
+	j.updatedAt = time.Now()
+}
+
+func (j *Job) setState(state JobState) {
+	j.mu.Lock()
+	j.state = state
+	j.updatedAt = time.Now()
+	j.mu.Unlock()
+}
+
+// JobManager manages the sharding DDL jobs.
+// NOTICE: the jobs are kept in the memory of current proxy instance, they are invisible to other instances and will be lost after restarting.
+type JobManager struct {
+	mu   sync.RWMutex
+	seq  int64
+	jobs []*Job // ordered by id
+}
+
+// Submit creates a job for the sharding ALTER TABLE, the failed job which has the same statement will be resumed.
+func (jm *JobManager) Submit(schema, query string, at *AlterTablePlan) (*Job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	table := at.stmt.Table.Suffix()
+
+	for _, it := range jm.jobs {
+		if it.Schema != schema || it.Table != table {
+			continue
+		}
+		switch state := it.State(); state {
+		case JobRunning, JobCancelling:
+			return nil, errors.Errorf("ddl job %d of table %s is %s", it.ID, table, state)
+		case JobFailed:
+			if it.Query != query {
+				continue
+			}
+			it.mu.Lock()
+			it.plan = at
+			it.shards = mergeJobShards(it.shards, at.Shards)
+			it.state = JobRunning
+			it.updatedAt = time.Now()
+			it.mu.Unlock()
+			log.Infof("resume ddl job %d: %s", it.ID, query)
+			return it, nil
+		}
+	}
+
+	jm.seq++
+	now := time.Now()
+	job := &Job{
+		ID:        jm.seq,
+		Schema:    schema,
+		Table:     table,
+		Query:     query,
+		CreatedAt: now,
+		state:     JobRunning,
+		updatedAt: now,
+		plan:      at,
+		shards:    mergeJobShards(nil, at.Shards),
+	}
+
+	jm.jobs = append(jm.jobs, job)
+	jm.evict()
+
+	return job, nil
+}
+
+// Get returns the job with given id.
+func (jm *JobManager) Get(id int64) (*Job, bool) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	for _, it := range jm.jobs {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	return nil, false
+}
+
+// List returns the jobs of given schema, the latest job comes first.
+func (jm *JobManager) List(schema string) []*Job {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	var ret []*Job
+	for i := len(jm.jobs) - 1; i >= 0; i-- {
+		if jm.jobs[i].Schema == schema {
+			ret = append(ret, jm.jobs[i])
+		}
+	}
+	return ret
+}
+
+// evict removes the oldest finished jobs and failed jobs.
+func (jm *JobManager) evict() {
+	var finished, failed int
+	for _, it := range jm.jobs {
+		switch it.State() {
+		case JobDone, JobCancelled:
+			finished++
+		case JobFailed:
+			failed++
+		}
+	}
+
+	if finished <= _maxFinishedJobs && failed <= _maxFailedJobs {
+		return
+	}
+
+	jobs := make([]*Job, 0, len(jm.jobs))
+	for _, it := range jm.jobs {
+		switch it.State() {
+		case JobDone, JobCancelled:
+			if finished > _maxFinishedJobs {
+				finished--
+				continue
+			}
+		case JobFailed:
+			if failed > _maxFailedJobs {
+				failed--
+				log.Warnf("evict failed ddl job %d, it cannot be resumed or cancelled any more: %s", it.ID, it.Query)
+				continue
+			}
+		}
+		jobs = append(jobs, it)
+	}
+	jm.jobs = jobs
+}
+
+// mergeJobShards returns the physical tables of job, the state of existing physical tables will be kept,
+// the altered physical tables are always kept so that they can be compensated.
+func mergeJobShards(exists []*JobShard, shards rule.DatabaseTables) []*JobShard {
+	type key struct {
+		db, table string
+	}
+
+	var (
+		ret  []*JobShard
+		seen = make(map[key]struct{})
+	)
+
+	for _, it := range exists {
+		if it.State == ShardDone {
+			ret = append(ret, it)
+			seen[key{it.DB, it.Table}] = struct{}{}
+		}
+	}
+
+	dbs := make([]string, 0, len(shards))
+	for db := range shards {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+
+	for _, db := range dbs {
+		for _, table := range shards[db] {
+			if _, ok := seen[key{db, table}]; ok {
+				continue
+			}
+			ret = append(ret, &JobShard{
+				DB:    db,
+				Table: table,
+				State: ShardPending,
+			})
+		}
+	}
+
+	return ret
+}
+
+// execJob alters the pending physical tables of job.
+func execJob(ctx context.Context, conn proto.VConn, job *Job) (proto.Result, error) {
+	var (
+		g       errgroup.Group
+		mu      sync.Mutex
+		affects uint64
+		cnt     int
+	)
+
+	for k, v := range job.pending() {
+		// do copy for goroutine-safe
+		var (
+			db     = k
+			tables = v
+		)
+		// execute concurrent for each phy database
+		g.Go(func() error {
+			for _, table := range tables {
+				query, n, err := job.plan.execPhysical(ctx, conn, db, job.plan.physicalStatement(table))
+				job.setShard(db, table, ShardDone, query, err)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				affects += n
+				cnt++
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		job.setState(JobFailed)
+		return nil, errors.Wrapf(err, "ddl job %d failed, execute the same statement to resume it, or 'ADMIN CANCEL DDL JOBS %d' to rollback", job.ID, job.ID)
+	}
+
+	log.Debugf("sharding alter table success: job=%d, batch=%d, affects=%d", job.ID, cnt, affects)
+
+	if err := job.plan.updateRule(ctx, &job.rule); err != nil {
+		job.setState(JobFailed)
+		return nil, errors.Wrapf(err, "ddl job %d failed", job.ID)
+	}
+
+	job.setState(JobDone)
+
+	return resultx.New(resultx.WithRowsAffected(affects)), nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto/rule"
+)
+
+func TestMergeJobShards(t *testing.T) {
+	shards := mergeJobShards(nil, rule.DatabaseTables{
+		"db_0001": {"student_0001"},
+		"db_0000": {"student_0000"},
+	})
+	assert.Len(t, shards, 2)
+	assert.Equal(t, "db_0000", shards[0].DB)
+	assert.Equal(t, ShardPending, shards[0].State)
+
+	shards[0].State = ShardDone
+	shards[1].State = ShardFailed
+
+	// the topology is changed when resuming
+	shards = mergeJobShards(shards, rule.DatabaseTables{
+		"db_0001": {"student_0001"},
+		"db_0002": {"student_0002"},
+	})
+	assert.Len(t, shards, 3)
+	assert.Equal(t, "student_0000", shards[0].Table)
+	assert.Equal(t, ShardDone, shards[0].State)
+	assert.Equal(t, "student_0001", shards[1].Table)
+	assert.Equal(t, ShardPending, shards[1].State)
+	assert.Equal(t, "student_0002", shards[2].Table)
+	assert.Equal(t, ShardPending, shards[2].State)
+}
+
+func TestJobManager_Evict(t *testing.T) {
+	var jm JobManager
+	for i := 0; i < _maxFailedJobs+10; i++ {
+		jm.jobs = append(jm.jobs, &Job{ID: int64(i), state: JobFailed})
+	}
+	jm.jobs = append(jm.jobs, &Job{ID: -1, state: JobRunning})
+
+	jm.evict()
+
+	assert.Len(t, jm.jobs, _maxFailedJobs+1)
+	assert.Equal(t, int64(10), jm.jobs[0].ID)
+	assert.Equal(t, int64(-1), jm.jobs[len(jm.jobs)-1].ID)
+}
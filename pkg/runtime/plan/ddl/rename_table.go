@@ -0,0 +1,180 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+	"sort"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+var _ proto.Plan = (*RenameTablePlan)(nil)
+
+type renameTable struct {
+	table, newTable string
+	shards          rule.DatabaseTables
+}
+
+func (rt renameTable) databases() []string {
+	dbs := make([]string, 0, len(rt.shards))
+	for db := range rt.shards {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+	return dbs
+}
+
+// RenameTablePlan renames the sharding tables, all physical tables will be renamed,
+// then the rule of logical table will be updated.
+type RenameTablePlan struct {
+	plan.BasePlan
+	tables []renameTable
+}
+
+// NewRenameTablePlan creates a rename table plan.
+func NewRenameTablePlan() *RenameTablePlan {
+	return &RenameTablePlan{}
+}
+
+// AddTable adds a sharding table which will be renamed.
+func (rp *RenameTablePlan) AddTable(table, newTable string, shards rule.DatabaseTables) {
+	rp.tables = append(rp.tables, renameTable{
+		table:    table,
+		newTable: newTable,
+		shards:   shards,
+	})
+}
+
+func (rp *RenameTablePlan) Type() proto.PlanType {
+	return proto.PlanTypeExec
+}
+
+func (rp *RenameTablePlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "RenameTablePlan.ExecIn")
+	defer span.End()
+
+	for i, it := range rp.tables {
+		if err := rp.renameOne(ctx, conn, it); err != nil {
+			// compensate the tables which have been renamed already
+			for j := i - 1; j >= 0; j-- {
+				rp.revertOne(ctx, conn, rp.tables[j])
+			}
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return resultx.New(), nil
+}
+
+func (rp *RenameTablePlan) renameOne(ctx context.Context, conn proto.VConn, rt renameTable) error {
+	dbs := rt.databases()
+
+	var done []string
+	undo := func() {
+		rp.undoPhysical(ctx, conn, rt, done)
+	}
+
+	for _, db := range dbs {
+		stmt := newRenamePhysicalTableStatement(rt.shards[db], rt.table, rt.newTable)
+		if err := rp.execOne(ctx, conn, db, stmt); err != nil {
+			undo()
+			return errors.Wrapf(err, "failed to rename table %s to %s", rt.table, rt.newTable)
+		}
+		done = append(done, db)
+	}
+
+	if err := proto.LoadRuleManager().RenameTable(ctx, rcontext.Schema(ctx), rt.table, rt.newTable); err != nil {
+		undo()
+		return errors.Wrapf(err, "failed to update rule of table %s", rt.table)
+	}
+
+	log.Infof("rename sharding table %s to %s successfully: databases=%d", rt.table, rt.newTable, len(dbs))
+
+	return nil
+}
+
+// revertOne reverts a sharding table which has been renamed completely, both the rule and the physical tables.
+func (rp *RenameTablePlan) revertOne(ctx context.Context, conn proto.VConn, rt renameTable) {
+	if err := proto.LoadRuleManager().RenameTable(ctx, rcontext.Schema(ctx), rt.newTable, rt.table); err != nil {
+		log.Errorf("failed to undo renaming rule of table %s to %s: %v", rt.table, rt.newTable, err)
+	}
+	rp.undoPhysical(ctx, conn, rt, rt.databases())
+}
+
+// undoPhysical renames the physical tables in given databases back.
+func (rp *RenameTablePlan) undoPhysical(ctx context.Context, conn proto.VConn, rt renameTable, dbs []string) {
+	for _, db := range dbs {
+		stmt := newRenamePhysicalTableStatement(rt.shards[db], rt.table, rt.newTable)
+		for _, it := range stmt.TableToTables {
+			it.OldTable, it.NewTable = it.NewTable, it.OldTable
+		}
+		if err := rp.execOne(ctx, conn, db, stmt); err != nil {
+			log.Errorf("failed to undo renaming table %s to %s in %s: %v", rt.table, rt.newTable, db, err)
+		}
+	}
+}
+
+func (rp *RenameTablePlan) execOne(ctx context.Context, conn proto.VConn, db string, stmt *ast.RenameTableStatement) error {
+	if len(stmt.TableToTables) == 0 {
+		return nil
+	}
+
+	query, err := ast.RestoreToString(ast.RestoreDefault, stmt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	res, err := conn.Exec(ctx, db, query)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer resultx.Drain(res)
+
+	return nil
+}
+
+// newRenamePhysicalTableStatement creates a statement which renames all physical tables in one database,
+// the tables whose name doesn't contain the logical table name will be ignored.
+func newRenamePhysicalTableStatement(tables []string, table, newTable string) *ast.RenameTableStatement {
+	stmt := ast.NewRenameTableStatement()
+	for _, it := range tables {
+		renamed := rule.RenameTable(it, table, newTable)
+		if renamed == it {
+			continue
+		}
+		stmt.TableToTables = append(stmt.TableToTables, &ast.TableToTable{
+			OldTable: ast.TableName{it},
+			NewTable: ast.TableName{renamed},
+		})
+	}
+	return stmt
+}
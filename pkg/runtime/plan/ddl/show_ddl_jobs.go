@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+	"fmt"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var (
+	_ proto.Plan = (*ShowDDLJobsPlan)(nil)
+	_ proto.Plan = (*ShowDDLJobQueriesPlan)(nil)
+)
+
+// ShowDDLJobsPlan shows the sharding DDL jobs of current schema.
+type ShowDDLJobsPlan struct {
+	plan.BasePlan
+	Stmt *ast.ShowDDLJobsStatement
+}
+
+// NewShowDDLJobsPlan creates a ShowDDLJobsPlan.
+func NewShowDDLJobsPlan(stmt *ast.ShowDDLJobsStatement) *ShowDDLJobsPlan {
+	return &ShowDDLJobsPlan{Stmt: stmt}
+}
+
+func (s *ShowDDLJobsPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (s *ShowDDLJobsPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "ShowDDLJobsPlan.ExecIn")
+	defer span.End()
+
+	var (
+		fields = thead.DDLJobs.ToFields()
+		ds     = &dataset.VirtualDataset{Columns: fields}
+		jobs   = DefaultJobManager().List(rcontext.Schema(ctx))
+	)
+
+	if s.Stmt.Limit > 0 && int64(len(jobs)) > s.Stmt.Limit {
+		jobs = jobs[:s.Stmt.Limit]
+	}
+
+	for _, it := range jobs {
+		done, total := it.Progress()
+		ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+			it.ID,
+			it.Schema,
+			it.Table,
+			it.State().String(),
+			fmt.Sprintf("%d/%d", done, total),
+			it.Query,
+			it.CreatedAt,
+			it.UpdatedAt(),
+		}))
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
+
+// ShowDDLJobQueriesPlan shows the physical statements of sharding DDL jobs.
+type ShowDDLJobQueriesPlan struct {
+	plan.BasePlan
+	Stmt *ast.ShowDDLJobQueriesStatement
+}
+
+// NewShowDDLJobQueriesPlan creates a ShowDDLJobQueriesPlan.
+func NewShowDDLJobQueriesPlan(stmt *ast.ShowDDLJobQueriesStatement) *ShowDDLJobQueriesPlan {
+	return &ShowDDLJobQueriesPlan{Stmt: stmt}
+}
+
+func (s *ShowDDLJobQueriesPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (s *ShowDDLJobQueriesPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "ShowDDLJobQueriesPlan.ExecIn")
+	defer span.End()
+
+	var (
+		fields = thead.DDLJobQueries.ToFields()
+		ds     = &dataset.VirtualDataset{Columns: fields}
+	)
+
+	for _, id := range s.Stmt.JobIDs {
+		job, ok := DefaultJobManager().Get(id)
+		if !ok || job.Schema != rcontext.Schema(ctx) {
+			return nil, errors.Errorf("no such ddl job %d", id)
+		}
+		for _, it := range job.Shards() {
+			ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(fields, []proto.Value{
+				job.ID,
+				it.DB,
+				it.Table,
+				it.State.String(),
+				it.Query,
+				it.Error,
+			}))
+		}
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
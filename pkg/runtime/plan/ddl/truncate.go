@@ -19,7 +19,7 @@ package ddl
 
 import (
 	"context"
-	"strings"
+	"sort"
 )
 
 import (
@@ -54,42 +54,57 @@ func (s *TruncatePlan) Type() proto.PlanType {
 func (s *TruncatePlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
 	ctx, span := plan.Tracer.Start(ctx, "TruncatePlan.ExecIn")
 	defer span.End()
+
+	statements, err := s.PhysicalStatements()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute TRUNCATE statement")
+	}
+
+	for _, it := range statements {
+		if err = s.execOne(ctx, conn, it.Group, it.Query); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return resultx.New(), nil
+}
+
+// PhysicalStatements returns the TRUNCATE statements of all physical tables.
+func (s *TruncatePlan) PhysicalStatements() ([]*PhysicalStatement, error) {
 	if s.shards == nil || s.shards.IsEmpty() {
-		return resultx.New(), nil
+		return nil, nil
+	}
+
+	dbs := make([]string, 0, len(s.shards))
+	for db := range s.shards {
+		dbs = append(dbs, db)
 	}
+	sort.Strings(dbs)
 
 	var (
-		sb   strings.Builder
-		args []int
+		ret  []*PhysicalStatement
 		stmt = new(ast.TruncateStatement)
 	)
-
-	// prepare
-	sb.Grow(256)
-	for db, tables := range s.shards {
-		for _, table := range tables {
+	for _, db := range dbs {
+		for _, table := range s.shards[db] {
 			stmt.Table = s.stmt.Table.ResetSuffix(table)
-			if err := stmt.Restore(ast.RestoreDefault, &sb, &args); err != nil {
-				return nil, errors.Wrap(err, "failed to execute TRUNCATE statement")
-			}
-
-			if err := s.execOne(ctx, conn, db, sb.String(), s.ToArgs(args)); err != nil {
+			query, err := ast.RestoreToString(ast.RestoreDefault, stmt)
+			if err != nil {
 				return nil, errors.WithStack(err)
 			}
-
-			sb.Reset()
+			ret = append(ret, &PhysicalStatement{Group: db, Query: query})
 		}
 	}
 
-	return resultx.New(), nil
+	return ret, nil
 }
 
 func (s *TruncatePlan) SetShards(shards rule.DatabaseTables) {
 	s.shards = shards
 }
 
-func (s *TruncatePlan) execOne(ctx context.Context, conn proto.VConn, db, query string, args []interface{}) error {
-	res, err := conn.Exec(ctx, db, query, args...)
+func (s *TruncatePlan) execOne(ctx context.Context, conn proto.VConn, db, query string) error {
+	res, err := conn.Exec(ctx, db, query)
 	if err != nil {
 		return errors.WithStack(err)
 	}
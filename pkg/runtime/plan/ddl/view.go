@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ddl
+
+import (
+	"context"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var (
+	_ proto.Plan = (*CreateViewPlan)(nil)
+	_ proto.Plan = (*DropViewPlan)(nil)
+)
+
+// CreateViewPlan saves the definition of logical view into config center, no physical view will be created.
+type CreateViewPlan struct {
+	plan.BasePlan
+	Stmt *ast.CreateViewStatement
+}
+
+// NewCreateViewPlan creates a CreateViewPlan.
+func NewCreateViewPlan(stmt *ast.CreateViewStatement) *CreateViewPlan {
+	return &CreateViewPlan{Stmt: stmt}
+}
+
+func (c *CreateViewPlan) Type() proto.PlanType {
+	return proto.PlanTypeExec
+}
+
+func (c *CreateViewPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "CreateViewPlan.ExecIn")
+	defer span.End()
+
+	if err := proto.LoadRuleManager().CreateView(ctx, rcontext.Schema(ctx), c.Stmt.View.Suffix(), c.Stmt.Definition, c.Stmt.OrReplace); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return resultx.New(), nil
+}
+
+// DropViewPlan removes the definitions of logical views from config center.
+type DropViewPlan struct {
+	plan.BasePlan
+	Stmt *ast.DropViewStatement
+}
+
+// NewDropViewPlan creates a DropViewPlan.
+func NewDropViewPlan(stmt *ast.DropViewStatement) *DropViewPlan {
+	return &DropViewPlan{Stmt: stmt}
+}
+
+func (d *DropViewPlan) Type() proto.PlanType {
+	return proto.PlanTypeExec
+}
+
+func (d *DropViewPlan) ExecIn(ctx context.Context, _ proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "DropViewPlan.ExecIn")
+	defer span.End()
+
+	for _, it := range d.Stmt.Views {
+		if err := proto.LoadRuleManager().DropView(ctx, rcontext.Schema(ctx), it.Suffix(), d.Stmt.IfExists); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return resultx.New(), nil
+}
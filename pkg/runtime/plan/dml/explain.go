@@ -0,0 +1,273 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dml
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/mysql/thead"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+var _ proto.Plan = (*ExplainPlan)(nil)
+
+// ExplainPlan shows the distributed plan tree and the physical statements of each leaf plan.
+// The EXPLAIN outputs of backends will be appended under each physical statement if Verbose is true.
+type ExplainPlan struct {
+	plan.BasePlan
+	Plan    proto.Plan
+	Verbose bool
+}
+
+func (e *ExplainPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (e *ExplainPlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "ExplainPlan.ExecIn")
+	defer span.End()
+
+	ex := &explainer{
+		conn:    conn,
+		verbose: e.Verbose,
+		fields:  thead.Explain.ToFields(),
+	}
+
+	if err := ex.explain(ctx, e.Plan, 0); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ds := &dataset.VirtualDataset{
+		Columns: ex.fields,
+		Rows:    ex.rows,
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
+
+type explainer struct {
+	conn    proto.VConn
+	verbose bool
+	fields  []proto.Field
+	rows    []proto.Row
+}
+
+func (ex *explainer) add(depth int, operator, group, info string) {
+	if depth > 0 {
+		operator = strings.Repeat("  ", depth-1) + "└─" + operator
+	}
+	ex.rows = append(ex.rows, rows.NewTextVirtualRow(ex.fields, []proto.Value{
+		int64(len(ex.rows) + 1),
+		operator,
+		group,
+		info,
+	}))
+}
+
+func (ex *explainer) explain(ctx context.Context, p proto.Plan, depth int) error {
+	name, info, children := describePlan(p)
+	ex.add(depth, name, "", info)
+
+	if children != nil {
+		for _, it := range children {
+			if err := ex.explain(ctx, it, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// execute the leaf plan with a recorder, so the physical statements can be collected without executing.
+	var recorder recordConn
+	res, err := p.ExecIn(ctx, &recorder)
+	if err != nil {
+		return errors.Wrapf(err, "failed to explain %s", name)
+	}
+	resultx.Drain(res)
+
+	for _, it := range recorder.sorted() {
+		query := it.query
+		if len(it.args) > 0 {
+			query = fmt.Sprintf("%s, args=%v", query, it.args)
+		}
+		ex.add(depth+1, it.kind, it.db, query)
+
+		if ex.verbose {
+			if err = ex.explainBackend(ctx, it, depth+2); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// explainBackend appends the EXPLAIN output of backend, each row will be rendered as 'column=value' pairs.
+func (ex *explainer) explainBackend(ctx context.Context, st *recordStatement, depth int) error {
+	res, err := ex.conn.Query(ctx, st.db, "EXPLAIN "+st.query, st.args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to explain '%s' in backend", st.query)
+	}
+
+	ds, err := res.Dataset()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	fields, err := ds.Fields()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for {
+		row, err := ds.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		values := make([]proto.Value, len(fields))
+		if err = row.Scan(values); err != nil {
+			return errors.WithStack(err)
+		}
+
+		var sb strings.Builder
+		for i, it := range values {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			var s sql.NullString
+			_ = s.Scan(it)
+			sb.WriteString(fields[i].Name())
+			sb.WriteByte('=')
+			if s.Valid {
+				sb.WriteString(s.String)
+			} else {
+				sb.WriteString("NULL")
+			}
+		}
+		ex.add(depth, "Backend", st.db, sb.String())
+	}
+
+	return nil
+}
+
+// describePlan returns the name, info and children of plan, the children is nil if the plan is a leaf.
+func describePlan(p proto.Plan) (string, string, []proto.Plan) {
+	switch t := p.(type) {
+	case *CompositePlan:
+		return "Composite", "", t.Plans
+	case CompositePlan:
+		return "Composite", "", t.Plans
+	case *LimitPlan:
+		return "Limit", fmt.Sprintf("offset=%d, limit=%d", t.OriginOffset, t.OverwriteLimit), []proto.Plan{t.ParentPlan}
+	case *OrderPlan:
+		return "Order", "order by " + describeOrderByItems(t.OrderByItems), []proto.Plan{t.ParentPlan}
+	case *GroupPlan:
+		return "Group", "group by " + describeOrderByItems(t.GroupItems), []proto.Plan{t.Plan}
+	case *AggregatePlan:
+		return "Aggregate", "", []proto.Plan{t.Plan}
+	case *DropWeakPlan:
+		return "DropWeak", fmt.Sprintf("weak fields=%d", len(t.WeakList)), []proto.Plan{t.Plan}
+	default:
+		name := fmt.Sprintf("%T", p)
+		if i := strings.LastIndexByte(name, '.'); i != -1 {
+			name = name[i+1:]
+		}
+		return strings.TrimSuffix(name, "Plan"), "", nil
+	}
+}
+
+func describeOrderByItems(items []dataset.OrderByItem) string {
+	var sb strings.Builder
+	for i, it := range items {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(it.Column)
+		if it.Desc {
+			sb.WriteString(" DESC")
+		}
+	}
+	return sb.String()
+}
+
+type recordStatement struct {
+	kind  string // Query or Exec
+	db    string
+	query string
+	args  []interface{}
+}
+
+// recordConn records the statements instead of executing them.
+type recordConn struct {
+	mu         sync.Mutex
+	statements []*recordStatement
+}
+
+func (r *recordConn) Query(_ context.Context, db string, query string, args ...interface{}) (proto.Result, error) {
+	r.record("Query", db, query, args)
+	return resultx.New(resultx.WithDataset(&dataset.VirtualDataset{})), nil
+}
+
+func (r *recordConn) Exec(_ context.Context, db string, query string, args ...interface{}) (proto.Result, error) {
+	r.record("Exec", db, query, args)
+	return resultx.New(), nil
+}
+
+func (r *recordConn) record(kind, db, query string, args []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, &recordStatement{
+		kind:  kind,
+		db:    db,
+		query: query,
+		args:  args,
+	})
+}
+
+// sorted returns the recorded statements ordered by database, because some plans are executed concurrently.
+func (r *recordConn) sorted() []*recordStatement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.SliceStable(r.statements, func(i, j int) bool {
+		return r.statements[i].db < r.statements[j].db
+	})
+	return r.statements
+}
@@ -0,0 +1,641 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dml
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	constant "github.com/arana-db/arana/pkg/constants/mysql"
+	"github.com/arana-db/arana/pkg/dataset"
+	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/mysql/rows"
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+	"github.com/arana-db/arana/pkg/runtime/misc"
+	"github.com/arana-db/arana/pkg/runtime/plan"
+)
+
+const (
+	InformationSchemaTables     = "tables"
+	InformationSchemaColumns    = "columns"
+	InformationSchemaStatistics = "statistics"
+)
+
+// _aggregatedTableColumns are the columns of information_schema.tables which will be summed up across shards.
+var _aggregatedTableColumns = []string{"TABLE_ROWS", "DATA_LENGTH", "INDEX_LENGTH", "DATA_FREE"}
+
+var _ proto.Plan = (*InformationSchemaPlan)(nil)
+
+// InformationSchemaPlan serves information_schema.tables/columns/statistics with logical tables.
+//
+// The metadata of physical tables is collected from backends and converted to logical tables, then
+// the WHERE, ORDER BY and LIMIT clauses are evaluated in proxy on the virtual rows, so the metadata of
+// logical tables is never sent back to backends.
+type InformationSchemaPlan struct {
+	plan.BasePlan
+	Stmt   *ast.SelectStatement
+	Table  string
+	shards map[string]rule.DatabaseTables // logical table name -> shards
+	views  []string
+}
+
+// NewInformationSchemaPlan creates an InformationSchemaPlan for the given information_schema table.
+func NewInformationSchemaPlan(stmt *ast.SelectStatement, table string) *InformationSchemaPlan {
+	return &InformationSchemaPlan{
+		Stmt:  stmt,
+		Table: strings.ToLower(table),
+	}
+}
+
+func (p *InformationSchemaPlan) Type() proto.PlanType {
+	return proto.PlanTypeQuery
+}
+
+func (p *InformationSchemaPlan) SetShards(shards map[string]rule.DatabaseTables) {
+	p.shards = shards
+}
+
+func (p *InformationSchemaPlan) SetViews(views []string) {
+	p.views = views
+}
+
+func (p *InformationSchemaPlan) ExecIn(ctx context.Context, conn proto.VConn) (proto.Result, error) {
+	ctx, span := plan.Tracer.Start(ctx, "InformationSchemaPlan.ExecIn")
+	defer span.End()
+
+	fields, values, err := p.collect(ctx, conn)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	names := fieldNames(fields)
+	eval := &localEvaluator{
+		fields: names,
+		args:   p.Args,
+	}
+
+	if p.Stmt.Where != nil {
+		filtered := values[:0]
+		for _, next := range values {
+			ok, err := eval.evalBool(p.Stmt.Where, next)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if ok {
+				filtered = append(filtered, next)
+			}
+		}
+		values = filtered
+	}
+
+	columns, projected, err := p.project(eval, fields, values)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err = p.sort(eval, columns, values, projected); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if projected, err = p.limit(projected); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ds := &dataset.VirtualDataset{
+		Columns: columns,
+	}
+	for _, next := range projected {
+		ds.Rows = append(ds.Rows, rows.NewTextVirtualRow(columns, next))
+	}
+
+	return resultx.New(resultx.WithDataset(ds)), nil
+}
+
+// project evaluates the select elements on each row.
+func (p *InformationSchemaPlan) project(eval *localEvaluator, fields []proto.Field, values [][]proto.Value) ([]proto.Field, [][]proto.Value, error) {
+	if p.Stmt.IsDistinct() || p.Stmt.GroupBy != nil || p.Stmt.Having != nil {
+		return nil, nil, errors.Errorf("DISTINCT, GROUP BY and HAVING on information_schema.%s are not supported", p.Table)
+	}
+
+	var (
+		columns   []proto.Field
+		selectors []func(row []proto.Value) (proto.Value, error)
+	)
+
+	for _, sel := range p.Stmt.Select {
+		switch it := sel.(type) {
+		case *ast.SelectElementAll:
+			for i := range fields {
+				idx := i
+				columns = append(columns, fields[idx])
+				selectors = append(selectors, func(row []proto.Value) (proto.Value, error) {
+					return row[idx], nil
+				})
+			}
+		case *ast.SelectElementColumn:
+			idx := indexOfField(eval.fields, it.Suffix())
+			if idx == -1 {
+				return nil, nil, errors.Errorf("unknown column '%s' in information_schema.%s", it.Suffix(), p.Table)
+			}
+			field := fields[idx]
+			if alias := it.Alias(); len(alias) > 0 {
+				field = mysql.NewField(alias, fields[idx].(*mysql.Field).FieldType())
+			}
+			columns = append(columns, field)
+			selectors = append(selectors, func(row []proto.Value) (proto.Value, error) {
+				return row[idx], nil
+			})
+		case *ast.SelectElementExpr:
+			name := it.Alias()
+			if len(name) < 1 {
+				name = it.ToSelectString()
+			}
+			expr := it.Expression()
+			columns = append(columns, mysql.NewField(name, constant.FieldTypeVarString))
+			selectors = append(selectors, func(row []proto.Value) (proto.Value, error) {
+				v, err := eval.eval(expr, row)
+				if b, ok := v.(bool); ok {
+					if b {
+						return int64(1), err
+					}
+					return int64(0), err
+				}
+				return v, err
+			})
+		default:
+			return nil, nil, errors.Errorf("select element %s on information_schema.%s is not supported", sel.ToSelectString(), p.Table)
+		}
+	}
+
+	ret := make([][]proto.Value, 0, len(values))
+	for _, next := range values {
+		row := make([]proto.Value, 0, len(selectors))
+		for _, selector := range selectors {
+			v, err := selector(next)
+			if err != nil {
+				return nil, nil, err
+			}
+			row = append(row, normalizeValue(v))
+		}
+		ret = append(ret, row)
+	}
+
+	return columns, ret, nil
+}
+
+// sort orders the projected rows, the ORDER BY items are resolved by the output columns first,
+// then the source columns.
+func (p *InformationSchemaPlan) sort(eval *localEvaluator, columns []proto.Field, values, projected [][]proto.Value) error {
+	if len(p.Stmt.OrderBy) < 1 {
+		return nil
+	}
+
+	type sortKey struct {
+		projected bool
+		idx       int
+		desc      bool
+	}
+
+	keys := make([]sortKey, 0, len(p.Stmt.OrderBy))
+	for _, item := range p.Stmt.OrderBy {
+		switch expr := item.Expr.(type) {
+		case ast.ColumnNameExpressionAtom:
+			if idx := indexOfField(fieldNames(columns), expr.Suffix()); idx != -1 {
+				keys = append(keys, sortKey{projected: true, idx: idx, desc: item.Desc})
+				continue
+			}
+			idx, err := eval.column(expr)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, sortKey{idx: idx, desc: item.Desc})
+		case *ast.ConstantExpressionAtom:
+			n, ok := toFloat(expr.Inner)
+			if !ok || n < 1 || int(n) > len(columns) {
+				return errors.Errorf("unknown column '%s' in 'order clause'", expr.String())
+			}
+			keys = append(keys, sortKey{projected: true, idx: int(n) - 1, desc: item.Desc})
+		default:
+			return errors.Errorf("ORDER BY %T on information_schema.%s is not supported", item.Expr, p.Table)
+		}
+	}
+
+	idx := make([]int, len(projected))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		for _, key := range keys {
+			rows := values
+			if key.projected {
+				rows = projected
+			}
+			a, b := normalizeValue(rows[idx[i]][key.idx]), normalizeValue(rows[idx[j]][key.idx])
+			var n int
+			switch {
+			case a == nil && b == nil:
+			case a == nil:
+				n = -1
+			case b == nil:
+				n = 1
+			default:
+				n = compareValues(a, b)
+			}
+			if key.desc {
+				n = -n
+			}
+			if n != 0 {
+				return n < 0
+			}
+		}
+		return false
+	})
+
+	sorted := make([][]proto.Value, len(projected))
+	for i, j := range idx {
+		sorted[i] = projected[j]
+	}
+	copy(projected, sorted)
+
+	return nil
+}
+
+func (p *InformationSchemaPlan) limit(values [][]proto.Value) ([][]proto.Value, error) {
+	limit := p.Stmt.Limit
+	if limit == nil {
+		return values, nil
+	}
+
+	value := func(n int64, isVar bool) (int64, error) {
+		if !isVar {
+			return n, nil
+		}
+		if int(n) >= len(p.Args) {
+			return 0, errors.Errorf("missing argument #%d", n)
+		}
+		f, ok := toFloat(p.Args[n])
+		if !ok {
+			return 0, errors.Errorf("invalid limit argument %v", p.Args[n])
+		}
+		return int64(f), nil
+	}
+
+	var offset int64
+	if limit.HasOffset() {
+		n, err := value(limit.Offset(), limit.IsOffsetVar())
+		if err != nil {
+			return nil, err
+		}
+		offset = n
+	}
+	size, err := value(limit.Limit(), limit.IsLimitVar())
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= int64(len(values)) {
+		return nil, nil
+	}
+	values = values[offset:]
+	if size < int64(len(values)) {
+		values = values[:size]
+	}
+	return values, nil
+}
+
+// collect loads metadata rows from backends and converts them to logical tables.
+func (p *InformationSchemaPlan) collect(ctx context.Context, conn proto.VConn) ([]proto.Field, [][]proto.Value, error) {
+	var (
+		schema = rcontext.Schema(ctx)
+		// physical tables and logical tables which should not be exposed from the default group
+		hidden = make(map[string]struct{})
+		// group -> physical table -> logical table
+		targets = make(map[string]map[string]string)
+		logical = make([]string, 0, len(p.shards))
+	)
+
+	for name, shards := range p.shards {
+		logical = append(logical, name)
+		hidden[name] = struct{}{}
+		for _, tables := range shards {
+			for _, table := range tables {
+				hidden[table] = struct{}{}
+			}
+		}
+
+		// table status is aggregated from all shards, columns and indexes are described by the first shard
+		if p.Table == InformationSchemaTables {
+			for group, tables := range shards {
+				for _, table := range tables {
+					p.target(targets, group, table, name)
+				}
+			}
+		} else if group, table, ok := p.representative(shards); ok {
+			p.target(targets, group, table, name)
+		}
+	}
+	sort.Strings(logical)
+
+	columns, values, err := p.query(ctx, conn, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		fields   = fieldNames(columns)
+		idxTable = indexOfField(fields, "TABLE_NAME")
+		ret      = make([][]proto.Value, 0, len(values))
+	)
+	if idxTable == -1 {
+		return nil, nil, errors.Errorf("cannot find column TABLE_NAME in information_schema.%s", p.Table)
+	}
+
+	for _, next := range values {
+		if _, ok := hidden[toString(next[idxTable])]; ok {
+			continue
+		}
+		p.rename(fields, next, schema, "")
+		ret = append(ret, next)
+	}
+
+	groups := make([]string, 0, len(targets))
+	for group := range targets {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	sharded := make(map[string][][]proto.Value, len(logical))
+	for _, group := range groups {
+		tables := make([]string, 0, len(targets[group]))
+		for table := range targets[group] {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+
+		_, shardValues, err := p.query(ctx, conn, group, tables)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, next := range shardValues {
+			name, ok := targets[group][toString(next[idxTable])]
+			if !ok {
+				continue
+			}
+			p.rename(fields, next, schema, name)
+			sharded[name] = append(sharded[name], next)
+		}
+	}
+
+	for _, name := range logical {
+		values := sharded[name]
+		if len(values) == 0 {
+			continue
+		}
+		if p.Table == InformationSchemaTables {
+			values = [][]proto.Value{aggregateTables(fields, values)}
+		}
+		ret = append(ret, values...)
+	}
+
+	if p.Table == InformationSchemaTables {
+		for _, view := range p.views {
+			ret = append(ret, newViewRow(fields, schema, view))
+		}
+	}
+
+	return columns, ret, nil
+}
+
+func (p *InformationSchemaPlan) target(targets map[string]map[string]string, group, table, name string) {
+	if _, ok := targets[group]; !ok {
+		targets[group] = make(map[string]string)
+	}
+	targets[group][table] = name
+}
+
+// representative returns the first shard which is used to describe columns and indexes of a logical table.
+func (p *InformationSchemaPlan) representative(shards rule.DatabaseTables) (string, string, bool) {
+	groups := make([]string, 0, len(shards))
+	for group := range shards {
+		if len(shards[group]) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) < 1 {
+		return "", "", false
+	}
+	sort.Strings(groups)
+
+	tables := append([]string(nil), shards[groups[0]]...)
+	sort.Strings(tables)
+
+	return groups[0], tables[0], true
+}
+
+// rename replaces the physical database and table with the logical ones.
+func (p *InformationSchemaPlan) rename(fields []string, values []proto.Value, schema, table string) {
+	for i, field := range fields {
+		switch strings.ToUpper(field) {
+		case "TABLE_SCHEMA", "INDEX_SCHEMA":
+			if len(schema) > 0 {
+				values[i] = schema
+			}
+		case "TABLE_NAME":
+			if len(table) > 0 {
+				values[i] = table
+			}
+		}
+	}
+}
+
+func (p *InformationSchemaPlan) query(ctx context.Context, conn proto.VConn, group string, tables []string) ([]proto.Field, [][]proto.Value, error) {
+	var sb strings.Builder
+
+	sb.WriteString("SELECT * FROM `information_schema`.")
+	ast.WriteID(&sb, p.Table)
+	sb.WriteString(" WHERE `TABLE_SCHEMA` = DATABASE()")
+	if len(tables) > 0 {
+		sb.WriteString(" AND `TABLE_NAME` IN (")
+		for i, table := range tables {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('\'')
+			misc.WriteEscape(&sb, table, misc.EscapeSingleQuote)
+			sb.WriteByte('\'')
+		}
+		sb.WriteByte(')')
+	}
+
+	res, err := conn.Query(ctx, group, sb.String())
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	ds, err := res.Dataset()
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer func() {
+		_ = ds.Close()
+	}()
+
+	fields, err := ds.Fields()
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	var values [][]proto.Value
+	for {
+		next, err := ds.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		dest := make([]proto.Value, len(fields))
+		if err = next.Scan(dest); err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		for i := range dest {
+			dest[i] = normalizeValue(dest[i])
+		}
+		values = append(values, dest)
+	}
+
+	return fields, values, nil
+}
+
+func aggregateTables(fields []string, values [][]proto.Value) []proto.Value {
+	ret := append([]proto.Value(nil), values[0]...)
+
+	for _, column := range _aggregatedTableColumns {
+		idx := indexOfField(fields, column)
+		if idx == -1 {
+			continue
+		}
+		var (
+			sum   uint64
+			valid bool
+		)
+		for _, next := range values {
+			if n, ok := toUint64(next[idx]); ok {
+				sum += n
+				valid = true
+			}
+		}
+		if valid {
+			ret[idx] = sum
+		}
+	}
+
+	// recompute the average row length with the aggregated values
+	var (
+		idxAvg  = indexOfField(fields, "AVG_ROW_LENGTH")
+		idxRows = indexOfField(fields, "TABLE_ROWS")
+		idxData = indexOfField(fields, "DATA_LENGTH")
+	)
+	if idxAvg != -1 && idxRows != -1 && idxData != -1 {
+		rows, ok1 := toUint64(ret[idxRows])
+		data, ok2 := toUint64(ret[idxData])
+		if ok1 && ok2 && rows > 0 {
+			ret[idxAvg] = data / rows
+		}
+	}
+
+	return ret
+}
+
+func newViewRow(fields []string, schema, view string) []proto.Value {
+	ret := make([]proto.Value, len(fields))
+	for i, field := range fields {
+		switch strings.ToUpper(field) {
+		case "TABLE_CATALOG":
+			ret[i] = "def"
+		case "TABLE_SCHEMA":
+			ret[i] = schema
+		case "TABLE_NAME":
+			ret[i] = view
+		case "TABLE_TYPE", "TABLE_COMMENT":
+			ret[i] = "VIEW"
+		}
+	}
+	return ret
+}
+
+func fieldNames(fields []proto.Field) []string {
+	ret := make([]string, 0, len(fields))
+	for _, it := range fields {
+		ret = append(ret, it.Name())
+	}
+	return ret
+}
+
+func indexOfField(fields []string, name string) int {
+	for i, field := range fields {
+		if strings.EqualFold(field, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func toString(value proto.Value) string {
+	switch val := value.(type) {
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	}
+	return ""
+}
+
+func toUint64(value proto.Value) (uint64, bool) {
+	switch val := value.(type) {
+	case uint64:
+		return val, true
+	case int64:
+		if val >= 0 {
+			return uint64(val), true
+		}
+	case string:
+		if n, err := strconv.ParseUint(val, 10, 64); err == nil {
+			return n, true
+		}
+	case []byte:
+		if n, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,350 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dml
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/runtime/ast"
+	"github.com/arana-db/arana/pkg/runtime/cmp"
+	"github.com/arana-db/arana/pkg/runtime/logical"
+)
+
+// localEvaluator evaluates the expressions on the virtual rows in proxy, only the simple predicates
+// on columns, constants and parameters are supported, eg: comparisons, IN, LIKE, BETWEEN and IS NULL.
+// The result of predicate is nil if it is unknown (NULL), otherwise it is a bool.
+type localEvaluator struct {
+	fields []string
+	args   []interface{}
+}
+
+func (le *localEvaluator) column(name ast.ColumnNameExpressionAtom) (int, error) {
+	idx := indexOfField(le.fields, name.Suffix())
+	if idx == -1 {
+		return -1, errors.Errorf("unknown column '%s' in information_schema", name.String())
+	}
+	return idx, nil
+}
+
+func (le *localEvaluator) evalBool(node ast.ExpressionNode, row []proto.Value) (bool, error) {
+	v, err := le.eval(node, row)
+	if err != nil {
+		return false, err
+	}
+	b, _ := toBool(v).(bool)
+	return b, nil
+}
+
+func (le *localEvaluator) eval(node ast.ExpressionNode, row []proto.Value) (interface{}, error) {
+	switch t := node.(type) {
+	case *ast.LogicalExpressionNode:
+		left, err := le.eval(t.Left, row)
+		if err != nil {
+			return nil, err
+		}
+		right, err := le.eval(t.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		l, r := toBool(left), toBool(right)
+		if t.Op == logical.Lor {
+			if l == true || r == true {
+				return true, nil
+			}
+		} else if l == false || r == false {
+			return false, nil
+		}
+		if l == nil || r == nil {
+			return nil, nil
+		}
+		return t.Op != logical.Lor, nil
+	case *ast.NotExpressionNode:
+		v, err := le.eval(t.E, row)
+		if err != nil {
+			return nil, err
+		}
+		return not(toBool(v)), nil
+	case *ast.PredicateExpressionNode:
+		return le.evalPredicate(t.P, row)
+	default:
+		return nil, errors.Errorf("unsupported expression %T in information_schema", node)
+	}
+}
+
+func (le *localEvaluator) evalPredicate(node ast.PredicateNode, row []proto.Value) (interface{}, error) {
+	switch t := node.(type) {
+	case *ast.AtomPredicateNode:
+		return le.evalAtom(t.A, row)
+	case *ast.BinaryComparisonPredicateNode:
+		left, err := le.evalPredicate(t.Left, row)
+		if err != nil {
+			return nil, err
+		}
+		// IS NULL and IS NOT NULL
+		if atom, ok := t.Right.(*ast.AtomPredicateNode); ok {
+			if c, ok := atom.A.(*ast.ConstantExpressionAtom); ok {
+				if _, ok = c.Inner.(ast.Null); ok {
+					return (left == nil) == (t.Op == cmp.Ceq), nil
+				}
+			}
+		}
+		right, err := le.evalPredicate(t.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		n := compareValues(left, right)
+		switch t.Op {
+		case cmp.Ceq:
+			return n == 0, nil
+		case cmp.Cne:
+			return n != 0, nil
+		case cmp.Cgt:
+			return n > 0, nil
+		case cmp.Cgte:
+			return n >= 0, nil
+		case cmp.Clt:
+			return n < 0, nil
+		case cmp.Clte:
+			return n <= 0, nil
+		}
+		return nil, errors.Errorf("unsupported comparison %s in information_schema", t.Op)
+	case *ast.InPredicateNode:
+		key, err := le.evalPredicate(t.P, row)
+		if err != nil || key == nil {
+			return nil, err
+		}
+		var unknown bool
+		for _, it := range t.E {
+			v, err := le.eval(it, row)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				unknown = true
+				continue
+			}
+			if compareValues(key, v) == 0 {
+				return !t.Not, nil
+			}
+		}
+		if unknown {
+			return nil, nil
+		}
+		return t.Not, nil
+	case *ast.LikePredicateNode:
+		left, err := le.evalPredicate(t.Left, row)
+		if err != nil {
+			return nil, err
+		}
+		right, err := le.evalPredicate(t.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		if left == nil || right == nil {
+			return nil, nil
+		}
+		return matchLike(toText(left), toText(right)) != t.Not, nil
+	case *ast.BetweenPredicateNode:
+		key, err := le.evalPredicate(t.Key, row)
+		if err != nil {
+			return nil, err
+		}
+		left, err := le.evalPredicate(t.Left, row)
+		if err != nil {
+			return nil, err
+		}
+		right, err := le.evalPredicate(t.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil || left == nil || right == nil {
+			return nil, nil
+		}
+		between := compareValues(key, left) >= 0 && compareValues(key, right) <= 0
+		return between != t.Not, nil
+	default:
+		return nil, errors.Errorf("unsupported predicate %T in information_schema", node)
+	}
+}
+
+func (le *localEvaluator) evalAtom(atom ast.ExpressionAtom, row []proto.Value) (interface{}, error) {
+	switch t := atom.(type) {
+	case ast.ColumnNameExpressionAtom:
+		idx, err := le.column(t)
+		if err != nil {
+			return nil, err
+		}
+		return normalizeValue(row[idx]), nil
+	case *ast.ConstantExpressionAtom:
+		if _, ok := t.Inner.(ast.Null); ok {
+			return nil, nil
+		}
+		return t.Inner, nil
+	case ast.VariableExpressionAtom:
+		if t.N() >= len(le.args) {
+			return nil, errors.Errorf("missing argument #%d", t.N())
+		}
+		return normalizeValue(le.args[t.N()]), nil
+	case *ast.NestedExpressionAtom:
+		return le.eval(t.First, row)
+	case *ast.UnaryExpressionAtom:
+		var (
+			v   interface{}
+			err error
+		)
+		switch inner := t.Inner.(type) {
+		case ast.ExpressionAtom:
+			v, err = le.evalAtom(inner, row)
+		case *ast.BinaryComparisonPredicateNode:
+			v, err = le.evalPredicate(inner, row)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.IsOperatorNot():
+			return not(toBool(v)), nil
+		case t.Operator == "-":
+			if f, ok := toFloat(v); ok {
+				return -f, nil
+			}
+			return nil, nil
+		}
+		return nil, errors.Errorf("unsupported operator %s in information_schema", t.Operator)
+	default:
+		return nil, errors.Errorf("unsupported expression %T in information_schema", atom)
+	}
+}
+
+func not(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		return !b
+	}
+	return nil
+}
+
+// toBool converts the value to bool, returns nil if the value is NULL.
+func toBool(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case bool:
+		return val
+	}
+	f, _ := toFloat(v)
+	return f != 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case int64:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case float64:
+		return val, true
+	case bool:
+		if val {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(v)
+}
+
+// normalizeValue converts the bytes to string, so they can be compared and written as text.
+func normalizeValue(v proto.Value) proto.Value {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+// compareValues compares two non-NULL values, the strings are compared case-insensitively just like
+// the default collation of information_schema, and they are compared as numbers if any side is a number.
+func compareValues(a, b interface{}) int {
+	_, aText := a.(string)
+	_, bText := b.(string)
+	if !aText || !bText {
+		if x, ok := toFloat(a); ok {
+			if y, ok := toFloat(b); ok {
+				switch {
+				case x < y:
+					return -1
+				case x > y:
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+	return strings.Compare(strings.ToLower(toText(a)), strings.ToLower(toText(b)))
+}
+
+// matchLike returns true if the value matches the LIKE pattern case-insensitively.
+func matchLike(value, pattern string) bool {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteByte('.')
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteByte('$')
+	matched, _ := regexp.MatchString(sb.String(), value)
+	return matched
+}
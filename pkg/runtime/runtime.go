@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 )
@@ -41,12 +42,15 @@ import (
 )
 
 import (
+	"github.com/arana-db/arana/pkg/breaker"
 	"github.com/arana-db/arana/pkg/config"
 	"github.com/arana-db/arana/pkg/metrics"
 	"github.com/arana-db/arana/pkg/mysql"
+	"github.com/arana-db/arana/pkg/process"
 	"github.com/arana-db/arana/pkg/proto"
 	"github.com/arana-db/arana/pkg/proto/hint"
 	"github.com/arana-db/arana/pkg/resultx"
+	"github.com/arana-db/arana/pkg/runtime/consistency"
 	rcontext "github.com/arana-db/arana/pkg/runtime/context"
 	"github.com/arana-db/arana/pkg/runtime/namespace"
 	"github.com/arana-db/arana/pkg/runtime/optimize"
@@ -67,7 +71,10 @@ var (
 
 var Tracer = otel.Tracer("Runtime")
 
-var errTxClosed = errors.New("transaction is closed")
+var (
+	errTxClosed = errors.New("transaction is closed")
+	errTxBroken = errors.New("transaction is broken by the interrupted statement, it can only be rolled back")
+)
 
 func NewAtomDB(node *config.Node) *AtomDB {
 	if node == nil {
@@ -78,9 +85,13 @@ func NewAtomDB(node *config.Node) *AtomDB {
 		return nil
 	}
 	db := &AtomDB{
-		id:     node.Name,
-		weight: proto.Weight{R: int32(r), W: int32(w)},
+		id:               node.Name,
+		weight:           proto.Weight{R: int32(r), W: int32(w)},
+		labels:           node.Labels,
+		statementTimeout: config.GetConnPropStatementTimeout(node.ConnProps, 0),
+		breaker:          breaker.New(),
 	}
+	db.breaker.OnStateChange = logBreakerStateChange(db.id)
 
 	raw, _ := json.Marshal(map[string]interface{}{
 		"dsn": fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s", node.Username, node.Password, node.Host, node.Port, node.Database, node.Parameters.String()),
@@ -96,7 +107,20 @@ func NewAtomDB(node *config.Node) *AtomDB {
 		idleTime    = config.GetConnPropIdleTime(node.ConnProps, 30*time.Minute)
 	)
 
-	db.pool = pools.NewResourcePool(connector.NewBackendConnection, capacity, maxCapacity, idleTime, 1, nil)
+	db.connector = connector
+	db.poolOptions = newPoolOptions(node.ConnProps, capacity, maxCapacity)
+	db.pool = pools.NewResourcePool(connector.NewBackendConnection, capacity, maxCapacity, idleTime, 0, nil)
+
+	// warm up in background, neither the creation of db nor the config observer should be blocked by a slow backend.
+	// The warmup is not counted as pending requests, it is cancelled once the db is closed.
+	if size := db.poolOptions.minIdle; size > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), _warmupTimeout)
+		db.stopWarmup = cancel
+		go func() {
+			defer cancel()
+			db.warmup(ctx, size)
+		}()
+	}
 
 	return db
 }
@@ -122,6 +146,7 @@ func Load(schema string) (Runtime, error) {
 
 var (
 	_ proto.DB       = (*AtomDB)(nil)
+	_ proto.DBStats  = (*AtomDB)(nil)
 	_ proto.Callable = (*atomTx)(nil)
 	_ proto.Tx       = (*compositeTx)(nil)
 )
@@ -154,10 +179,13 @@ func (tx *compositeTx) call(ctx context.Context, db string, query string, args .
 
 	log.Debugf("call upstream: db=%s, sql=\"%s\", args=%v", db, query, args)
 
+	ctx, query, done := startPhysical(ctx, tx.rt.Namespace().Name(), db, atx.parent.id, query)
 	res, _, err := atx.Call(ctx, query, args...)
 	if err != nil {
+		done(err)
 		return nil, perrors.WithStack(err)
 	}
+	onResultClose(res, done)
 	return res, nil
 }
 
@@ -223,6 +251,9 @@ func (tx *compositeTx) Execute(ctx *proto.Context) (res proto.Result, warn uint1
 	)
 
 	c = rcontext.WithSQL(c, ctx.GetQuery())
+	c = rcontext.WithSchema(c, ctx.Schema)
+	c = rcontext.WithTenant(c, ctx.Tenant)
+	c = rcontext.WithUser(c, ctx.Username)
 	c = rcontext.WithHints(c, ctx.Stmt.Hints)
 
 	var opt proto.Optimizer
@@ -318,8 +349,10 @@ func (tx *compositeTx) Rollback(ctx context.Context) (proto.Result, uint16, erro
 
 type atomTx struct {
 	closed atomic.Bool
+	broken atomic.Bool
 	parent *AtomDB
 	bc     *mysql.BackendConnection
+	detach func()
 }
 
 func (tx *atomTx) Commit(ctx context.Context) (res proto.Result, warn uint16, err error) {
@@ -329,6 +362,10 @@ func (tx *atomTx) Commit(ctx context.Context) (res proto.Result, warn uint16, er
 		return
 	}
 	defer tx.dispose()
+	if tx.broken.Load() {
+		err = errTxBroken
+		return
+	}
 	if res, err = tx.bc.ExecuteWithWarningCount("commit", true); err != nil {
 		return
 	}
@@ -352,19 +389,62 @@ func (tx *atomTx) Rollback(ctx context.Context) (res proto.Result, warn uint16,
 		return
 	}
 	defer tx.dispose()
+	if tx.broken.Load() {
+		// the backend connection will be discarded, which rolls back the transaction in server side
+		res = resultx.New()
+		return
+	}
 	res, err = tx.bc.ExecuteWithWarningCount("rollback", true)
 	return
 }
 
 func (tx *atomTx) Call(ctx context.Context, sql string, args ...interface{}) (res proto.Result, warn uint16, err error) {
+	if tx.broken.Load() {
+		err = errTxBroken
+		return
+	}
+	if err = tx.bc.SyncVariables(rcontext.Variables(ctx)); err != nil {
+		err = perrors.WithStack(err)
+		return
+	}
+
+	var done func(outcome breaker.Outcome)
+	if done, err = tx.parent.allow(); err != nil {
+		return
+	}
+
+	g := tx.parent.guard(ctx, tx.bc, done)
+
 	if len(args) > 0 {
 		res, err = tx.bc.PrepareQueryArgs(sql, args)
 	} else {
 		res, err = tx.bc.ExecuteWithWarningCountIterRow(sql)
 	}
+
+	if err != nil {
+		err = g.mapError(err)
+		tx.release(g)
+		return
+	}
+
+	res.(*mysql.RawResult).SetErrorHook(g.mapError)
+	res.(*mysql.RawResult).SetEOFHook(func() { g.stop() })
+	res.(*mysql.RawResult).SetCloser(func() error {
+		tx.release(g)
+		return nil
+	})
+
 	return
 }
 
+// release stops watching the call, the transaction is broken if the call has been interrupted,
+// since the state of backend connection is unknown.
+func (tx *atomTx) release(g *callGuard) {
+	if g.release() {
+		tx.broken.Store(true)
+	}
+}
+
 func (tx *atomTx) CallFieldList(ctx context.Context, table, wildcard string) ([]proto.Field, error) {
 	// TODO: choose table
 	var err error
@@ -378,10 +458,16 @@ func (tx *atomTx) dispose() {
 	defer func() {
 		tx.parent = nil
 		tx.bc = nil
+		tx.detach = nil
 	}()
 
+	tx.detach()
 	cnt := tx.parent.pendingRequests.Dec()
-	tx.parent.returnConnection(tx.bc)
+	if tx.broken.Load() {
+		tx.parent.discardConnection(tx.bc)
+	} else {
+		tx.parent.returnConnection(tx.bc)
+	}
 	if cnt == 0 && tx.parent.closed.Load() {
 		tx.parent.pool.Close()
 	}
@@ -391,11 +477,23 @@ type AtomDB struct {
 	id string
 
 	weight proto.Weight
+	labels map[string]string
 	pool   *pools.ResourcePool
 
 	closed atomic.Bool
 
 	pendingRequests atomic.Int64
+
+	latency atomic.Float64 // EWMA of call durations in nanoseconds
+
+	statementTimeout time.Duration
+	breaker          *breaker.Breaker
+	connector        *mysql.Connector // opens the dedicated connections out of pool, such as KILL QUERY
+
+	poolOptions poolOptions
+	stopWarmup  context.CancelFunc // cancels the warmup of pool, nil if no warmup
+	expired     atomic.Int64       // count of connections closed due to max lifetime
+	invalid     atomic.Int64       // count of connections failed in validation
 }
 
 func (db *AtomDB) begin(ctx context.Context) (*atomTx, error) {
@@ -435,7 +533,7 @@ func (db *AtomDB) begin(ctx context.Context) (*atomTx, error) {
 		return nil, perrors.WithStack(err)
 	}
 
-	return &atomTx{parent: db, bc: bc}, nil
+	return &atomTx{parent: db, bc: bc, detach: db.attach(ctx, bc)}, nil
 }
 
 func (db *AtomDB) CallFieldList(ctx context.Context, table, wildcard string) ([]proto.Field, error) {
@@ -468,15 +566,24 @@ func (db *AtomDB) Call(ctx context.Context, sql string, args ...interface{}) (re
 		return
 	}
 
+	var done func(outcome breaker.Outcome)
+	if done, err = db.allow(); err != nil {
+		return
+	}
+
 	var bc *mysql.BackendConnection
 
 	if bc, err = db.borrowConnection(ctx); err != nil {
+		done(outcomeOf(err))
 		err = perrors.WithStack(err)
 		return
 	}
 
 	undoPending := db.pending()
+	detach := db.attach(ctx, bc)
+	g := db.guard(ctx, bc, done)
 
+	start := time.Now()
 	if len(args) > 0 {
 		res, err = bc.PrepareQueryArgs(sql, args)
 	} else {
@@ -484,14 +591,22 @@ func (db *AtomDB) Call(ctx context.Context, sql string, args ...interface{}) (re
 	}
 
 	if err != nil {
+		err = g.mapError(err)
+		detach()
 		undoPending()
-		db.returnConnection(bc)
+		db.releaseConnection(bc, g)
 		return
 	}
 
+	// only the successful calls are sampled, the fast failures should not make a broken node look faster
+	db.observeLatency(time.Since(start))
+
+	res.(*mysql.RawResult).SetErrorHook(g.mapError)
+	res.(*mysql.RawResult).SetEOFHook(func() { g.stop() })
 	res.(*mysql.RawResult).SetCloser(func() error {
+		detach()
 		undoPending()
-		db.returnConnection(bc)
+		db.releaseConnection(bc, g)
 		return nil
 	})
 
@@ -500,6 +615,9 @@ func (db *AtomDB) Call(ctx context.Context, sql string, args ...interface{}) (re
 
 func (db *AtomDB) Close() error {
 	if db.closed.CAS(false, true) {
+		if db.stopWarmup != nil {
+			db.stopWarmup()
+		}
 		if db.pendingRequests.Load() == 0 {
 			db.pool.Close()
 		}
@@ -517,6 +635,59 @@ func (db *AtomDB) pending() func() {
 	}
 }
 
+// _latencyDecay is the weight of new sample in EWMA of latency.
+const _latencyDecay = 0.3
+
+// observeLatency records the call duration into EWMA of latency.
+func (db *AtomDB) observeLatency(d time.Duration) {
+	sample := float64(d)
+	for {
+		prev := db.latency.Load()
+		next := sample
+		if prev > 0 {
+			next = prev + _latencyDecay*(sample-prev)
+		}
+		if db.latency.CAS(prev, next) {
+			return
+		}
+	}
+}
+
+// attach binds the backend connection to the process of current context, so that the query executing on it can be killed.
+func (db *AtomDB) attach(ctx context.Context, bc *mysql.BackendConnection) func() {
+	p, ok := process.FromContext(ctx)
+	if !ok {
+		return func() {}
+	}
+	connectionID := bc.GetDatabaseConn().ConnectionID
+	return p.Attach(func(ctx context.Context) error {
+		return db.killQuery(ctx, connectionID)
+	})
+}
+
+// killQuery kills the query which is executing on the backend connection. The KILL is sent on a dedicated
+// connection which is bounded by the deadline of context, instead of a normal call, because the pool may be
+// exhausted by the very query being killed, and the circuit breaker may be open already.
+func (db *AtomDB) killQuery(ctx context.Context, connectionID uint32) error {
+	res, err := db.connector.NewBackendConnection(ctx)
+	bc, _ := res.(*mysql.BackendConnection)
+	if bc != nil && bc.GetDatabaseConn() != nil {
+		defer bc.Close()
+	}
+	if err != nil {
+		return perrors.Wrapf(err, "failed to connect backend '%s'", db.id)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = bc.GetDatabaseConn().GetNetConn().SetDeadline(deadline); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+
+	_, err = bc.ExecuteWithWarningCount(fmt.Sprintf("KILL QUERY %d", connectionID), false)
+	return perrors.WithStack(err)
+}
+
 func (db *AtomDB) ID() string {
 	return db.id
 }
@@ -537,6 +708,18 @@ func (db *AtomDB) Weight() proto.Weight {
 	return db.weight
 }
 
+func (db *AtomDB) Labels() map[string]string {
+	return db.labels
+}
+
+func (db *AtomDB) ActiveRequests() int64 {
+	return db.pendingRequests.Load()
+}
+
+func (db *AtomDB) Latency() time.Duration {
+	return time.Duration(db.latency.Load())
+}
+
 func (db *AtomDB) SetCapacity(capacity int) error {
 	return db.pool.SetCapacity(capacity)
 }
@@ -566,14 +749,53 @@ func (db *AtomDB) borrowConnection(ctx context.Context) (*mysql.BackendConnectio
 	if err != nil {
 		return nil, perrors.WithStack(err)
 	}
+
+	// reopen the connection which is expired or broken
+	for i := 0; ; i++ {
+		if err = db.validate(res); err == nil {
+			break
+		}
+		db.discardConnection(res)
+		if i >= _maxValidateRetries {
+			return nil, perrors.Wrapf(err, "no usable connection of backend '%s'", db.id)
+		}
+		log.Debugf("backend connection of '%s' is not usable, try another one: %v", db.id, err)
+		if res, err = bcp.Get(ctx); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
+
+	// replay the session variables of frontend connection
+	if err = res.SyncVariables(rcontext.Variables(ctx)); err != nil {
+		db.discardConnection(res)
+		return nil, perrors.WithStack(err)
+	}
 	return res, nil
 }
 
+// returnConnection puts the backend connection back to pool, the session variables are kept as is,
+// they will be synchronized lazily by the next borrower only if they differ.
 func (db *AtomDB) returnConnection(bc *mysql.BackendConnection) {
+	bc.MarkIdle()
 	db.pool.Put(bc)
 	// log.Infof("^^^^^ return conn: active=%d, available=%d", db.pool.Active(), db.pool.Available())
 }
 
+// releaseConnection returns the backend connection after the guarded call, or discards it if the call has been interrupted.
+func (db *AtomDB) releaseConnection(bc *mysql.BackendConnection, g *callGuard) {
+	if g.release() {
+		db.discardConnection(bc)
+		return
+	}
+	db.returnConnection(bc)
+}
+
+// discardConnection closes the backend connection, and puts a new one into the pool.
+func (db *AtomDB) discardConnection(bc *mysql.BackendConnection) {
+	bc.Close()
+	db.pool.Put(nil)
+}
+
 type defaultRuntime namespace.Namespace
 
 func (pi *defaultRuntime) Begin(ctx context.Context) (proto.Tx, error) {
@@ -635,6 +857,7 @@ func (pi *defaultRuntime) Execute(ctx *proto.Context) (res proto.Result, warn ui
 	c = rcontext.WithSQL(c, ctx.GetQuery())
 	c = rcontext.WithSchema(c, ctx.Schema)
 	c = rcontext.WithTenant(c, ctx.Tenant)
+	c = rcontext.WithUser(c, ctx.Username)
 	c = rcontext.WithHints(c, ctx.Stmt.Hints)
 
 	start := time.Now()
@@ -661,11 +884,15 @@ func (pi *defaultRuntime) Execute(ctx *proto.Context) (res proto.Result, warn ui
 }
 
 func (pi *defaultRuntime) callDirect(ctx *proto.Context, args []interface{}) (res proto.Result, warn uint16, err error) {
-	res, warn, err = pi.Namespace().DB0(ctx.Context).Call(rcontext.WithWrite(ctx.Context), ctx.GetQuery(), args...)
+	db := pi.Namespace().DB0(ctx.Context)
+	c, query, done := startPhysical(ctx.Context, pi.Namespace().Name(), pi.Namespace().DBGroups()[0], db.ID(), ctx.GetQuery())
+	res, warn, err = db.Call(rcontext.WithWrite(c), query, args...)
 	if err != nil {
+		done(err)
 		err = perrors.WithStack(err)
 		return
 	}
+	onResultClose(res, done)
 	return
 }
 
@@ -675,10 +902,32 @@ func (pi *defaultRuntime) call(ctx context.Context, group, query string, args ..
 		return nil, perrors.Errorf("cannot get upstream database %s", group)
 	}
 	log.Debugf("call upstream: db=%s, id=%s, sql=\"%s\", args=%v", group, db.ID(), query, args)
+
+	if len(group) < 1 {
+		group = pi.Namespace().DBGroups()[0]
+	}
+	ctx, query, done := startPhysical(ctx, pi.Namespace().Name(), group, db.ID(), query)
+
 	// TODO: how to pass warn???
 	res, _, err := db.Call(ctx, query, args...)
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+	onResultClose(res, done)
+
+	return res, nil
+}
 
-	return res, err
+// onResultClose calls the callback when the result is released, or calls it immediately if the result is not closeable.
+func onResultClose(res proto.Result, callback func(err error)) {
+	if rr, ok := res.(*mysql.RawResult); ok {
+		rr.OnClose(func() {
+			callback(nil)
+		})
+		return
+	}
+	callback(nil)
 }
 
 // select db by group
@@ -700,22 +949,47 @@ func selectDB(ctx context.Context, group string, ns *namespace.Namespace) proto.
 	// extracts hints
 	hints := rcontext.Hints(ctx)
 	for _, v := range hints {
-		if v.Type == hint.TypeMaster || v.Type == hint.TypeSlave {
-			hintType = v.Type
-			break
+		switch v.Type {
+		case hint.TypeMaster, hint.TypeSlave:
+			if hintType == 0 {
+				hintType = v.Type
+			}
+		case hint.TypeNodeLabel:
+			// the label specified by hint takes precedence over the one of user or connection
+			ctx = rcontext.WithNodeLabel(ctx, nodeLabelOf(v))
 		}
 	}
 	switch hintType {
 	case hint.TypeMaster:
 		db = ns.DBMaster(ctx, group)
 	case hint.TypeSlave:
-		db = ns.DBSlave(ctx, group)
+		// fallback to master if no slave available
+		if db = ns.DBSlave(ctx, group); db == nil {
+			db = ns.DBMaster(ctx, group)
+		}
 	default:
-		db = ns.DB(ctx, group)
+		// keep the reads after writes consistent if required
+		db = consistency.Ensure(ctx, ns, group, ns.DB(ctx, group))
 	}
 	return db
 }
 
+// nodeLabelOf returns the node label of hint, eg: NODE_LABEL(zone=shanghai,rack=r1) -> zone=shanghai,rack=r1
+func nodeLabelOf(h *hint.Hint) string {
+	var sb strings.Builder
+	for i, it := range h.Inputs {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if len(it.K) > 0 {
+			sb.WriteString(it.K)
+			sb.WriteByte('=')
+		}
+		sb.WriteString(it.V)
+	}
+	return sb.String()
+}
+
 var (
 	_txIds     *snowflake.Node
 	_txIdsOnce sync.Once
@@ -20,6 +20,7 @@ package runtime
 import (
 	"sync"
 	"testing"
+	"time"
 )
 
 import (
@@ -29,6 +30,7 @@ import (
 )
 
 import (
+	"github.com/arana-db/arana/pkg/proto/hint"
 	"github.com/arana-db/arana/pkg/runtime/namespace"
 )
 
@@ -70,3 +72,48 @@ func TestNextTxID(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestNodeLabelOf(t *testing.T) {
+	for _, it := range []struct {
+		input  string
+		expect string
+	}{
+		{"node_label(zone=shanghai)", "zone=shanghai"},
+		{"node_label(zone=shanghai,rack=r1)", "zone=shanghai,rack=r1"},
+		{"node_label(zone:shanghai)", "zone:shanghai"},
+	} {
+		h, err := hint.Parse(it.input)
+		assert.NoError(t, err)
+		assert.Equal(t, it.expect, nodeLabelOf(h))
+	}
+}
+
+func TestAtomDB_ObserveLatency(t *testing.T) {
+	var db AtomDB
+	assert.Zero(t, db.Latency())
+
+	db.observeLatency(10 * time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, db.Latency())
+
+	db.observeLatency(20 * time.Millisecond)
+	assert.Equal(t, 13*time.Millisecond, db.Latency())
+}
+
+func TestNewPoolOptions(t *testing.T) {
+	opts := newPoolOptions(map[string]interface{}{
+		"min_idle":       128,
+		"test_on_borrow": "true",
+		"ping_on_idle":   "30s",
+		"max_lifetime":   3600,
+	}, 8, 64)
+	assert.Equal(t, poolOptions{
+		minIdle:      64,
+		testOnBorrow: true,
+		pingOnIdle:   30 * time.Second,
+		maxLifetime:  time.Hour,
+	}, opts)
+
+	// the pool is warmed up to capacity by default
+	opts = newPoolOptions(nil, 8, 64)
+	assert.Equal(t, poolOptions{minIdle: 8}, opts)
+}
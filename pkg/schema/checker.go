@@ -0,0 +1,215 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/runtime"
+	rcontext "github.com/arana-db/arana/pkg/runtime/context"
+)
+
+var _ proto.SchemaChecker = (*SimpleSchemaLoader)(nil)
+
+// CheckTable loads the columns and indexes of all physical tables of the logical table, then diffs them.
+func (l *SimpleSchemaLoader) CheckTable(ctx context.Context, schema, table string) ([]*proto.SchemaDrift, error) {
+	rt, err := runtime.Load(schema)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	vt, ok := rt.Namespace().Rule().VTable(table)
+	if !ok {
+		return nil, errors.Errorf("no such sharding table %s.%s", schema, table)
+	}
+
+	var (
+		shards   = vt.Topology().Enumerate()
+		metadata = make(map[string]map[string]*proto.TableMetadata, len(shards))
+	)
+
+	ctx = rcontext.WithRead(rcontext.WithDirect(ctx))
+
+	for group, tables := range shards {
+		if metadata[group], err = l.loadPhysical(ctx, schema, group, tables); err != nil {
+			return nil, errors.Wrapf(err, "failed to load metadata of physical tables in %s", group)
+		}
+	}
+
+	return DiffPhysicalTables(shards, metadata), nil
+}
+
+// loadPhysical loads the metadata of physical tables from given group, the cache will be skipped.
+func (l *SimpleSchemaLoader) loadPhysical(ctx context.Context, schema, group string, tables []string) (map[string]*proto.TableMetadata, error) {
+	columns, err := l.loadColumnMetadataMap(ctx, schema, group, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := l.loadIndexMetadata(ctx, schema, group, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]*proto.TableMetadata, len(columns))
+	for name, it := range columns {
+		ret[name] = proto.NewTableMetadata(name, it, indexes[name])
+	}
+	return ret, nil
+}
+
+type physicalTable struct {
+	group, table string
+	metadata     *proto.TableMetadata
+}
+
+// DiffPhysicalTables compares the metadata of physical tables. The columns and indexes which exist in
+// at least half of the physical tables are expected, the differences from them will be reported.
+func DiffPhysicalTables(shards rule.DatabaseTables, metadata map[string]map[string]*proto.TableMetadata) []*proto.SchemaDrift {
+	var (
+		drifts  []*proto.SchemaDrift
+		tables  []*physicalTable
+		columns = make(map[string]int)
+		types   = make(map[string]map[string]int)
+		indexes = make(map[string]int)
+	)
+
+	groups := make([]string, 0, len(shards))
+	for group := range shards {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		for _, table := range shards[group] {
+			md, ok := metadata[group][table]
+			if !ok {
+				drifts = append(drifts, &proto.SchemaDrift{
+					Group:  group,
+					Table:  table,
+					Kind:   proto.SchemaDriftTable,
+					Name:   table,
+					Detail: "doesn't exist",
+				})
+				continue
+			}
+			tables = append(tables, &physicalTable{group: group, table: table, metadata: md})
+			for name, column := range md.Columns {
+				columns[name]++
+				if types[name] == nil {
+					types[name] = make(map[string]int)
+				}
+				types[name][column.DataType]++
+			}
+			for name := range md.Indexes {
+				indexes[name]++
+			}
+		}
+	}
+
+	isExpected := func(cnt int) bool {
+		return cnt*2 >= len(tables)
+	}
+
+	for _, name := range sortedKeys(columns) {
+		var (
+			expected = isExpected(columns[name])
+			dataType = mostCommon(types[name])
+		)
+		for _, it := range tables {
+			column, ok := it.metadata.Columns[name]
+			var detail string
+			switch {
+			case expected && !ok:
+				detail = "is missing"
+			case !expected && ok:
+				detail = "is unexpected"
+			case ok && column.DataType != dataType:
+				detail = fmt.Sprintf("has data type %s, expected %s", column.DataType, dataType)
+			default:
+				continue
+			}
+			drifts = append(drifts, &proto.SchemaDrift{
+				Group:  it.group,
+				Table:  it.table,
+				Kind:   proto.SchemaDriftColumn,
+				Name:   name,
+				Detail: detail,
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(indexes) {
+		expected := isExpected(indexes[name])
+		for _, it := range tables {
+			_, ok := it.metadata.Indexes[name]
+			var detail string
+			switch {
+			case expected && !ok:
+				detail = "is missing"
+			case !expected && ok:
+				detail = "is unexpected"
+			default:
+				continue
+			}
+			drifts = append(drifts, &proto.SchemaDrift{
+				Group:  it.group,
+				Table:  it.table,
+				Kind:   proto.SchemaDriftIndex,
+				Name:   name,
+				Detail: detail,
+			})
+		}
+	}
+
+	return drifts
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mostCommon returns the key with max count, the smaller key wins if the counts are same.
+func mostCommon(m map[string]int) string {
+	var (
+		ret  string
+		best int
+	)
+	// iterate in the order of keys, so that the ties are resolved deterministically
+	for _, k := range sortedKeys(m) {
+		if m[k] > best {
+			ret, best = k, m[k]
+		}
+	}
+	return ret
+}
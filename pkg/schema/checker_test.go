@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema_test
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/proto/rule"
+	"github.com/arana-db/arana/pkg/schema"
+)
+
+func TestDiffPhysicalTables(t *testing.T) {
+	newTable := func(name string, types map[string]string, indexes ...string) *proto.TableMetadata {
+		var (
+			columns []*proto.ColumnMetadata
+			idxes   []*proto.IndexMetadata
+		)
+		for _, column := range []string{"id", "uid", "name"} {
+			if tp, ok := types[column]; ok {
+				columns = append(columns, &proto.ColumnMetadata{Name: column, DataType: tp})
+			}
+		}
+		for _, it := range indexes {
+			idxes = append(idxes, &proto.IndexMetadata{Name: it})
+		}
+		return proto.NewTableMetadata(name, columns, idxes)
+	}
+
+	full := map[string]string{"id": "bigint", "uid": "bigint", "name": "varchar"}
+
+	shards := rule.DatabaseTables{
+		"employees_0000": {"student_0000", "student_0001"},
+		"employees_0001": {"student_0002", "student_0003"},
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		metadata := map[string]map[string]*proto.TableMetadata{
+			"employees_0000": {
+				"student_0000": newTable("student_0000", full, "primary", "idx_uid"),
+				"student_0001": newTable("student_0001", full, "primary", "idx_uid"),
+			},
+			"employees_0001": {
+				"student_0002": newTable("student_0002", full, "primary", "idx_uid"),
+				"student_0003": newTable("student_0003", full, "primary", "idx_uid"),
+			},
+		}
+		assert.Empty(t, schema.DiffPhysicalTables(shards, metadata))
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		metadata := map[string]map[string]*proto.TableMetadata{
+			"employees_0000": {
+				"student_0000": newTable("student_0000", full, "primary", "idx_uid"),
+				"student_0001": newTable("student_0001", map[string]string{"id": "bigint", "uid": "int", "name": "varchar"}, "primary", "idx_uid"),
+			},
+			"employees_0001": {
+				"student_0002": newTable("student_0002", map[string]string{"id": "bigint", "uid": "bigint"}, "primary", "idx_uid"),
+			},
+		}
+
+		var actual []string
+		for _, it := range schema.DiffPhysicalTables(shards, metadata) {
+			actual = append(actual, it.String())
+		}
+
+		assert.Equal(t, []string{
+			"employees_0001.student_0003: table `student_0003` doesn't exist",
+			"employees_0001.student_0002: column `name` is missing",
+			"employees_0000.student_0001: column `uid` has data type int, expected bigint",
+		}, actual)
+	})
+}
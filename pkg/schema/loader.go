@@ -48,7 +48,9 @@ const (
 )
 
 func init() {
-	proto.RegisterSchemaLoader(NewSimpleSchemaLoader())
+	loader := NewSimpleSchemaLoader()
+	proto.RegisterSchemaLoader(loader)
+	proto.RegisterSchemaChecker(loader)
 }
 
 type SimpleSchemaLoader struct {
@@ -137,6 +139,10 @@ func (l *SimpleSchemaLoader) Load(ctx context.Context, schema string, tables []s
 }
 
 func (l *SimpleSchemaLoader) LoadColumnMetadataMap(ctx context.Context, schema string, tables []string) (map[string][]*proto.ColumnMetadata, error) {
+	return l.loadColumnMetadataMap(ctx, schema, "", tables)
+}
+
+func (l *SimpleSchemaLoader) loadColumnMetadataMap(ctx context.Context, schema, group string, tables []string) (map[string][]*proto.ColumnMetadata, error) {
 	conn, err := runtime.Load(schema)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -146,7 +152,7 @@ func (l *SimpleSchemaLoader) LoadColumnMetadataMap(ctx context.Context, schema s
 		ds        proto.Dataset
 	)
 
-	if resultSet, err = conn.Query(ctx, "", getColumnMetadataSQL(tables)); err != nil {
+	if resultSet, err = conn.Query(ctx, group, getColumnMetadataSQL(tables)); err != nil {
 		log.Errorf("Load ColumnMetadata error when call db: %v", err)
 		return nil, errors.WithStack(err)
 	}
@@ -215,6 +221,10 @@ func convertInterfaceToStrNullable(value proto.Value) string {
 }
 
 func (l *SimpleSchemaLoader) LoadIndexMetadata(ctx context.Context, schema string, tables []string) (map[string][]*proto.IndexMetadata, error) {
+	return l.loadIndexMetadata(ctx, schema, "", tables)
+}
+
+func (l *SimpleSchemaLoader) loadIndexMetadata(ctx context.Context, schema, group string, tables []string) (map[string][]*proto.IndexMetadata, error) {
 	conn, err := runtime.Load(schema)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -225,7 +235,7 @@ func (l *SimpleSchemaLoader) LoadIndexMetadata(ctx context.Context, schema strin
 		ds        proto.Dataset
 	)
 
-	if resultSet, err = conn.Query(ctx, "", getIndexMetadataSQL(tables)); err != nil {
+	if resultSet, err = conn.Query(ctx, group, getIndexMetadataSQL(tables)); err != nil {
 		return nil, errors.WithStack(err)
 	}
 
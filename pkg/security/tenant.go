@@ -45,11 +45,21 @@ type TenantManager interface {
 	PutCluster(tenant string, cluster string)
 	// RemoveCluster removes a cluster from tenant.
 	RemoveCluster(tenant string, cluster string)
+	// SetAllowDestructiveDDL sets whether the tenant can execute destructive DDL on sharding tables without confirmation.
+	SetAllowDestructiveDDL(tenant string, allow bool)
+	// AllowDestructiveDDL returns true if the tenant can execute destructive DDL on sharding tables without confirmation.
+	AllowDestructiveDDL(tenant string) bool
+	// SetConsistency sets the read-your-writes consistency of tenant, nil means disabled.
+	SetConsistency(tenant string, consistency *config.Consistency)
+	// Consistency returns the read-your-writes consistency of tenant, returns nil if disabled.
+	Consistency(tenant string) *config.Consistency
 }
 
 type tenantItem struct {
-	clusters map[string]struct{}
-	users    map[string]*config.User
+	clusters            map[string]struct{}
+	users               map[string]*config.User
+	allowDestructiveDDL bool
+	consistency         *config.Consistency
 }
 
 type simpleTenantManager struct {
@@ -57,6 +67,12 @@ type simpleTenantManager struct {
 	tenants map[string]*tenantItem
 }
 
+// IsAdmin returns true if the user of tenant has the administrative privileges.
+func IsAdmin(tenant, username string) bool {
+	user, ok := DefaultTenantManager().GetUser(tenant, username)
+	return ok && user.Admin
+}
+
 func (st *simpleTenantManager) GetTenants() []string {
 	st.RLock()
 	defer st.RUnlock()
@@ -157,6 +173,60 @@ func (st *simpleTenantManager) RemoveCluster(tenant string, cluster string) {
 	delete(exist.clusters, cluster)
 }
 
+func (st *simpleTenantManager) SetAllowDestructiveDDL(tenant string, allow bool) {
+	st.Lock()
+	defer st.Unlock()
+
+	current, ok := st.tenants[tenant]
+	if !ok {
+		current = &tenantItem{
+			clusters: make(map[string]struct{}),
+			users:    make(map[string]*config.User),
+		}
+		st.tenants[tenant] = current
+	}
+
+	current.allowDestructiveDDL = allow
+}
+
+func (st *simpleTenantManager) SetConsistency(tenant string, consistency *config.Consistency) {
+	st.Lock()
+	defer st.Unlock()
+
+	current, ok := st.tenants[tenant]
+	if !ok {
+		current = &tenantItem{
+			clusters: make(map[string]struct{}),
+			users:    make(map[string]*config.User),
+		}
+		st.tenants[tenant] = current
+	}
+
+	current.consistency = consistency
+}
+
+func (st *simpleTenantManager) Consistency(tenant string) *config.Consistency {
+	st.RLock()
+	defer st.RUnlock()
+
+	exist, ok := st.tenants[tenant]
+	if !ok {
+		return nil
+	}
+	return exist.consistency
+}
+
+func (st *simpleTenantManager) AllowDestructiveDDL(tenant string) bool {
+	st.RLock()
+	defer st.RUnlock()
+
+	exist, ok := st.tenants[tenant]
+	if !ok {
+		return false
+	}
+	return exist.allowDestructiveDDL
+}
+
 var (
 	_defaultTenantManager     TenantManager
 	_defaultTenantManagerOnce sync.Once
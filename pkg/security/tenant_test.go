@@ -66,6 +66,14 @@ func TestSimpleTenantManager(t *testing.T) {
 	assert.Len(t, clusters, 1)
 	assert.Equal(t, []string{"fake-cluster"}, clusters)
 
+	assert.False(t, tm.AllowDestructiveDDL("fake-tenant"))
+	tm.SetAllowDestructiveDDL("fake-tenant", true)
+	assert.True(t, tm.AllowDestructiveDDL("fake-tenant"))
+
+	assert.Nil(t, tm.Consistency("fake-tenant"))
+	tm.SetConsistency("fake-tenant", &config.Consistency{Mode: config.ConsistencyGTID})
+	assert.Equal(t, config.ConsistencyGTID, tm.Consistency("fake-tenant").Mode)
+
 	tm.RemoveUser("fake-tenant", "fake-user")
 	tm.RemoveCluster("fake-tenant", "fake-cluster")
 }
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+type leastActive struct {
+	weights []int
+	actives []int64
+}
+
+func (l leastActive) GetDataSourceNo() int {
+	var (
+		least   int64 = -1
+		ties    []int
+		weights []int
+	)
+	for _, i := range available(l.weights) {
+		switch active := l.actives[i]; {
+		case least < 0 || active < least:
+			least = active
+			ties = append(ties[:0], i)
+			weights = append(weights[:0], l.weights[i])
+		case active == least:
+			ties = append(ties, i)
+			weights = append(weights, l.weights[i])
+		}
+	}
+
+	switch len(ties) {
+	case 0:
+		return 0
+	case 1:
+		return ties[0]
+	default:
+		// select randomly by weight if there are multiple nodes with least active requests
+		return ties[NewWeightRandomSelector(weights).GetDataSourceNo()]
+	}
+}
+
+// NewLeastActiveSelector creates a selector which selects the node with least in-flight requests.
+func NewLeastActiveSelector(weights []int, actives []int64) Selector {
+	return leastActive{
+		weights: weights,
+		actives: actives,
+	}
+}
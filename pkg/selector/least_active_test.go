@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastActive(t *testing.T) {
+	assert.Equal(t, 1, NewLeastActiveSelector([]int{10, 10, 10}, []int64{3, 1, 2}).GetDataSourceNo())
+	// the node with zero weight is skipped
+	assert.Equal(t, 2, NewLeastActiveSelector([]int{10, 0, 10}, []int64{3, 1, 2}).GetDataSourceNo())
+	assert.Equal(t, 0, NewLeastActiveSelector(nil, nil).GetDataSourceNo())
+
+	// select randomly among the nodes with least active requests
+	hits := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		hits[NewLeastActiveSelector([]int{10, 10, 10}, []int64{1, 5, 1}).GetDataSourceNo()]++
+	}
+	assert.Zero(t, hits[1])
+	assert.Equal(t, 100, hits[0]+hits[2])
+}
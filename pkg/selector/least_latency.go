@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"math/rand"
+	"time"
+)
+
+type leastLatency struct {
+	weights   []int
+	latencies []time.Duration
+	actives   []int64
+}
+
+// GetDataSourceNo picks two nodes randomly by weight, then selects the one with lower cost, which avoids
+// sending all requests to the fastest node.
+func (l leastLatency) GetDataSourceNo() int {
+	candidates := available(l.weights)
+	switch len(candidates) {
+	case 0:
+		return 0
+	case 1:
+		return candidates[0]
+	}
+
+	weights := make([]int, len(candidates))
+	for i, it := range candidates {
+		weights[i] = l.weights[it]
+	}
+
+	wr := NewWeightRandomSelector(weights)
+	a, b := wr.GetDataSourceNo(), wr.GetDataSourceNo()
+	if a == b {
+		b = (a + 1 + rand.Intn(len(candidates)-1)) % len(candidates)
+	}
+
+	x, y := candidates[a], candidates[b]
+	if l.cost(y) < l.cost(x) {
+		return y
+	}
+	return x
+}
+
+// cost returns the EWMA latency multiplied by in-flight requests plus one, the node without latency is preferred.
+func (l leastLatency) cost(i int) float64 {
+	return float64(l.latencies[i]) * float64(l.actives[i]+1)
+}
+
+// NewLeastLatencySelector creates a selector which prefers the node with lower EWMA latency and less in-flight requests.
+func NewLeastLatencySelector(weights []int, latencies []time.Duration, actives []int64) Selector {
+	return leastLatency{
+		weights:   weights,
+		latencies: latencies,
+		actives:   actives,
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastLatency(t *testing.T) {
+	// two candidates are always compared
+	for i := 0; i < 20; i++ {
+		no := NewLeastLatencySelector([]int{10, 10}, []time.Duration{10 * time.Millisecond, time.Millisecond}, []int64{0, 0}).GetDataSourceNo()
+		assert.Equal(t, 1, no)
+	}
+
+	// the in-flight requests are considered
+	for i := 0; i < 20; i++ {
+		no := NewLeastLatencySelector([]int{10, 10}, []time.Duration{2 * time.Millisecond, time.Millisecond}, []int64{0, 3}).GetDataSourceNo()
+		assert.Equal(t, 0, no)
+	}
+
+	// the node without latency is preferred
+	for i := 0; i < 20; i++ {
+		no := NewLeastLatencySelector([]int{10, 10}, []time.Duration{time.Millisecond, 0}, []int64{0, 0}).GetDataSourceNo()
+		assert.Equal(t, 1, no)
+	}
+
+	// the slowest node receives less traffic
+	hits := make(map[int]int)
+	for i := 0; i < 300; i++ {
+		latencies := []time.Duration{time.Millisecond, time.Millisecond, 100 * time.Millisecond}
+		hits[NewLeastLatencySelector([]int{10, 10, 10}, latencies, []int64{0, 0, 0}).GetDataSourceNo()]++
+	}
+	assert.Zero(t, hits[2])
+
+	assert.Equal(t, 1, NewLeastLatencySelector([]int{0, 10}, []time.Duration{0, time.Second}, []int64{0, 0}).GetDataSourceNo())
+	assert.Equal(t, 0, NewLeastLatencySelector(nil, nil, nil).GetDataSourceNo())
+}
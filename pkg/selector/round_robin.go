@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"go.uber.org/atomic"
+)
+
+type roundRobin struct {
+	counter *atomic.Uint64
+	weights []int
+}
+
+func (r roundRobin) GetDataSourceNo() int {
+	candidates := available(r.weights)
+	if len(candidates) < 1 {
+		return 0
+	}
+
+	// all weights are zero, select the nodes in turn equally
+	weight := func(i int) int {
+		if w := r.weights[i]; w > 0 {
+			return w
+		}
+		return 1
+	}
+
+	var total int
+	for _, i := range candidates {
+		total += weight(i)
+	}
+
+	// interleave the nodes by rounds: each node is selected in a round if its weight is larger than the round
+	// number, so a node with weight w is selected w times in a cycle of total selections without bursts.
+	n := int((r.counter.Inc() - 1) % uint64(total))
+	for round := 0; ; round++ {
+		for _, i := range candidates {
+			if weight(i) <= round {
+				continue
+			}
+			if n == 0 {
+				return i
+			}
+			n--
+		}
+	}
+}
+
+// NewRoundRobinSelector creates a selector which selects the nodes with positive weight in turn proportional
+// to their weights, the counter should be shared by the selections of same group.
+func NewRoundRobinSelector(counter *atomic.Uint64, weights []int) Selector {
+	return roundRobin{
+		counter: counter,
+		weights: weights,
+	}
+}
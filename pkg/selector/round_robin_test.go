@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+func TestRoundRobin(t *testing.T) {
+	counter := atomic.NewUint64(0)
+
+	var selected []int
+	for i := 0; i < 6; i++ {
+		selected = append(selected, NewRoundRobinSelector(counter, []int{10, 0, 10}).GetDataSourceNo())
+	}
+	// the node with zero weight is skipped
+	assert.Equal(t, []int{0, 2, 0, 2, 0, 2}, selected)
+
+	// the nodes are selected proportional to weights
+	counter = atomic.NewUint64(0)
+	selected = selected[:0]
+	for i := 0; i < 6; i++ {
+		selected = append(selected, NewRoundRobinSelector(counter, []int{1, 3, 2}).GetDataSourceNo())
+	}
+	assert.Equal(t, []int{0, 1, 2, 1, 2, 1}, selected)
+	assert.Equal(t, 0, NewRoundRobinSelector(counter, []int{1, 3, 2}).GetDataSourceNo())
+
+	// all weights are zero
+	assert.Equal(t, 0, NewRoundRobinSelector(atomic.NewUint64(0), []int{0, 0}).GetDataSourceNo())
+	assert.Equal(t, 0, NewRoundRobinSelector(counter, nil).GetDataSourceNo())
+}
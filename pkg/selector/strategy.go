@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Strategy represents the load balance strategy of selecting nodes.
+type Strategy string
+
+const (
+	WeightRandom Strategy = "weight_random" // select randomly by weight
+	RoundRobin   Strategy = "round_robin"   // select in turn
+	LeastActive  Strategy = "least_active"  // select the one with least in-flight requests
+	LeastLatency Strategy = "least_latency" // select the one with least EWMA latency
+)
+
+// ParseStrategy parses the load balance strategy, empty string means WeightRandom.
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(strings.ToLower(strings.TrimSpace(s))); st {
+	case "":
+		return WeightRandom, nil
+	case WeightRandom, RoundRobin, LeastActive, LeastLatency:
+		return st, nil
+	default:
+		return "", errors.Errorf("invalid load balance strategy '%s'", s)
+	}
+}
+
+// available returns the indexes of positive weights, all indexes will be returned if all weights are zero.
+func available(weights []int) []int {
+	ret := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w > 0 {
+			ret = append(ret, i)
+		}
+	}
+	if len(ret) < 1 {
+		for i := range weights {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
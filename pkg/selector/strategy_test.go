@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStrategy(t *testing.T) {
+	for _, it := range []struct {
+		input  string
+		expect Strategy
+	}{
+		{"", WeightRandom},
+		{"weight_random", WeightRandom},
+		{"ROUND_ROBIN", RoundRobin},
+		{" least_active ", LeastActive},
+		{"least_latency", LeastLatency},
+	} {
+		st, err := ParseStrategy(it.input)
+		assert.NoError(t, err)
+		assert.Equal(t, it.expect, st)
+	}
+
+	_, err := ParseStrategy("fastest")
+	assert.Error(t, err)
+}
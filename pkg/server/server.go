@@ -17,6 +17,10 @@
 
 package server
 
+import (
+	"sync"
+)
+
 import (
 	"github.com/arana-db/arana/pkg/proto"
 )
@@ -40,3 +44,16 @@ func (srv *Server) Start() {
 		go l.Listen()
 	}
 }
+
+// Close closes all listeners, it blocks until the frontend connections are drained.
+func (srv *Server) Close() {
+	var wg sync.WaitGroup
+	for _, l := range srv.listeners {
+		wg.Add(1)
+		go func(l proto.Listener) {
+			defer wg.Done()
+			l.Close()
+		}(l)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package slowlog records the logical statements which take longer than the threshold,
+// along with the physical statements dispatched to backends.
+package slowlog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+// _maxSQLLength is the max length of sql in the slow log, the longer sql will be truncated.
+const _maxSQLLength = 4096
+
+var _slowLog atomic.Value // *slowLog
+
+// Config is the configuration of slow log.
+type Config struct {
+	log.RotateConfig `yaml:",inline"`
+	// Threshold is the minimal duration of slow statements.
+	Threshold time.Duration `yaml:"threshold"`
+}
+
+type slowLog struct {
+	threshold time.Duration
+	logger    *zap.Logger
+}
+
+// Init enables the slow log.
+func Init(c *Config) {
+	SetLogger(c.Threshold, log.NewRotateJSONLogger(c.RotateConfig))
+}
+
+// SetLogger enables the slow log with the given logger.
+func SetLogger(threshold time.Duration, logger *zap.Logger) {
+	_slowLog.Store(&slowLog{
+		threshold: threshold,
+		logger:    logger,
+	})
+}
+
+type keyTrace struct{}
+
+// Trace collects the physical statements of a logical statement.
+type Trace struct {
+	mu         sync.Mutex
+	statements []*statement
+}
+
+type statement struct {
+	group, node, sql string
+	start            time.Time
+	duration         time.Duration
+	done             bool
+	err              error
+}
+
+// Begin begins to trace the logical statement, the returned function should be called when the statement is done,
+// eg: the result is released, only the first call takes effect.
+func Begin(ctx *proto.Context) (context.Context, func(err error)) {
+	sl, ok := _slowLog.Load().(*slowLog)
+	if !ok {
+		return ctx.Context, func(error) {}
+	}
+
+	var (
+		start = time.Now()
+		tr    = new(Trace)
+		info  = []zap.Field{
+			zap.Uint32("connection_id", ctx.ConnectionID),
+			zap.String("tenant", ctx.Tenant),
+			zap.String("user", ctx.Username),
+			zap.String("client", ctx.RemoteAddr),
+			zap.String("schema", ctx.Schema),
+			zap.String("sql", truncate(ctx.GetQuery())),
+		}
+	)
+
+	var once sync.Once
+	return context.WithValue(ctx.Context, keyTrace{}, tr), func(err error) {
+		once.Do(func() {
+			duration := time.Since(start)
+			if duration < sl.threshold {
+				return
+			}
+			fields := append(info, log.Duration(duration))
+			if err != nil {
+				fields = append(fields, zap.String("error", err.Error()))
+			}
+			fields = append(fields, zap.Array("statements", tr))
+			sl.logger.Info("slow_query", fields...)
+		})
+	}
+}
+
+// FromContext extracts the Trace, returns nil if the slow log is disabled.
+func FromContext(ctx context.Context) *Trace {
+	tr, _ := ctx.Value(keyTrace{}).(*Trace)
+	return tr
+}
+
+// Record records a physical statement executed in the group and node,
+// the returned function should be called when the statement is done.
+func (t *Trace) Record(group, node, sql string) func(err error) {
+	if t == nil {
+		return func(error) {}
+	}
+
+	st := &statement{
+		group: group,
+		node:  node,
+		sql:   sql,
+		start: time.Now(),
+	}
+
+	t.mu.Lock()
+	t.statements = append(t.statements, st)
+	t.mu.Unlock()
+
+	return func(err error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if st.done {
+			return
+		}
+		st.done = true
+		st.duration = time.Since(st.start)
+		st.err = err
+	}
+}
+
+func (t *Trace) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, it := range t.statements {
+		if err := enc.AppendObject(it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (st *statement) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("group", st.group)
+	enc.AddString("node", st.node)
+	enc.AddString("sql", truncate(st.sql))
+	if !st.done {
+		// the result is not released yet
+		enc.AddBool("pending", true)
+		enc.AddInt64("duration_ms", time.Since(st.start).Milliseconds())
+		return nil
+	}
+	enc.AddInt64("duration_ms", st.duration.Milliseconds())
+	if st.err != nil {
+		enc.AddString("error", st.err.Error())
+	}
+	return nil
+}
+
+func truncate(sql string) string {
+	if len(sql) > _maxSQLLength {
+		return sql[:_maxSQLLength] + "..."
+	}
+	return sql
+}
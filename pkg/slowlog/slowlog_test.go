@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/proto"
+)
+
+func TestSlowLog(t *testing.T) {
+	newContext := func() *proto.Context {
+		return &proto.Context{
+			Context:      context.Background(),
+			Tenant:       "arana",
+			Schema:       "employees",
+			ConnectionID: 1,
+			Username:     "root",
+			Data:         []byte("\x03select * from student"),
+		}
+	}
+
+	// disabled
+	c, finish := Begin(newContext())
+	assert.Nil(t, FromContext(c))
+	FromContext(c).Record("employees_0000", "node0", "select 1")(nil)
+	finish(nil)
+
+	core, logs := observer.New(zap.InfoLevel)
+	SetLogger(time.Hour, zap.New(core))
+
+	c, finish = Begin(newContext())
+	assert.NotNil(t, FromContext(c))
+	finish(nil)
+	assert.Equal(t, 0, logs.Len())
+
+	SetLogger(0, zap.New(core))
+	c, finish = Begin(newContext())
+	tr := FromContext(c)
+	tr.Record("employees_0000", "node0", "select * from student_0000")(nil)
+	tr.Record("employees_0001", "node1", "select * from student_0001")(errors.New("timeout"))
+	tr.Record("employees_0002", "node2", "select * from student_0002")
+	finish(errors.New("timeout"))
+	// only the first call takes effect
+	finish(nil)
+
+	assert.Equal(t, 1, logs.Len())
+	record := logs.All()[0].ContextMap()
+	assert.IsType(t, int64(0), record["duration_ms"])
+	assert.Equal(t, "arana", record["tenant"])
+	assert.Equal(t, "root", record["user"])
+	assert.Equal(t, "select * from student", record["sql"])
+	assert.Equal(t, "timeout", record["error"])
+
+	statements := record["statements"].([]interface{})
+	assert.Len(t, statements, 3)
+	assert.Equal(t, "employees_0000", statements[0].(map[string]interface{})["group"])
+	assert.Equal(t, "node1", statements[1].(map[string]interface{})["node"])
+	assert.Equal(t, "timeout", statements[1].(map[string]interface{})["error"])
+	assert.Equal(t, true, statements[2].(map[string]interface{})["pending"])
+}
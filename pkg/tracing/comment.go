@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	_keyTraceParent = "traceparent"
+	_keyTraceState  = "tracestate"
+)
+
+var (
+	_propagator = propagation.TraceContext{}
+
+	// matches the key-value pairs of sqlcommenter, eg: traceparent='00-xxx-xxx-01'
+	_regexpComment = regexp.MustCompile(`\b(traceparent|tracestate)\s*=\s*'?([^'\s,]+)'?`)
+)
+
+// Extract extracts the W3C trace context from the leading comments of SQL, and returns a context which joins it.
+//
+// For example:
+//
+//	/*traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/ SELECT * FROM student
+func Extract(ctx context.Context, sql string) context.Context {
+	carrier := make(propagation.MapCarrier)
+
+	rest := strings.TrimSpace(sql)
+	for strings.HasPrefix(rest, "/*") {
+		end := strings.Index(rest, "*/")
+		if end == -1 {
+			break
+		}
+		for _, it := range _regexpComment.FindAllStringSubmatch(rest[2:end], -1) {
+			value, err := url.QueryUnescape(it[2])
+			if err != nil {
+				value = it[2]
+			}
+			carrier.Set(strings.ToLower(it[1]), value)
+		}
+		rest = strings.TrimSpace(rest[end+2:])
+	}
+
+	if len(carrier.Get(_keyTraceParent)) < 1 {
+		return ctx
+	}
+
+	return _propagator.Extract(ctx, carrier)
+}
+
+// Inject prepends the W3C trace context of the current span to the SQL as a comment,
+// the SQL will be returned as it is if there is no valid span.
+func Inject(ctx context.Context, sql string) string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return sql
+	}
+
+	carrier := make(propagation.MapCarrier)
+	_propagator.Inject(ctx, carrier)
+
+	traceParent := carrier.Get(_keyTraceParent)
+	if len(traceParent) < 1 {
+		return sql
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(sql) + 72)
+	sb.WriteString("/*")
+	sb.WriteString(_keyTraceParent)
+	sb.WriteString("='")
+	sb.WriteString(traceParent)
+	sb.WriteByte('\'')
+	if traceState := carrier.Get(_keyTraceState); len(traceState) > 0 {
+		sb.WriteByte(',')
+		sb.WriteString(_keyTraceState)
+		sb.WriteString("='")
+		sb.WriteString(url.QueryEscape(traceState))
+		sb.WriteByte('\'')
+	}
+	sb.WriteString("*/ ")
+	sb.WriteString(sql)
+
+	return sb.String()
+}
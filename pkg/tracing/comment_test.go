@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const _traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+func TestExtract(t *testing.T) {
+	for _, it := range []struct {
+		sql   string
+		valid bool
+	}{
+		{"/*traceparent='" + _traceParent + "'*/ SELECT * FROM student", true},
+		{"/* traceparent=" + _traceParent + " */ SELECT * FROM student", true},
+		{"  /*app='foo'*/ /*route='/users',traceparent='" + _traceParent + "',tracestate='congo%3Dt61rcWkgMzE'*/ SELECT 1", true},
+		{"SELECT * FROM student /*traceparent='" + _traceParent + "'*/", false},
+		{"/*traceparent='invalid'*/ SELECT 1", false},
+		{"/*+ MAX_EXECUTION_TIME(1000) */ SELECT 1", false},
+		{"SELECT 1", false},
+	} {
+		t.Run(it.sql, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(Extract(context.Background(), it.sql))
+			assert.Equal(t, it.valid, sc.IsValid())
+			if it.valid {
+				assert.True(t, sc.IsRemote())
+				assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", sc.TraceID().String())
+				assert.Equal(t, "b7ad6b7169203331", sc.SpanID().String())
+				assert.True(t, sc.IsSampled())
+			}
+		})
+	}
+
+	sc := trace.SpanContextFromContext(Extract(context.Background(), "/*traceparent='"+_traceParent+"',tracestate='congo%3Dt61rcWkgMzE'*/ SELECT 1"))
+	assert.Equal(t, "congo=t61rcWkgMzE", sc.TraceState().String())
+}
+
+func TestInject(t *testing.T) {
+	assert.Equal(t, "SELECT 1", Inject(context.Background(), "SELECT 1"))
+
+	ctx := Extract(context.Background(), "/*traceparent='"+_traceParent+"'*/ SELECT 1")
+	assert.Equal(t, "/*traceparent='"+_traceParent+"'*/ SELECT 1", Inject(ctx, "SELECT 1"))
+
+	ctx = Extract(context.Background(), "/*traceparent='"+_traceParent+"',tracestate='congo=t61rcWkgMzE'*/ SELECT 1")
+	injected := Inject(ctx, "SELECT 1")
+	assert.Equal(t, "/*traceparent='"+_traceParent+"',tracestate='congo%3Dt61rcWkgMzE'*/ SELECT 1", injected)
+
+	// round trip
+	assert.Equal(t, trace.SpanContextFromContext(ctx), trace.SpanContextFromContext(Extract(context.Background(), injected)))
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+import (
+	"github.com/arana-db/arana/pkg/util/log"
+)
+
+const _defaultServiceName = "arana"
+
+var (
+	_mu       sync.Mutex
+	_provider *sdktrace.TracerProvider
+)
+
+// Config represents the config of OTLP trace exporter.
+type Config struct {
+	// Endpoint is the address of OTLP gRPC collector, eg: 'localhost:4317'.
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	// Insecure disables the transport security.
+	Insecure bool `yaml:"insecure" json:"insecure"`
+	// Headers will be sent with each export request.
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// ServiceName is the service name of resource, default is 'arana'.
+	ServiceName string `yaml:"service_name" json:"service_name"`
+	// SampleRatio is the ratio of sampled root traces, default is 1.
+	SampleRatio float64 `yaml:"sample_ratio" json:"sample_ratio"`
+}
+
+// Init creates a tracer provider which exports spans to the OTLP collector, and sets it as the global one.
+func Init(c *Config) error {
+	if c == nil || len(c.Endpoint) < 1 {
+		return perrors.New("no endpoint of trace exporter specified")
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(c.Endpoint),
+	}
+	if c.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(c.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(c.Headers))
+	}
+
+	exporter, err := otlptrace.New(context.Background(), otlptracegrpc.NewClient(opts...))
+	if err != nil {
+		return perrors.Wrap(err, "failed to create trace exporter")
+	}
+
+	SetExporter(c, exporter)
+
+	log.Infof("export traces to %s", c.Endpoint)
+
+	return nil
+}
+
+// SetExporter sets the global tracer provider which exports spans by the given exporter.
+func SetExporter(c *Config, exporter sdktrace.SpanExporter) {
+	var (
+		serviceName = _defaultServiceName
+		ratio       = 1.0
+	)
+	if c != nil {
+		if len(c.ServiceName) > 0 {
+			serviceName = c.ServiceName
+		}
+		if c.SampleRatio > 0 {
+			ratio = c.SampleRatio
+		}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	_mu.Lock()
+	prev := _provider
+	_provider = provider
+	_mu.Unlock()
+
+	if prev != nil {
+		_ = prev.Shutdown(context.Background())
+	}
+}
+
+// Shutdown flushes all pending spans and stops exporting.
+func Shutdown(ctx context.Context) error {
+	_mu.Lock()
+	provider := _provider
+	_provider = nil
+	_mu.Unlock()
+
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fingerprint identifies the SQL statements of the same shape, it is shared by the firewall and the limiter.
+package fingerprint
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+import (
+	"github.com/arana-db/parser"
+)
+
+// Of returns the normalized SQL and its hex digest.
+func Of(sql string) (normalized, digest string) {
+	n, d := parser.NormalizeDigest(sql)
+	return n, d.String()
+}
+
+// Key returns the lookup key of a configured fingerprint, which is the normalized SQL or the lower-case digest.
+// The key matches either value returned by Of for the statements of the same shape.
+func Key(fingerprint string) string {
+	if IsDigest(fingerprint) {
+		return strings.ToLower(fingerprint)
+	}
+	return parser.Normalize(fingerprint)
+}
+
+// IsDigest reports whether s is a hex digest of normalized SQL.
+func IsDigest(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fingerprint
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	normalized, digest := Of("SELECT * FROM student WHERE id = 1")
+
+	assert.Equal(t, normalized, Key("select * from student where id = 42"))
+	assert.Equal(t, digest, Key(strings.ToUpper(digest)))
+}
+
+func TestIsDigest(t *testing.T) {
+	_, digest := Of("select 1")
+	assert.True(t, IsDigest(digest))
+	assert.True(t, IsDigest(strings.ToUpper(digest)))
+	assert.False(t, IsDigest(digest[1:]))
+	assert.False(t, IsDigest(strings.Repeat("z", 64)))
+	assert.False(t, IsDigest("select 1"))
+}
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"time"
 )
 
 import (
@@ -142,6 +143,58 @@ func Init(logPath string, level LogLevel) {
 	log = zapLogger.Sugar()
 }
 
+// RotateConfig is the configuration of rotating log file.
+type RotateConfig struct {
+	// Filename is the file to write logs to.
+	Filename string `json:"filename" yaml:"filename"`
+	// MaxSize is the maximum size in megabytes of the log file before it gets rotated.
+	MaxSize int `json:"max_size,omitempty" yaml:"max_size"`
+	// MaxBackups is the maximum number of old log files to retain.
+	MaxBackups int `json:"max_backups,omitempty" yaml:"max_backups"`
+	// MaxAge is the maximum number of days to retain old log files.
+	MaxAge int `json:"max_age,omitempty" yaml:"max_age"`
+	// Compress determines if the rotated log files should be compressed using gzip.
+	Compress bool `json:"compress,omitempty" yaml:"compress"`
+}
+
+// Duration returns the field of duration in milliseconds, which is shared by the JSON loggers.
+func Duration(d time.Duration) zap.Field {
+	return zap.Int64("duration_ms", d.Milliseconds())
+}
+
+// NewRotateJSONLogger creates a logger which writes JSON lines into a rotating file.
+func NewRotateJSONLogger(c RotateConfig) *zap.Logger {
+	lumberJackLogger := &lumberjack.Logger{
+		Filename:   c.Filename,
+		MaxSize:    c.MaxSize,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     c.MaxAge,
+		Compress:   c.Compress,
+	}
+	if lumberJackLogger.MaxSize < 1 {
+		lumberJackLogger.MaxSize = 10
+	}
+	if lumberJackLogger.MaxBackups < 1 {
+		lumberJackLogger.MaxBackups = 5
+	}
+	if lumberJackLogger.MaxAge < 1 {
+		lumberJackLogger.MaxAge = 30
+	}
+	syncer := zapcore.AddSync(lumberJackLogger)
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeDuration = zapcore.MillisDurationEncoder
+	encoderConfig.LevelKey = ""
+	encoderConfig.CallerKey = ""
+	encoderConfig.StacktraceKey = ""
+
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	core := zapcore.NewCore(encoder, syncer, zap.NewAtomicLevelAt(zapcore.InfoLevel))
+	return zap.New(core)
+}
+
 // SetLogger customize yourself logger.
 func SetLogger(logger Logger) {
 	log = logger
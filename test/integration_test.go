@@ -328,7 +328,7 @@ func (s *IntegrationSuite) TestDropTable() {
 	t.Skip()
 
 	// drop table  physical name != logical name  and  physical name = logical name
-	result, err := db.Exec(`DROP TABLE student,salaries`)
+	result, err := db.Exec(`/*A! confirm */ DROP TABLE student,salaries`)
 
 	assert.NoErrorf(t, err, "drop table error:%v", err)
 	affected, err := result.RowsAffected()
@@ -336,7 +336,7 @@ func (s *IntegrationSuite) TestDropTable() {
 	assert.NoErrorf(t, err, "drop table  error: %v", err)
 
 	// drop again, return error
-	result, err = db.Exec(`DROP TABLE student,salaries`)
+	result, err = db.Exec(`/*A! confirm */ DROP TABLE student,salaries`)
 	assert.Error(t, err, "drop table error: %v", err)
 	assert.Nil(t, result)
 }
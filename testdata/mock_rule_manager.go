@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/arana-db/arana/pkg/proto (interfaces: RuleManager)
+
+// Package testdata is a generated GoMock package.
+package testdata
+
+import (
+	context "context"
+	reflect "reflect"
+)
+
+import (
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRuleManager is a mock of RuleManager interface.
+type MockRuleManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleManagerMockRecorder
+}
+
+// MockRuleManagerMockRecorder is the mock recorder for MockRuleManager.
+type MockRuleManagerMockRecorder struct {
+	mock *MockRuleManager
+}
+
+// NewMockRuleManager creates a new mock instance.
+func NewMockRuleManager(ctrl *gomock.Controller) *MockRuleManager {
+	mock := &MockRuleManager{ctrl: ctrl}
+	mock.recorder = &MockRuleManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleManager) EXPECT() *MockRuleManagerMockRecorder {
+	return m.recorder
+}
+
+// CreateView mocks base method.
+func (m *MockRuleManager) CreateView(arg0 context.Context, arg1, arg2, arg3 string, arg4 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateView", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateView indicates an expected call of CreateView.
+func (mr *MockRuleManagerMockRecorder) CreateView(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateView", reflect.TypeOf((*MockRuleManager)(nil).CreateView), arg0, arg1, arg2, arg3, arg4)
+}
+
+// DropView mocks base method.
+func (m *MockRuleManager) DropView(arg0 context.Context, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropView", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropView indicates an expected call of DropView.
+func (mr *MockRuleManagerMockRecorder) DropView(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropView", reflect.TypeOf((*MockRuleManager)(nil).DropView), arg0, arg1, arg2, arg3)
+}
+
+// RenameColumn mocks base method.
+func (m *MockRuleManager) RenameColumn(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameColumn", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameColumn indicates an expected call of RenameColumn.
+func (mr *MockRuleManagerMockRecorder) RenameColumn(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameColumn", reflect.TypeOf((*MockRuleManager)(nil).RenameColumn), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RenameTable mocks base method.
+func (m *MockRuleManager) RenameTable(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTable", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameTable indicates an expected call of RenameTable.
+func (mr *MockRuleManagerMockRecorder) RenameTable(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTable", reflect.TypeOf((*MockRuleManager)(nil).RenameTable), arg0, arg1, arg2, arg3)
+}
@@ -286,6 +286,20 @@ func (mr *MockDBMockRecorder) IdleTimeout() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdleTimeout", reflect.TypeOf((*MockDB)(nil).IdleTimeout))
 }
 
+// Labels mocks base method.
+func (m *MockDB) Labels() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Labels")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Labels indicates an expected call of Labels.
+func (mr *MockDBMockRecorder) Labels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Labels", reflect.TypeOf((*MockDB)(nil).Labels))
+}
+
 // MaxCapacity mocks base method.
 func (m *MockDB) MaxCapacity() int {
 	m.ctrl.T.Helper()
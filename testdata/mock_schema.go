@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/arana-db/arana/pkg/proto (interfaces: SchemaLoader)
+// Source: github.com/arana-db/arana/pkg/proto (interfaces: SchemaLoader,SchemaChecker)
 
 // Package testdata is a generated GoMock package.
 package testdata
@@ -54,3 +54,41 @@ func (mr *MockSchemaLoaderMockRecorder) Load(arg0, arg1, arg2 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockSchemaLoader)(nil).Load), arg0, arg1, arg2)
 }
+
+// MockSchemaChecker is a mock of SchemaChecker interface.
+type MockSchemaChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaCheckerMockRecorder
+}
+
+// MockSchemaCheckerMockRecorder is the mock recorder for MockSchemaChecker.
+type MockSchemaCheckerMockRecorder struct {
+	mock *MockSchemaChecker
+}
+
+// NewMockSchemaChecker creates a new mock instance.
+func NewMockSchemaChecker(ctrl *gomock.Controller) *MockSchemaChecker {
+	mock := &MockSchemaChecker{ctrl: ctrl}
+	mock.recorder = &MockSchemaCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaChecker) EXPECT() *MockSchemaCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckTable mocks base method.
+func (m *MockSchemaChecker) CheckTable(arg0 context.Context, arg1, arg2 string) ([]*proto.SchemaDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTable", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*proto.SchemaDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTable indicates an expected call of CheckTable.
+func (mr *MockSchemaCheckerMockRecorder) CheckTable(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTable", reflect.TypeOf((*MockSchemaChecker)(nil).CheckTable), arg0, arg1, arg2)
+}
@@ -240,6 +240,8 @@ func (rp *ResourcePool) get(ctx context.Context) (resource Resource, err error)
 		select {
 		case wrapper, ok = <-rp.resources:
 		case <-ctx.Done():
+			// the timed out waits are accounted too
+			rp.recordWait(startTime)
 			return nil, ErrTimeout
 		}
 		rp.recordWait(startTime)